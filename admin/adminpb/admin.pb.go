@@ -0,0 +1,904 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: admin/adminpb/admin.proto
+
+package adminpb
+
+import (
+	soteriapb "github.com/jtejido/soteria/soteriapb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Override identifies who applies a manual override and why, for the
+// audit log.
+type Override struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Operator      string                 `protobuf:"bytes,1,opt,name=operator,proto3" json:"operator,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Override) Reset() {
+	*x = Override{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Override) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Override) ProtoMessage() {}
+
+func (x *Override) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Override.ProtoReflect.Descriptor instead.
+func (*Override) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Override) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *Override) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type Breaker struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Name   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	State  soteriapb.State        `protobuf:"varint,2,opt,name=state,proto3,enum=soteria.v1.State" json:"state,omitempty"`
+	Labels map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// the name of state if it is STATE_CUSTOM
+	CustomState   string `protobuf:"bytes,4,opt,name=custom_state,json=customState,proto3" json:"custom_state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Breaker) Reset() {
+	*x = Breaker{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Breaker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Breaker) ProtoMessage() {}
+
+func (x *Breaker) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Breaker.ProtoReflect.Descriptor instead.
+func (*Breaker) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Breaker) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Breaker) GetState() soteriapb.State {
+	if x != nil {
+		return x.State
+	}
+	return soteriapb.State(0)
+}
+
+func (x *Breaker) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Breaker) GetCustomState() string {
+	if x != nil {
+		return x.CustomState
+	}
+	return ""
+}
+
+type ListBreakersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBreakersRequest) Reset() {
+	*x = ListBreakersRequest{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBreakersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBreakersRequest) ProtoMessage() {}
+
+func (x *ListBreakersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBreakersRequest.ProtoReflect.Descriptor instead.
+func (*ListBreakersRequest) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{2}
+}
+
+type ListBreakersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Breakers      []*Breaker             `protobuf:"bytes,1,rep,name=breakers,proto3" json:"breakers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListBreakersResponse) Reset() {
+	*x = ListBreakersResponse{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListBreakersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListBreakersResponse) ProtoMessage() {}
+
+func (x *ListBreakersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListBreakersResponse.ProtoReflect.Descriptor instead.
+func (*ListBreakersResponse) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListBreakersResponse) GetBreakers() []*Breaker {
+	if x != nil {
+		return x.Breakers
+	}
+	return nil
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetStatsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Breaker       *Breaker               `protobuf:"bytes,1,opt,name=breaker,proto3" json:"breaker,omitempty"`
+	Stats         *soteriapb.Stats       `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetStatsResponse) GetBreaker() *Breaker {
+	if x != nil {
+		return x.Breaker
+	}
+	return nil
+}
+
+func (x *GetStatsResponse) GetStats() *soteriapb.Stats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+type ForceStateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// only STATE_CLOSED, STATE_OPEN and STATE_ISOLATED can be forced;
+	// forcing open isolates
+	State         soteriapb.State `protobuf:"varint,2,opt,name=state,proto3,enum=soteria.v1.State" json:"state,omitempty"`
+	Override      *Override       `protobuf:"bytes,3,opt,name=override,proto3" json:"override,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceStateRequest) Reset() {
+	*x = ForceStateRequest{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceStateRequest) ProtoMessage() {}
+
+func (x *ForceStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceStateRequest.ProtoReflect.Descriptor instead.
+func (*ForceStateRequest) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ForceStateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ForceStateRequest) GetState() soteriapb.State {
+	if x != nil {
+		return x.State
+	}
+	return soteriapb.State(0)
+}
+
+func (x *ForceStateRequest) GetOverride() *Override {
+	if x != nil {
+		return x.Override
+	}
+	return nil
+}
+
+type ForceStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Breaker       *Breaker               `protobuf:"bytes,1,opt,name=breaker,proto3" json:"breaker,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceStateResponse) Reset() {
+	*x = ForceStateResponse{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceStateResponse) ProtoMessage() {}
+
+func (x *ForceStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceStateResponse.ProtoReflect.Descriptor instead.
+func (*ForceStateResponse) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ForceStateResponse) GetBreaker() *Breaker {
+	if x != nil {
+		return x.Breaker
+	}
+	return nil
+}
+
+// UpdateSettingsRequest changes the fields that are set and keeps the
+// others, including the hooks only Go code can configure.
+type UpdateSettingsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MaxRequests     *uint32                `protobuf:"varint,2,opt,name=max_requests,json=maxRequests,proto3,oneof" json:"max_requests,omitempty"`
+	Interval        *durationpb.Duration   `protobuf:"bytes,3,opt,name=interval,proto3" json:"interval,omitempty"`
+	AlignInterval   *bool                  `protobuf:"varint,4,opt,name=align_interval,json=alignInterval,proto3,oneof" json:"align_interval,omitempty"`
+	Timeout         *durationpb.Duration   `protobuf:"bytes,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	MinimumRequests *uint32                `protobuf:"varint,6,opt,name=minimum_requests,json=minimumRequests,proto3,oneof" json:"minimum_requests,omitempty"`
+	Override        *Override              `protobuf:"bytes,7,opt,name=override,proto3" json:"override,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateSettingsRequest) Reset() {
+	*x = UpdateSettingsRequest{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSettingsRequest) ProtoMessage() {}
+
+func (x *UpdateSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSettingsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateSettingsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateSettingsRequest) GetMaxRequests() uint32 {
+	if x != nil && x.MaxRequests != nil {
+		return *x.MaxRequests
+	}
+	return 0
+}
+
+func (x *UpdateSettingsRequest) GetInterval() *durationpb.Duration {
+	if x != nil {
+		return x.Interval
+	}
+	return nil
+}
+
+func (x *UpdateSettingsRequest) GetAlignInterval() bool {
+	if x != nil && x.AlignInterval != nil {
+		return *x.AlignInterval
+	}
+	return false
+}
+
+func (x *UpdateSettingsRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+func (x *UpdateSettingsRequest) GetMinimumRequests() uint32 {
+	if x != nil && x.MinimumRequests != nil {
+		return *x.MinimumRequests
+	}
+	return 0
+}
+
+func (x *UpdateSettingsRequest) GetOverride() *Override {
+	if x != nil {
+		return x.Override
+	}
+	return nil
+}
+
+type UpdateSettingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Breaker       *Breaker               `protobuf:"bytes,1,opt,name=breaker,proto3" json:"breaker,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSettingsResponse) Reset() {
+	*x = UpdateSettingsResponse{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSettingsResponse) ProtoMessage() {}
+
+func (x *UpdateSettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSettingsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateSettingsResponse) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *UpdateSettingsResponse) GetBreaker() *Breaker {
+	if x != nil {
+		return x.Breaker
+	}
+	return nil
+}
+
+type AuditEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Breaker       string                 `protobuf:"bytes,2,opt,name=breaker,proto3" json:"breaker,omitempty"`
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Operator      string                 `protobuf:"bytes,4,opt,name=operator,proto3" json:"operator,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Error         string                 `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEntry) ProtoMessage() {}
+
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AuditEntry) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetBreaker() string {
+	if x != nil {
+		return x.Breaker
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ListAuditEntriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// all breakers if empty
+	Breaker string `protobuf:"bytes,1,opt,name=breaker,proto3" json:"breaker,omitempty"`
+	// no limit if 0
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEntriesRequest) Reset() {
+	*x = ListAuditEntriesRequest{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEntriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEntriesRequest) ProtoMessage() {}
+
+func (x *ListAuditEntriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEntriesRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditEntriesRequest) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListAuditEntriesRequest) GetBreaker() string {
+	if x != nil {
+		return x.Breaker
+	}
+	return ""
+}
+
+func (x *ListAuditEntriesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListAuditEntriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*AuditEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditEntriesResponse) Reset() {
+	*x = ListAuditEntriesResponse{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditEntriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditEntriesResponse) ProtoMessage() {}
+
+func (x *ListAuditEntriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditEntriesResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditEntriesResponse) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListAuditEntriesResponse) GetEntries() []*AuditEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+var File_admin_adminpb_admin_proto protoreflect.FileDescriptor
+
+const file_admin_adminpb_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x19admin/adminpb/admin.proto\x12\x10soteria.admin.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x17soteriapb/soteria.proto\">\n" +
+	"\bOverride\x12\x1a\n" +
+	"\boperator\x18\x01 \x01(\tR\boperator\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xe3\x01\n" +
+	"\aBreaker\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12'\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x11.soteria.v1.StateR\x05state\x12=\n" +
+	"\x06labels\x18\x03 \x03(\v2%.soteria.admin.v1.Breaker.LabelsEntryR\x06labels\x12!\n" +
+	"\fcustom_state\x18\x04 \x01(\tR\vcustomState\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x15\n" +
+	"\x13ListBreakersRequest\"M\n" +
+	"\x14ListBreakersResponse\x125\n" +
+	"\bbreakers\x18\x01 \x03(\v2\x19.soteria.admin.v1.BreakerR\bbreakers\"%\n" +
+	"\x0fGetStatsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"p\n" +
+	"\x10GetStatsResponse\x123\n" +
+	"\abreaker\x18\x01 \x01(\v2\x19.soteria.admin.v1.BreakerR\abreaker\x12'\n" +
+	"\x05stats\x18\x02 \x01(\v2\x11.soteria.v1.StatsR\x05stats\"\x88\x01\n" +
+	"\x11ForceStateRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12'\n" +
+	"\x05state\x18\x02 \x01(\x0e2\x11.soteria.v1.StateR\x05state\x126\n" +
+	"\boverride\x18\x03 \x01(\v2\x1a.soteria.admin.v1.OverrideR\boverride\"I\n" +
+	"\x12ForceStateResponse\x123\n" +
+	"\abreaker\x18\x01 \x01(\v2\x19.soteria.admin.v1.BreakerR\abreaker\"\x8c\x03\n" +
+	"\x15UpdateSettingsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12&\n" +
+	"\fmax_requests\x18\x02 \x01(\rH\x00R\vmaxRequests\x88\x01\x01\x125\n" +
+	"\binterval\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\binterval\x12*\n" +
+	"\x0ealign_interval\x18\x04 \x01(\bH\x01R\ralignInterval\x88\x01\x01\x123\n" +
+	"\atimeout\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12.\n" +
+	"\x10minimum_requests\x18\x06 \x01(\rH\x02R\x0fminimumRequests\x88\x01\x01\x126\n" +
+	"\boverride\x18\a \x01(\v2\x1a.soteria.admin.v1.OverrideR\boverrideB\x0f\n" +
+	"\r_max_requestsB\x11\n" +
+	"\x0f_align_intervalB\x13\n" +
+	"\x11_minimum_requests\"M\n" +
+	"\x16UpdateSettingsResponse\x123\n" +
+	"\abreaker\x18\x01 \x01(\v2\x19.soteria.admin.v1.BreakerR\abreaker\"\xb8\x01\n" +
+	"\n" +
+	"AuditEntry\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x18\n" +
+	"\abreaker\x18\x02 \x01(\tR\abreaker\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x1a\n" +
+	"\boperator\x18\x04 \x01(\tR\boperator\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\"I\n" +
+	"\x17ListAuditEntriesRequest\x12\x18\n" +
+	"\abreaker\x18\x01 \x01(\tR\abreaker\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"R\n" +
+	"\x18ListAuditEntriesResponse\x126\n" +
+	"\aentries\x18\x01 \x03(\v2\x1c.soteria.admin.v1.AuditEntryR\aentries2\xe2\x03\n" +
+	"\x05Admin\x12]\n" +
+	"\fListBreakers\x12%.soteria.admin.v1.ListBreakersRequest\x1a&.soteria.admin.v1.ListBreakersResponse\x12Q\n" +
+	"\bGetStats\x12!.soteria.admin.v1.GetStatsRequest\x1a\".soteria.admin.v1.GetStatsResponse\x12W\n" +
+	"\n" +
+	"ForceState\x12#.soteria.admin.v1.ForceStateRequest\x1a$.soteria.admin.v1.ForceStateResponse\x12c\n" +
+	"\x0eUpdateSettings\x12'.soteria.admin.v1.UpdateSettingsRequest\x1a(.soteria.admin.v1.UpdateSettingsResponse\x12i\n" +
+	"\x10ListAuditEntries\x12).soteria.admin.v1.ListAuditEntriesRequest\x1a*.soteria.admin.v1.ListAuditEntriesResponseB*Z(github.com/jtejido/soteria/admin/adminpbb\x06proto3"
+
+var (
+	file_admin_adminpb_admin_proto_rawDescOnce sync.Once
+	file_admin_adminpb_admin_proto_rawDescData []byte
+)
+
+func file_admin_adminpb_admin_proto_rawDescGZIP() []byte {
+	file_admin_adminpb_admin_proto_rawDescOnce.Do(func() {
+		file_admin_adminpb_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_admin_adminpb_admin_proto_rawDesc), len(file_admin_adminpb_admin_proto_rawDesc)))
+	})
+	return file_admin_adminpb_admin_proto_rawDescData
+}
+
+var file_admin_adminpb_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_admin_adminpb_admin_proto_goTypes = []any{
+	(*Override)(nil),                 // 0: soteria.admin.v1.Override
+	(*Breaker)(nil),                  // 1: soteria.admin.v1.Breaker
+	(*ListBreakersRequest)(nil),      // 2: soteria.admin.v1.ListBreakersRequest
+	(*ListBreakersResponse)(nil),     // 3: soteria.admin.v1.ListBreakersResponse
+	(*GetStatsRequest)(nil),          // 4: soteria.admin.v1.GetStatsRequest
+	(*GetStatsResponse)(nil),         // 5: soteria.admin.v1.GetStatsResponse
+	(*ForceStateRequest)(nil),        // 6: soteria.admin.v1.ForceStateRequest
+	(*ForceStateResponse)(nil),       // 7: soteria.admin.v1.ForceStateResponse
+	(*UpdateSettingsRequest)(nil),    // 8: soteria.admin.v1.UpdateSettingsRequest
+	(*UpdateSettingsResponse)(nil),   // 9: soteria.admin.v1.UpdateSettingsResponse
+	(*AuditEntry)(nil),               // 10: soteria.admin.v1.AuditEntry
+	(*ListAuditEntriesRequest)(nil),  // 11: soteria.admin.v1.ListAuditEntriesRequest
+	(*ListAuditEntriesResponse)(nil), // 12: soteria.admin.v1.ListAuditEntriesResponse
+	nil,                              // 13: soteria.admin.v1.Breaker.LabelsEntry
+	(soteriapb.State)(0),             // 14: soteria.v1.State
+	(*soteriapb.Stats)(nil),          // 15: soteria.v1.Stats
+	(*durationpb.Duration)(nil),      // 16: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),    // 17: google.protobuf.Timestamp
+}
+var file_admin_adminpb_admin_proto_depIdxs = []int32{
+	14, // 0: soteria.admin.v1.Breaker.state:type_name -> soteria.v1.State
+	13, // 1: soteria.admin.v1.Breaker.labels:type_name -> soteria.admin.v1.Breaker.LabelsEntry
+	1,  // 2: soteria.admin.v1.ListBreakersResponse.breakers:type_name -> soteria.admin.v1.Breaker
+	1,  // 3: soteria.admin.v1.GetStatsResponse.breaker:type_name -> soteria.admin.v1.Breaker
+	15, // 4: soteria.admin.v1.GetStatsResponse.stats:type_name -> soteria.v1.Stats
+	14, // 5: soteria.admin.v1.ForceStateRequest.state:type_name -> soteria.v1.State
+	0,  // 6: soteria.admin.v1.ForceStateRequest.override:type_name -> soteria.admin.v1.Override
+	1,  // 7: soteria.admin.v1.ForceStateResponse.breaker:type_name -> soteria.admin.v1.Breaker
+	16, // 8: soteria.admin.v1.UpdateSettingsRequest.interval:type_name -> google.protobuf.Duration
+	16, // 9: soteria.admin.v1.UpdateSettingsRequest.timeout:type_name -> google.protobuf.Duration
+	0,  // 10: soteria.admin.v1.UpdateSettingsRequest.override:type_name -> soteria.admin.v1.Override
+	1,  // 11: soteria.admin.v1.UpdateSettingsResponse.breaker:type_name -> soteria.admin.v1.Breaker
+	17, // 12: soteria.admin.v1.AuditEntry.time:type_name -> google.protobuf.Timestamp
+	10, // 13: soteria.admin.v1.ListAuditEntriesResponse.entries:type_name -> soteria.admin.v1.AuditEntry
+	2,  // 14: soteria.admin.v1.Admin.ListBreakers:input_type -> soteria.admin.v1.ListBreakersRequest
+	4,  // 15: soteria.admin.v1.Admin.GetStats:input_type -> soteria.admin.v1.GetStatsRequest
+	6,  // 16: soteria.admin.v1.Admin.ForceState:input_type -> soteria.admin.v1.ForceStateRequest
+	8,  // 17: soteria.admin.v1.Admin.UpdateSettings:input_type -> soteria.admin.v1.UpdateSettingsRequest
+	11, // 18: soteria.admin.v1.Admin.ListAuditEntries:input_type -> soteria.admin.v1.ListAuditEntriesRequest
+	3,  // 19: soteria.admin.v1.Admin.ListBreakers:output_type -> soteria.admin.v1.ListBreakersResponse
+	5,  // 20: soteria.admin.v1.Admin.GetStats:output_type -> soteria.admin.v1.GetStatsResponse
+	7,  // 21: soteria.admin.v1.Admin.ForceState:output_type -> soteria.admin.v1.ForceStateResponse
+	9,  // 22: soteria.admin.v1.Admin.UpdateSettings:output_type -> soteria.admin.v1.UpdateSettingsResponse
+	12, // 23: soteria.admin.v1.Admin.ListAuditEntries:output_type -> soteria.admin.v1.ListAuditEntriesResponse
+	19, // [19:24] is the sub-list for method output_type
+	14, // [14:19] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
+}
+
+func init() { file_admin_adminpb_admin_proto_init() }
+func file_admin_adminpb_admin_proto_init() {
+	if File_admin_adminpb_admin_proto != nil {
+		return
+	}
+	file_admin_adminpb_admin_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admin_adminpb_admin_proto_rawDesc), len(file_admin_adminpb_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_admin_adminpb_admin_proto_goTypes,
+		DependencyIndexes: file_admin_adminpb_admin_proto_depIdxs,
+		MessageInfos:      file_admin_adminpb_admin_proto_msgTypes,
+	}.Build()
+	File_admin_adminpb_admin_proto = out.File
+	file_admin_adminpb_admin_proto_goTypes = nil
+	file_admin_adminpb_admin_proto_depIdxs = nil
+}
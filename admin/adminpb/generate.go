@@ -0,0 +1,5 @@
+// Package adminpb holds the protobuf definition of the soteria Admin
+// service and the Go code generated from it.
+package adminpb
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative admin/adminpb/admin.proto
@@ -0,0 +1,166 @@
+// Package admin serves the Admin gRPC service of adminpb for a soteria.Registry.
+package admin
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/admin/adminpb"
+	"github.com/jtejido/soteria/soteriapb"
+)
+
+// Server implements adminpb.AdminServer on top of a Registry.
+type Server struct {
+	adminpb.UnimplementedAdminServer
+	registry *soteria.Registry
+}
+
+func NewServer(registry *soteria.Registry) *Server {
+	return &Server{registry: registry}
+}
+
+// Register registers a Server for registry on s.
+func Register(s *grpc.Server, registry *soteria.Registry) {
+	adminpb.RegisterAdminServer(s, NewServer(registry))
+}
+
+func (s *Server) ListBreakers(ctx context.Context, req *adminpb.ListBreakersRequest) (*adminpb.ListBreakersResponse, error) {
+	var resp adminpb.ListBreakersResponse
+	for _, cb := range s.registry.Breakers() {
+		resp.Breakers = append(resp.Breakers, breaker(cb))
+	}
+	return &resp, nil
+}
+
+func (s *Server) GetStats(ctx context.Context, req *adminpb.GetStatsRequest) (*adminpb.GetStatsResponse, error) {
+	cb, err := s.lookup(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	return &adminpb.GetStatsResponse{
+		Breaker: breaker(cb),
+		Stats:   soteriapb.FromStats(cb.Stats()),
+	}, nil
+}
+
+func (s *Server) ForceState(ctx context.Context, req *adminpb.ForceStateRequest) (*adminpb.ForceStateResponse, error) {
+	var state soteria.State
+	switch req.GetState() {
+	case soteriapb.State_STATE_OPEN, soteriapb.State_STATE_ISOLATED:
+		state = soteria.StateIsolated
+	case soteriapb.State_STATE_CLOSED:
+		state = soteria.StateClosed
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "cannot force state %v", req.GetState())
+	}
+
+	if err := s.registry.ForceState(req.GetName(), state, override(req.GetOverride())); err != nil {
+		return nil, statusError(req.GetName(), err)
+	}
+
+	b, err := s.current(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return &adminpb.ForceStateResponse{Breaker: b}, nil
+}
+
+func (s *Server) UpdateSettings(ctx context.Context, req *adminpb.UpdateSettingsRequest) (*adminpb.UpdateSettingsResponse, error) {
+	err := s.registry.ModifySettings(req.GetName(), func(st *soteria.Settings) {
+		if req.MaxRequests != nil {
+			st.MaxRequests = req.GetMaxRequests()
+		}
+		if req.Interval != nil {
+			st.Interval = req.GetInterval().AsDuration()
+		}
+		if req.AlignInterval != nil {
+			st.AlignInterval = req.GetAlignInterval()
+		}
+		if req.Timeout != nil {
+			st.Timeout = req.GetTimeout().AsDuration()
+		}
+		if req.MinimumRequests != nil {
+			st.MinimumRequests = req.GetMinimumRequests()
+		}
+	}, override(req.GetOverride()))
+
+	if err != nil {
+		return nil, statusError(req.GetName(), err)
+	}
+
+	b, err := s.current(req.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return &adminpb.UpdateSettingsResponse{Breaker: b}, nil
+}
+
+func (s *Server) ListAuditEntries(ctx context.Context, req *adminpb.ListAuditEntriesRequest) (*adminpb.ListAuditEntriesResponse, error) {
+	var resp adminpb.ListAuditEntriesResponse
+
+	log := s.registry.AuditLog()
+	if log == nil {
+		return &resp, nil
+	}
+
+	entries, err := log.Entries(req.GetBreaker(), int(req.GetLimit()))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &adminpb.AuditEntry{
+			Time:     timestamppb.New(e.Time),
+			Breaker:  e.Breaker,
+			Action:   e.Action,
+			Operator: e.Operator,
+			Reason:   e.Reason,
+			Error:    e.Error,
+		})
+	}
+	return &resp, nil
+}
+
+func (s *Server) lookup(name string) (*soteria.CircuitBreaker, error) {
+	cb, ok := s.registry.Get(name)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%v: %q", soteria.ErrUnknownBreaker, name)
+	}
+	return cb, nil
+}
+
+func (s *Server) current(name string) (*adminpb.Breaker, error) {
+	cb, err := s.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return breaker(cb), nil
+}
+
+func statusError(name string, err error) error {
+	if errors.Is(err, soteria.ErrUnknownBreaker) {
+		return status.Errorf(codes.NotFound, "%v: %q", soteria.ErrUnknownBreaker, name)
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func override(o *adminpb.Override) soteria.Override {
+	return soteria.Override{Operator: o.GetOperator(), Reason: o.GetReason()}
+}
+
+func breaker(cb *soteria.CircuitBreaker) *adminpb.Breaker {
+	state, custom := soteriapb.FromState(cb.State())
+	return &adminpb.Breaker{
+		Name:        cb.Name(),
+		State:       state,
+		Labels:      cb.Labels(),
+		CustomState: custom,
+	}
+}
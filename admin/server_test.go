@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/admin/adminpb"
+	"github.com/jtejido/soteria/soteriapb"
+)
+
+func newServer() (*Server, *soteria.CircuitBreaker) {
+	r := soteria.NewRegistry()
+	cb := r.GetOrCreate("db", soteria.Settings{Labels: map[string]string{"tier": "storage"}})
+	r.GetOrCreate("api", soteria.Settings{})
+	return NewServer(r), cb
+}
+
+func TestListBreakers(t *testing.T) {
+	s, _ := newServer()
+
+	resp, err := s.ListBreakers(context.Background(), &adminpb.ListBreakersRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.GetBreakers()) != 2 {
+		t.Fatalf("got %d breakers, want 2", len(resp.GetBreakers()))
+	}
+	db := resp.GetBreakers()[1]
+	if db.GetName() != "db" || db.GetState() != soteriapb.State_STATE_CLOSED || db.GetLabels()["tier"] != "storage" {
+		t.Errorf("db = %v", db)
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	s, cb := newServer()
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+
+	resp, err := s.GetStats(context.Background(), &adminpb.GetStatsRequest{Name: "db"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetStats().GetTotalSuccesses() != 1 {
+		t.Errorf("Stats = %v", resp.GetStats())
+	}
+
+	_, err = s.GetStats(context.Background(), &adminpb.GetStatsRequest{Name: "nope"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetStats = %v, want NotFound", err)
+	}
+}
+
+func TestForceState(t *testing.T) {
+	s, cb := newServer()
+
+	for _, state := range []soteriapb.State{soteriapb.State_STATE_OPEN, soteriapb.State_STATE_ISOLATED} {
+		cb.Reset()
+		resp, err := s.ForceState(context.Background(), &adminpb.ForceStateRequest{Name: "db", State: state})
+		if err != nil {
+			t.Fatalf("%v: %v", state, err)
+		}
+		if resp.GetBreaker().GetState() != soteriapb.State_STATE_ISOLATED {
+			t.Errorf("%v: State = %v, want STATE_ISOLATED", state, resp.GetBreaker().GetState())
+		}
+	}
+
+	_, err := s.ForceState(context.Background(), &adminpb.ForceStateRequest{Name: "db", State: soteriapb.State_STATE_HALF_OPEN})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ForceState(STATE_HALF_OPEN) = %v, want InvalidArgument", err)
+	}
+
+	_, err = s.ForceState(context.Background(), &adminpb.ForceStateRequest{Name: "nope", State: soteriapb.State_STATE_CLOSED})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("ForceState on an unknown breaker = %v, want NotFound", err)
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	s, cb := newServer()
+
+	_, err := s.UpdateSettings(context.Background(), &adminpb.UpdateSettingsRequest{
+		Name:    "db",
+		Timeout: durationpb.New(time.Minute),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cb.Timeout() != time.Minute {
+		t.Errorf("Timeout = %v, want 1m", cb.Timeout())
+	}
+}
@@ -0,0 +1,362 @@
+package soteria
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// BreakerStatus is the JSON representation of a CircuitBreaker served by
+// the AdminHandler.
+type BreakerStatus struct {
+	Name   string            `json:"name"`
+	State  State             `json:"state"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Stats  *Stats            `json:"stats,omitempty"`
+	Trips  *TripRate         `json:"trips,omitempty"`
+
+	// ShedPercent is the percentage of requests shed, see SetShedPercent.
+	ShedPercent float64 `json:"shed_percent,omitempty"`
+
+	Latency   *LatencyHistogram `json:"latency,omitempty"`
+	Shadows   []ShadowStats     `json:"shadows,omitempty"`
+	Anomalies *Anomalies        `json:"anomalies,omitempty"`
+}
+
+// Status returns the BreakerStatus of cb, with Stats, Trips, Latency,
+// Shadows and Anomalies if withStats is true.
+func Status(cb *CircuitBreaker, withStats bool) BreakerStatus {
+	s := BreakerStatus{
+		Name:   cb.Name(),
+		State:  cb.State(),
+		Labels: cb.Labels(),
+
+		ShedPercent: cb.ShedPercent(),
+	}
+	if withStats {
+		st := cb.Stats()
+		s.Stats = &st
+		trips := cb.TripRate()
+		s.Trips = &trips
+		latency := cb.Latencies()
+		s.Latency = &latency
+		if shadows := cb.Shadows(); len(shadows) > 0 {
+			s.Shadows = shadows
+		}
+		anomalies := cb.Anomalies()
+		s.Anomalies = &anomalies
+	}
+	return s
+}
+
+// AdminHandler serves the breakers of a Registry over HTTP, as JSON:
+//
+//	GET  /breakers                   lists the breakers
+//	GET  /breakers/NAME              returns a breaker with its stats
+//	POST /breakers/NAME/open         isolates a breaker (forces it open)
+//	POST /breakers/NAME/close        forces a breaker closed
+//	POST /breakers/NAME/reset        resets a breaker
+//	POST /breakers/NAME/reset-stats  clears the stats of a breaker, keeping its state
+//	POST /breakers/NAME/shed         sheds ?percent=P of the requests of a breaker
+//	GET  /breakers/NAME/decisions    lists the last decisions of a breaker to trip or not,
+//	                                 see Settings.DecisionHistory
+//	GET  /audit                      lists the recorded overrides, oldest first;
+//	                                 ?breaker=NAME&limit=N filter them
+//	GET  /events                     streams state changes, one JSON TraceEvent per line;
+//	                                 ?all=true streams every event
+//
+// Overrides take an operator and a reason as form values, which are
+// recorded to the AuditLog of the Registry. NAME is path escaped. Mount
+// the handler with http.StripPrefix to serve it below a prefix. /events
+// only covers the breakers registered when the stream starts.
+//
+// AdminHandler combines a StatsHandler and a ControlHandler without
+// authentication; serve them separately to expose the stats more widely
+// than the overrides.
+type AdminHandler struct {
+	stats   *StatsHandler
+	control *ControlHandler
+}
+
+func NewAdminHandler(registry *Registry) *AdminHandler {
+	return &AdminHandler{stats: NewStatsHandler(registry), control: NewControlHandler(registry, nil)}
+}
+
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if isControl(r) {
+		h.control.ServeHTTP(w, r)
+	} else {
+		h.stats.ServeHTTP(w, r)
+	}
+}
+
+// StatsHandler serves the read-only endpoints of an AdminHandler: the
+// GET /breakers, /breakers/NAME, /breakers/NAME/decisions, /audit and
+// /events endpoints.
+type StatsHandler struct {
+	registry *Registry
+}
+
+func NewStatsHandler(registry *Registry) *StatsHandler {
+	return &StatsHandler{registry: registry}
+}
+
+func (h *StatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.EscapedPath(), "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "breakers":
+		h.list(w, r)
+	case path == "events":
+		h.events(w, r)
+	case path == "audit":
+		h.audit(w, r)
+	case parts[0] == "breakers" && len(parts) == 2:
+		cb, ok := lookupBreaker(w, h.registry, parts[1])
+		if ok {
+			h.get(w, r, cb)
+		}
+	case parts[0] == "breakers" && len(parts) == 3 && parts[2] == "decisions":
+		cb, ok := lookupBreaker(w, h.registry, parts[1])
+		if ok && allowMethod(w, r, http.MethodGet) {
+			writeJSON(w, append([]TripDecision{}, cb.Decisions()...))
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Middleware wraps a handler, such as to authenticate its requests.
+type Middleware func(next http.Handler) http.Handler
+
+// ControlHandler serves the override endpoints of an AdminHandler: the
+// POST /breakers/NAME/open, /close, /reset, /reset-stats and /shed
+// endpoints.
+type ControlHandler struct {
+	registry *Registry
+	handler  http.Handler
+}
+
+// NewControlHandler returns a ControlHandler for registry whose requests go
+// through auth first, if not nil. See RequireBearerToken.
+func NewControlHandler(registry *Registry, auth Middleware) *ControlHandler {
+	h := &ControlHandler{registry: registry}
+	h.handler = http.HandlerFunc(h.serve)
+	if auth != nil {
+		h.handler = auth(h.handler)
+	}
+	return h
+}
+
+func (h *ControlHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
+
+func (h *ControlHandler) serve(w http.ResponseWriter, r *http.Request) {
+	if !isControl(r) {
+		http.NotFound(w, r)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	if cb, ok := lookupBreaker(w, h.registry, parts[1]); ok {
+		h.control(w, r, cb.Name(), parts[2])
+	}
+}
+
+// RequireBearerToken returns a Middleware letting through the requests with
+// an "Authorization: Bearer TOKEN" header for one of tokens, and answering
+// the others with 401 Unauthorized.
+func RequireBearerToken(tokens ...string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			for _, token := range tokens {
+				if ok && subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1 {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		})
+	}
+}
+
+// isControl reports whether r is for an override endpoint.
+func isControl(r *http.Request) bool {
+	parts := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	return parts[0] == "breakers" && len(parts) == 3 && parts[2] != "decisions"
+}
+
+// lookupBreaker returns the CircuitBreaker of the path escaped name, or
+// answers the request with an error.
+func lookupBreaker(w http.ResponseWriter, registry *Registry, escaped string) (*CircuitBreaker, bool) {
+	name, err := url.PathUnescape(escaped)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	cb, ok := registry.Get(name)
+	if !ok {
+		http.Error(w, ErrUnknownBreaker.Error(), http.StatusNotFound)
+	}
+	return cb, ok
+}
+
+func (h *StatsHandler) list(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	breakers := h.registry.Breakers()
+	statuses := make([]BreakerStatus, 0, len(breakers))
+	for _, cb := range breakers {
+		statuses = append(statuses, Status(cb, false))
+	}
+	writeJSON(w, statuses)
+}
+
+func (h *StatsHandler) get(w http.ResponseWriter, r *http.Request, cb *CircuitBreaker) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, Status(cb, true))
+}
+
+func (h *ControlHandler) control(w http.ResponseWriter, r *http.Request, name, action string) {
+	var do func(o Override) error
+	switch action {
+	case "open":
+		do = func(o Override) error { return h.registry.ForceState(name, StateOpen, o) }
+	case "close":
+		do = func(o Override) error { return h.registry.ForceState(name, StateClosed, o) }
+	case "reset":
+		do = func(o Override) error { return h.registry.Reset(name, o) }
+	case "reset-stats":
+		do = func(o Override) error { return h.registry.ResetStats(name, o) }
+	case "shed":
+		do = func(o Override) error {
+			percent, err := strconv.ParseFloat(r.FormValue("percent"), 64)
+			if err != nil {
+				return fmt.Errorf("invalid percent: %w", err)
+			}
+			return h.registry.Shed(name, percent, o)
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+
+	o := Override{Operator: r.FormValue("operator"), Reason: r.FormValue("reason")}
+	if err := do(o); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	cb, ok := h.registry.Get(name)
+	if !ok {
+		http.Error(w, ErrUnknownBreaker.Error(), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, Status(cb, false))
+}
+
+func (h *StatsHandler) audit(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	limit := 0
+	if v := r.FormValue("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	entries := []AuditEntry{}
+	if log := h.registry.AuditLog(); log != nil {
+		recorded, err := log.Entries(r.FormValue("breaker"), limit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		entries = append(entries, recorded...)
+	}
+	writeJSON(w, entries)
+}
+
+func (h *StatsHandler) events(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	all := r.URL.Query().Get("all") == "true"
+	events := make(chan TraceEvent, 64)
+	done := r.Context().Done()
+
+	for _, cb := range h.registry.Breakers() {
+		s := cb.SubscribeTransitions(64)
+		if all {
+			s = cb.Subscribe(64)
+		}
+		defer s.Close()
+
+		go func() {
+			for e := range s.C {
+				select {
+				case events <- e:
+				case <-done:
+				}
+			}
+		}()
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-done:
+			return
+		case e := <-events:
+			if err := enc.Encode(e); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,208 @@
+package soteria_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func newAdmin(t *testing.T) (*soteria.Registry, http.Handler) {
+	t.Helper()
+
+	r := soteria.NewRegistry()
+	r.GetOrCreate("db", soteria.Settings{})
+	r.GetOrCreate("a/b", soteria.Settings{})
+	return r, soteria.NewAdminHandler(r)
+}
+
+func serve(h http.Handler, method, target string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestAdminHandlerList(t *testing.T) {
+	_, h := newAdmin(t)
+
+	w := serve(h, http.MethodGet, "/breakers", nil)
+	var statuses []soteria.BreakerStatus
+	if err := json.NewDecoder(w.Body).Decode(&statuses); err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[0].Name != "a/b" || statuses[0].Stats != nil {
+		t.Errorf("statuses = %+v", statuses)
+	}
+}
+
+func TestAdminHandlerGet(t *testing.T) {
+	_, h := newAdmin(t)
+
+	w := serve(h, http.MethodGet, "/breakers/a%2Fb", nil)
+	var status soteria.BreakerStatus
+	if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Name != "a/b" || status.State != soteria.StateClosed || status.Stats == nil {
+		t.Errorf("status = %+v", status)
+	}
+
+	if w := serve(h, http.MethodGet, "/breakers/nope", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unknown breaker: status %d, want 404", w.Code)
+	}
+}
+
+func TestAdminHandlerControl(t *testing.T) {
+	r, h := newAdmin(t)
+	cb, _ := r.Get("db")
+
+	if w := serve(h, http.MethodGet, "/breakers/db/open", nil); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET open: status %d, want 405", w.Code)
+	}
+
+	w := serve(h, http.MethodPost, "/breakers/db/open", url.Values{"operator": {"alice"}, "reason": {"drill"}})
+	if w.Code != http.StatusOK || cb.State() != soteria.StateIsolated {
+		t.Errorf("open: status %d, State = %v", w.Code, cb.State())
+	}
+
+	serve(h, http.MethodPost, "/breakers/db/close", nil)
+	if cb.State() != soteria.StateClosed {
+		t.Errorf("close: State = %v", cb.State())
+	}
+
+	if w := serve(h, http.MethodPost, "/breakers/db/explode", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unknown action: status %d, want 404", w.Code)
+	}
+}
+
+func TestAdminHandlerEvents(t *testing.T) {
+	r, h := newAdmin(t)
+	cb, _ := r.Get("db")
+
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// the subscriptions exist once the headers are flushed
+	succeed(cb)
+	trip(cb)
+
+	line, err := bufio.NewReader(resp.Body).ReadBytes('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e soteria.TraceEvent
+	if err := json.Unmarshal(line, &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Kind != soteria.TraceTransition || e.Breaker != "db" || e.To != soteria.StateOpen {
+		t.Errorf("first event = %+v, want the trip of db", e)
+	}
+}
+
+func TestAdminHandlerEventsKeepTransitions(t *testing.T) {
+	for _, all := range []bool{false, true} {
+		r, h := newAdmin(t)
+		cb, _ := r.Get("db")
+
+		srv := httptest.NewServer(h)
+		resp, err := http.Get(fmt.Sprintf("%s/events?all=%v", srv.URL, all))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// a burst of requests overflowing the buffer of the subscription
+		for i := 0; i < 200; i++ {
+			succeed(cb)
+		}
+		trip(cb)
+
+		line, err := bufio.NewReader(resp.Body).ReadBytes('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		var e soteria.TraceEvent
+		if err := json.Unmarshal(line, &e); err != nil {
+			t.Fatal(err)
+		}
+		if request := e.Kind != soteria.TraceTransition; request != all {
+			t.Errorf("all=%v: first event = %+v, want a request event only with all", all, e)
+		}
+
+		resp.Body.Close()
+		srv.Close()
+	}
+}
+
+func TestStatsHandlerIsReadOnly(t *testing.T) {
+	r, _ := newAdmin(t)
+	h := soteria.NewStatsHandler(r)
+	cb, _ := r.Get("db")
+
+	if w := serve(h, http.MethodPost, "/breakers/db/open", nil); w.Code != http.StatusNotFound || cb.State() != soteria.StateClosed {
+		t.Errorf("open: status %d, State = %v, want 404 and closed", w.Code, cb.State())
+	}
+	if w := serve(h, http.MethodGet, "/breakers/db", nil); w.Code != http.StatusOK {
+		t.Errorf("get: status %d, want 200", w.Code)
+	}
+}
+
+func TestControlHandlerAuth(t *testing.T) {
+	r, _ := newAdmin(t)
+	h := soteria.NewControlHandler(r, soteria.RequireBearerToken("s3cret"))
+	cb, _ := r.Get("db")
+
+	w := serve(h, http.MethodPost, "/breakers/db/open", nil)
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") != "Bearer" || cb.State() != soteria.StateClosed {
+		t.Errorf("open without a token: status %d, State = %v, want 401 and closed", w.Code, cb.State())
+	}
+
+	authorized := func(method, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+	if w := authorized(http.MethodPost, "/breakers/db/open"); w.Code != http.StatusOK || cb.State() != soteria.StateIsolated {
+		t.Errorf("open with a token: status %d, State = %v, want 200 and isolated", w.Code, cb.State())
+	}
+	if w := authorized(http.MethodGet, "/breakers"); w.Code != http.StatusNotFound {
+		t.Errorf("list: status %d, want 404", w.Code)
+	}
+}
+
+func TestAdminHandlerDecisions(t *testing.T) {
+	r := soteria.NewRegistry()
+	cb := r.GetOrCreate("db", soteria.Settings{DecisionHistory: 10})
+	fail(cb)
+	h := soteria.NewAdminHandler(r)
+
+	w := serve(h, http.MethodGet, "/breakers/db/decisions", nil)
+	var decisions []soteria.TripDecision
+	if err := json.NewDecoder(w.Body).Decode(&decisions); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("GET decisions = %d, %v", w.Code, err)
+	}
+	if len(decisions) != 1 || decisions[0].Tripped || decisions[0].Stats.TotalFailures != 1 {
+		t.Errorf("decisions = %+v", decisions)
+	}
+
+	if w := serve(h, http.MethodPost, "/breakers/db/decisions", nil); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST decisions = %d", w.Code)
+	}
+}
@@ -0,0 +1,57 @@
+package soteria
+
+import "fmt"
+
+// Anomalies counts the misbehavior of a CircuitBreaker or of its hooks,
+// since New, so that it is observable rather than silently swallowed.
+type Anomalies struct {
+	// MachineErrors is the number of errors the state machine returned
+	// while accounting for requests. See Settings.OnMachineError.
+	MachineErrors uint64 `json:"machine_errors"`
+	// InvariantViolations is the number of violated internal invariants
+	// found. They are only checked if Settings.OnInvariantViolation is set.
+	InvariantViolations uint64 `json:"invariant_violations"`
+	// Panics is the number of panics recovered from OnTrace, OnRejected,
+	// OnMachineError and OnInvariantViolation.
+	Panics uint64 `json:"panics"`
+}
+
+// Total returns the number of anomalies of every kind.
+func (a Anomalies) Total() uint64 {
+	return a.MachineErrors + a.InvariantViolations + a.Panics
+}
+
+// Anomalies returns the anomalies counted by cb. Each of them is also
+// traced as a TraceAnomaly event.
+func (cb *CircuitBreaker) Anomalies() Anomalies {
+	if cb.unprotected() {
+		return Anomalies{}
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return cb.anomalies
+}
+
+// hook calls the hook named name with fn, recovering and reporting a panic.
+// cb.mutex must be held.
+func (cb *CircuitBreaker) hook(name string, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			cb.anomalies.Panics++
+			cb.anomaly(fmt.Errorf("soteria: %s panicked: %v", name, r))
+		}
+	}()
+	fn()
+}
+
+// anomaly traces err as a TraceAnomaly event, unless it happened while
+// tracing another anomaly. cb.mutex must be held.
+func (cb *CircuitBreaker) anomaly(err error) {
+	if cb.tracingAnomaly {
+		return
+	}
+
+	cb.tracingAnomaly = true
+	defer func() { cb.tracingAnomaly = false }()
+	cb.trace(TraceEvent{Time: cb.clock.Now(), Kind: TraceAnomaly, Error: err.Error()})
+}
@@ -0,0 +1,61 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestAnomaliesRecoverHookPanics(t *testing.T) {
+	var anomalies []soteria.TraceEvent
+	cb, _ := newBreaker(t, soteria.Settings{
+		OnRejected: func(err error, suppressed uint64) { panic("bug in OnRejected") },
+	})
+	s := cb.Subscribe(16)
+	defer s.Close()
+
+	trip(cb)
+	if err := succeed(cb); !errors.Is(err, soteria.ErrOpenState) {
+		t.Fatalf("Execute = %v, want the rejection despite the panic", err)
+	}
+
+	for len(s.C) > 0 {
+		if e := <-s.C; e.Kind == soteria.TraceAnomaly {
+			anomalies = append(anomalies, e)
+		}
+	}
+	if a := cb.Anomalies(); a.Panics != 1 || a.Total() != 1 {
+		t.Errorf("Anomalies = %+v, want 1 panic", a)
+	}
+	if len(anomalies) != 1 || anomalies[0].Error != "soteria: OnRejected panicked: bug in OnRejected" {
+		t.Errorf("anomaly events = %+v", anomalies)
+	}
+}
+
+func TestAnomaliesPanickingOnTrace(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{
+		OnTrace: func(e soteria.TraceEvent) { panic("bug in OnTrace") },
+	})
+
+	if err := succeed(cb); err != nil {
+		t.Fatalf("Execute = %v", err)
+	}
+	if a := cb.Anomalies(); a.Panics != 2 {
+		t.Errorf("Anomalies = %+v, want the success and its anomaly event counted once each", a)
+	}
+}
+
+func TestAnomaliesCountMachineErrors(t *testing.T) {
+	var m *testMachine
+	cb, _ := newBreaker(t, soteria.Settings{NewMachine: newTestMachine(&m)})
+	m.fail = map[soteria.Input]error{soteria.Ok: errors.New("machine bug")}
+
+	succeed(cb)
+	if a := cb.Anomalies(); a.MachineErrors != 1 || a.MachineErrors != cb.MachineErrors() {
+		t.Errorf("Anomalies = %+v, want the machine error", a)
+	}
+	if st := soteria.Status(cb, true); st.Anomalies == nil || st.Anomalies.MachineErrors != 1 {
+		t.Errorf("Status.Anomalies = %+v", st.Anomalies)
+	}
+}
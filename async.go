@@ -0,0 +1,70 @@
+package soteria
+
+import "context"
+
+// Future is the pending result of ExecuteAsync.
+type Future struct {
+	done   chan struct{}
+	result interface{}
+	err    error
+}
+
+// Done is closed once the result is available.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Get blocks until the result is available and returns it.
+func (f *Future) Get() (interface{}, error) {
+	<-f.done
+	return f.result, f.err
+}
+
+// Wait is like Get, but gives up with ctx.Err() once ctx is done.
+// The request itself keeps running and is still accounted for.
+func (f *Future) Wait(ctx context.Context) (interface{}, error) {
+	select {
+	case <-f.done:
+		return f.result, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// ExecuteAsync admits req like Execute and runs it on its own goroutine.
+// A rejection resolves the Future immediately; otherwise the outcome is
+// accounted for when req returns, before the Future resolves.
+func (cb *CircuitBreaker) ExecuteAsync(req func() (interface{}, error)) *Future {
+	f := &Future{done: make(chan struct{})}
+	if req == nil {
+		f.err = cb.errNilRequest()
+		close(f.done)
+		return f
+	}
+	if cb.unprotected() {
+		go func() {
+			defer close(f.done)
+			f.result, f.err = req()
+		}()
+		return f
+	}
+
+	t, err := cb.beforeRequest(context.Background())
+	if err != nil {
+		f.err = t.mapError(err)
+		close(f.done)
+		return f
+	}
+
+	go func() {
+		defer close(f.done)
+		defer cb.settlePanic(t)
+
+		var err error
+		f.result, err = req()
+		cb.afterRequest(t, t.outcomeOf(err), err)
+		f.err = t.mapError(err)
+	}()
+
+	return f
+}
@@ -0,0 +1,78 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestExecuteAsync(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	release := make(chan struct{})
+	f := cb.ExecuteAsync(func() (interface{}, error) {
+		<-release
+		return "done", nil
+	})
+
+	select {
+	case <-f.Done():
+		t.Fatal("resolved before the request returned")
+	default:
+	}
+
+	close(release)
+	got, err := f.Get()
+	if err != nil || got != "done" {
+		t.Errorf("Get = %v, %v", got, err)
+	}
+	if cb.Stats().TotalSuccesses != 1 {
+		t.Error("the outcome was not accounted for before the Future resolved")
+	}
+}
+
+func TestExecuteAsyncRejected(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	trip(cb)
+
+	f := cb.ExecuteAsync(func() (interface{}, error) {
+		t.Error("rejected request ran")
+		return nil, nil
+	})
+
+	select {
+	case <-f.Done():
+	default:
+		t.Fatal("rejection did not resolve the Future immediately")
+	}
+	if _, err := f.Get(); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Get = %v, want ErrOpenState", err)
+	}
+}
+
+func TestFutureWait(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	release := make(chan struct{})
+	f := cb.ExecuteAsync(func() (interface{}, error) {
+		<-release
+		return nil, errFail
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.Wait(ctx); err != context.Canceled {
+		t.Errorf("Wait = %v, want context.Canceled", err)
+	}
+
+	// the request is still accounted for after the caller gave up
+	close(release)
+	if _, err := f.Wait(context.Background()); err != errFail {
+		t.Errorf("Wait = %v, want errFail", err)
+	}
+	if cb.Stats().TotalFailures != 1 {
+		t.Error("the abandoned request was not accounted for")
+	}
+}
@@ -0,0 +1,143 @@
+package soteria
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// Audited actions.
+const (
+	ActionForceOpen      = "force-open"
+	ActionForceClose     = "force-close"
+	ActionReset          = "reset"
+	ActionResetStats     = "reset-stats"
+	ActionShed           = "shed"
+	ActionUpdateSettings = "update-settings"
+	ActionDisable        = "disable"
+	ActionEnable         = "enable"
+)
+
+// AuditEntry records a manual override of a CircuitBreaker.
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Breaker  string    `json:"breaker"`
+	Action   string    `json:"action"`
+	Operator string    `json:"operator,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// AuditLog stores AuditEntries.
+type AuditLog interface {
+	Record(e AuditEntry) error
+	// Entries returns up to limit of the most recent entries, oldest first,
+	// for breaker or for all breakers if breaker is empty.
+	// A limit of 0 or less means no limit.
+	Entries(breaker string, limit int) ([]AuditEntry, error)
+}
+
+const defaultAuditCapacity = 1000
+
+// MemoryAuditLog keeps the most recent entries in memory.
+type MemoryAuditLog struct {
+	mutex    sync.Mutex
+	capacity int
+	entries  []AuditEntry
+}
+
+// NewMemoryAuditLog returns a MemoryAuditLog keeping up to capacity
+// entries. If capacity is 0 or less, 1000 entries are kept.
+func NewMemoryAuditLog(capacity int) *MemoryAuditLog {
+	if capacity <= 0 {
+		capacity = defaultAuditCapacity
+	}
+	return &MemoryAuditLog{capacity: capacity}
+}
+
+func (l *MemoryAuditLog) Record(e AuditEntry) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.entries = append(l.entries, e)
+	if over := len(l.entries) - l.capacity; over > 0 {
+		l.entries = append(l.entries[:0], l.entries[over:]...)
+	}
+	return nil
+}
+
+func (l *MemoryAuditLog) Entries(breaker string, limit int) ([]AuditEntry, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	var entries []AuditEntry
+	for i := len(l.entries) - 1; i >= 0 && (limit <= 0 || len(entries) < limit); i-- {
+		if breaker == "" || l.entries[i].Breaker == breaker {
+			entries = append(entries, l.entries[i])
+		}
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
+
+// FileAuditLog appends every entry as a line of JSON to a file, and keeps
+// the most recent ones, including those already in the file when it was
+// opened, in memory for Entries.
+type FileAuditLog struct {
+	mutex  sync.Mutex
+	file   *os.File
+	recent *MemoryAuditLog
+}
+
+// OpenFileAuditLog opens or creates the audit log at path.
+func OpenFileAuditLog(path string) (*FileAuditLog, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &FileAuditLog{file: file, recent: NewMemoryAuditLog(0)}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err == nil {
+			l.recent.Record(e)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *FileAuditLog) Record(e AuditEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return l.recent.Record(e)
+}
+
+func (l *FileAuditLog) Entries(breaker string, limit int) ([]AuditEntry, error) {
+	return l.recent.Entries(breaker, limit)
+}
+
+func (l *FileAuditLog) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.file.Close()
+}
@@ -0,0 +1,91 @@
+package soteria_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestMemoryAuditLog(t *testing.T) {
+	l := soteria.NewMemoryAuditLog(3)
+	for _, name := range []string{"a", "b", "a", "c", "a"} {
+		l.Record(soteria.AuditEntry{Breaker: name, Action: soteria.ActionReset})
+	}
+
+	all, _ := l.Entries("", 0)
+	if len(all) != 3 || all[0].Breaker != "a" || all[1].Breaker != "c" || all[2].Breaker != "a" {
+		t.Errorf("Entries = %+v, want the 3 most recent, oldest first", all)
+	}
+
+	if a, _ := l.Entries("a", 1); len(a) != 1 || a[0].Breaker != "a" {
+		t.Errorf("Entries(a, 1) = %+v", a)
+	}
+}
+
+func TestRegistryRecordsOverrides(t *testing.T) {
+	r := soteria.NewRegistry()
+	r.GetOrCreate("db", soteria.Settings{})
+	l := soteria.NewMemoryAuditLog(0)
+	r.SetAuditLog(l)
+
+	o := soteria.Override{Operator: "alice", Reason: "drill"}
+	r.ForceState("db", soteria.StateOpen, o)
+	r.Reset("db", o)
+	r.ForceState("db", soteria.StateHalfOpen, o)
+
+	entries, _ := l.Entries("db", 0)
+	if len(entries) != 2 {
+		t.Fatalf("Entries = %+v, want the two applied overrides", entries)
+	}
+	if e := entries[0]; e.Action != soteria.ActionForceOpen || e.Operator != "alice" || e.Reason != "drill" || e.Time.IsZero() {
+		t.Errorf("first entry = %+v", e)
+	}
+	if entries[1].Action != soteria.ActionReset {
+		t.Errorf("second entry = %+v", entries[1])
+	}
+}
+
+type failingAuditLog struct{ soteria.AuditLog }
+
+func (failingAuditLog) Record(soteria.AuditEntry) error {
+	return errFail
+}
+
+func TestRegistryReportsAuditErrors(t *testing.T) {
+	r := soteria.NewRegistry()
+	cb := r.GetOrCreate("db", soteria.Settings{})
+	r.SetAuditLog(failingAuditLog{})
+
+	if err := r.ForceState("db", soteria.StateOpen, soteria.Override{}); err != errFail {
+		t.Errorf("ForceState = %v, want the audit error", err)
+	}
+	if cb.State() != soteria.StateIsolated {
+		t.Errorf("State = %v, want the override applied anyway", cb.State())
+	}
+}
+
+func TestFileAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	l, err := soteria.OpenFileAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Record(soteria.AuditEntry{Breaker: "db", Action: soteria.ActionForceOpen, Operator: "alice"})
+	l.Record(soteria.AuditEntry{Breaker: "db", Action: soteria.ActionReset, Operator: "bob"})
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := soteria.OpenFileAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reopened.Close()
+
+	entries, _ := reopened.Entries("db", 0)
+	if len(entries) != 2 || entries[0].Operator != "alice" || entries[1].Operator != "bob" {
+		t.Errorf("Entries after reopening = %+v", entries)
+	}
+}
@@ -0,0 +1,126 @@
+package soteria
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Weights of the states in a Balancer.
+const (
+	closedWeight   = 1
+	halfOpenWeight = 0.1
+)
+
+// BalancerTarget is a target of a Balancer with the CircuitBreaker
+// guarding it.
+type BalancerTarget[T any] struct {
+	Target  T
+	Breaker *CircuitBreaker
+}
+
+// Balancer spreads requests over targets, such as the replicas of a
+// service, according to the health their CircuitBreakers see, so that
+// client-side load balancing and circuit breaking share one source of
+// truth. A target is picked at random, weighted by its state, a closed
+// target getting ten times the traffic of a half-open one and an open or
+// isolated one none, and by its median latency over the last minute or
+// two relative to the fastest target.
+type Balancer[T any] struct {
+	targets []BalancerTarget[T]
+	random  *random
+}
+
+func NewBalancer[T any](targets ...BalancerTarget[T]) *Balancer[T] {
+	return &Balancer[T]{targets: append([]BalancerTarget[T](nil), targets...)}
+}
+
+// SetRand makes b pick targets from src, so that its picks can be
+// reproduced in tests and simulations. If src is nil, the global source of
+// math/rand is used. SetRand must be called before b is used.
+func (b *Balancer[T]) SetRand(src rand.Source) {
+	b.random = newRandom(src)
+}
+
+// Targets returns the targets of b.
+func (b *Balancer[T]) Targets() []BalancerTarget[T] {
+	return append([]BalancerTarget[T](nil), b.targets...)
+}
+
+// Pick returns a target. If every target is open or isolated, it returns an
+// *OpenStateError with the shortest remaining open time, or ErrIsolated if
+// they all are isolated.
+func (b *Balancer[T]) Pick() (BalancerTarget[T], error) {
+	weights := make([]float64, len(b.targets))
+	latencies := make([]time.Duration, len(b.targets))
+	var fastest time.Duration
+	for i, t := range b.targets {
+		if latency, n := t.Breaker.latency(0.5); n > 0 {
+			latencies[i] = latency
+			if fastest == 0 || latency < fastest {
+				fastest = latency
+			}
+		}
+	}
+
+	var (
+		total     float64
+		open      bool
+		remaining time.Duration
+	)
+	for i, t := range b.targets {
+		var w float64
+		switch t.Breaker.State() {
+		case StateClosed:
+			w = closedWeight
+		case StateHalfOpen:
+			w = halfOpenWeight
+		case StateOpen:
+			if r := t.Breaker.RemainingOpenTime(); !open || r < remaining {
+				remaining = r
+			}
+			open = true
+			continue
+		case StateIsolated:
+			continue
+		default: // custom states admit requests as they see fit
+			w = closedWeight
+		}
+
+		if latencies[i] > 0 {
+			w *= float64(fastest) / float64(latencies[i])
+		}
+		weights[i] = w
+		total += w
+	}
+
+	if total == 0 {
+		if !open {
+			return BalancerTarget[T]{}, ErrIsolated
+		}
+		return BalancerTarget[T]{}, &OpenStateError{Remaining: remaining}
+	}
+
+	pick := b.random.Float64() * total
+	for i, w := range weights {
+		if pick -= w; pick < 0 && w > 0 {
+			return b.targets[i], nil
+		}
+	}
+	for i := len(weights) - 1; ; i-- {
+		if weights[i] > 0 {
+			return b.targets[i], nil
+		}
+	}
+}
+
+// Execute runs req on a target picked by Pick, through its CircuitBreaker.
+func (b *Balancer[T]) Execute(ctx context.Context, req func(ctx context.Context, target T) (interface{}, error)) (interface{}, error) {
+	t, err := b.Pick()
+	if err != nil {
+		return nil, err
+	}
+	return t.Breaker.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		return req(ctx, t.Target)
+	})
+}
@@ -0,0 +1,88 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func picks(t *testing.T, b *soteria.Balancer[string], n int) map[string]int {
+	t.Helper()
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		target, err := b.Pick()
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[target.Target]++
+	}
+	return counts
+}
+
+func TestBalancerWeighsStates(t *testing.T) {
+	closed, _ := newBreaker(t, soteria.Settings{})
+	halfOpen, clock := newBreaker(t, soteria.Settings{})
+	open, _ := newBreaker(t, soteria.Settings{})
+	soteriatest.Trip(t, halfOpen, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, halfOpen)
+	soteriatest.Trip(t, open, 10)
+
+	b := soteria.NewBalancer(
+		soteria.BalancerTarget[string]{Target: "a", Breaker: closed},
+		soteria.BalancerTarget[string]{Target: "b", Breaker: halfOpen},
+		soteria.BalancerTarget[string]{Target: "c", Breaker: open},
+	)
+
+	counts := picks(t, b, 11000)
+	if counts["c"] != 0 || counts["b"] < 500 || counts["b"] > 1500 {
+		t.Errorf("picks = %v, want about 10000 a, 1000 b and no c", counts)
+	}
+}
+
+func TestBalancerWeighsLatency(t *testing.T) {
+	fast, fastClock := newBreaker(t, soteria.Settings{})
+	slowBreaker, slowClock := newBreaker(t, soteria.Settings{})
+	for i := 0; i < 10; i++ {
+		slow(fast, fastClock, 10*time.Millisecond)
+		slow(slowBreaker, slowClock, 100*time.Millisecond)
+	}
+
+	b := soteria.NewBalancer(
+		soteria.BalancerTarget[string]{Target: "fast", Breaker: fast},
+		soteria.BalancerTarget[string]{Target: "slow", Breaker: slowBreaker},
+	)
+
+	counts := picks(t, b, 11000)
+	if counts["slow"] < 500 || counts["slow"] > 1500 {
+		t.Errorf("picks = %v, want about 10000 fast and 1000 slow", counts)
+	}
+}
+
+func TestBalancerAllOpen(t *testing.T) {
+	open, _ := newBreaker(t, soteria.Settings{Timeout: time.Minute})
+	isolated, _ := newBreaker(t, soteria.Settings{})
+	soteriatest.Trip(t, open, 10)
+	isolated.ForceOpen()
+
+	b := soteria.NewBalancer(soteria.BalancerTarget[string]{Target: "b", Breaker: isolated})
+	if _, err := b.Pick(); err != soteria.ErrIsolated {
+		t.Errorf("Pick with every target isolated = %v, want ErrIsolated", err)
+	}
+
+	b = soteria.NewBalancer(
+		soteria.BalancerTarget[string]{Target: "a", Breaker: open},
+		soteria.BalancerTarget[string]{Target: "b", Breaker: isolated},
+	)
+	_, err := b.Execute(context.Background(), func(ctx context.Context, target string) (interface{}, error) {
+		t.Errorf("request sent to %s", target)
+		return nil, nil
+	})
+	var openErr *soteria.OpenStateError
+	if !errors.As(err, &openErr) || openErr.Remaining != time.Minute {
+		t.Errorf("Execute with every target open = %v, want an OpenStateError", err)
+	}
+}
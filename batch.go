@@ -0,0 +1,83 @@
+package soteria
+
+import "context"
+
+// BatchPolicy configures how ExecuteBatch accounts for a batch.
+//
+// PerItem counts every item as a request of its own: items are admitted
+// one by one, so once the CircuitBreaker opens the remaining items are
+// rejected.
+//
+// Otherwise the whole batch is admitted and counted as one request, which
+// fails when more than FailureRatio of its items failed. If FailureRatio
+// is 0, a single failed item fails the batch.
+type BatchPolicy struct {
+	PerItem      bool
+	FailureRatio float64
+}
+
+// BatchResult holds the result and error of every item, by index.
+type BatchResult struct {
+	Results []interface{}
+	Errors  []error
+}
+
+// Failed returns the number of items that returned or were rejected with an error.
+func (r *BatchResult) Failed() int {
+	n := 0
+	for _, err := range r.Errors {
+		if err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// ExecuteBatch runs fn for every item, in order, under the CircuitBreaker.
+// With aggregate accounting, a rejection of the batch is returned as the
+// error and no item is run. Errors of individual items, including per-item
+// rejections, are reported in the BatchResult only.
+func (cb *CircuitBreaker) ExecuteBatch(items []interface{}, fn func(item interface{}) (interface{}, error), policy BatchPolicy) (*BatchResult, error) {
+	r := &BatchResult{
+		Results: make([]interface{}, len(items)),
+		Errors:  make([]error, len(items)),
+	}
+
+	if policy.PerItem || cb.unprotected() {
+		for i, item := range items {
+			r.Results[i], r.Errors[i] = cb.Execute(func() (interface{}, error) {
+				return fn(item)
+			})
+		}
+		return r, nil
+	}
+
+	t, err := cb.beforeRequest(context.Background())
+	if err != nil {
+		return nil, t.mapError(err)
+	}
+
+	defer cb.settlePanic(t)
+
+	var (
+		failed int
+		first  error
+	)
+	for i, item := range items {
+		r.Results[i], r.Errors[i] = fn(item)
+		if !t.isSuccessful(r.Errors[i]) {
+			if failed == 0 {
+				first = r.Errors[i]
+			}
+			failed++
+		}
+	}
+
+	outcome := outcomeSuccess
+	if failed > 0 && float64(failed)/float64(len(items)) > policy.FailureRatio {
+		outcome = outcomeFailure
+	}
+
+	cb.afterRequest(t, outcome, first)
+	return r, nil
+}
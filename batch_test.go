@@ -0,0 +1,93 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+// items returns n items, those listed in failing failing when run by runItem.
+func items(n int, failing ...int) []interface{} {
+	items := make([]interface{}, n)
+	for i := range items {
+		items[i] = false
+	}
+	for _, i := range failing {
+		items[i] = true
+	}
+	return items
+}
+
+func runItem(item interface{}) (interface{}, error) {
+	if item.(bool) {
+		return nil, errFail
+	}
+	return "ok", nil
+}
+
+func TestExecuteBatchPerItem(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	r, err := cb.ExecuteBatch(items(10, 0, 1, 2, 3, 4, 5), runItem, soteria.BatchPolicy{PerItem: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the sixth consecutive failure trips the breaker
+	if r.Failed() != 10 {
+		t.Errorf("Failed = %d, want 10", r.Failed())
+	}
+	for i := 6; i < 10; i++ {
+		if !errors.Is(r.Errors[i], soteria.ErrOpenState) {
+			t.Errorf("Errors[%d] = %v, want ErrOpenState", i, r.Errors[i])
+		}
+	}
+	if st := cb.Stats(); st.Requests != 0 || cb.State() != soteria.StateOpen {
+		t.Errorf("State = %v, Stats = %+v", cb.State(), st)
+	}
+}
+
+func TestExecuteBatchAggregate(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	r, err := cb.ExecuteBatch(items(4, 1), runItem, soteria.BatchPolicy{FailureRatio: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Failed() != 1 || r.Results[0] != "ok" || r.Errors[1] != errFail {
+		t.Errorf("BatchResult = %+v", r)
+	}
+
+	if _, err := cb.ExecuteBatch(items(4, 0, 1, 2), runItem, soteria.BatchPolicy{FailureRatio: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+
+	st := cb.Stats()
+	if st.Requests != 2 || st.TotalSuccesses != 1 || st.TotalFailures != 1 {
+		t.Errorf("Stats = %+v, want one successful and one failed batch", st)
+	}
+}
+
+func TestExecuteBatchAggregateZeroRatio(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	cb.ExecuteBatch(items(100, 7), runItem, soteria.BatchPolicy{})
+
+	if got := cb.Stats().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %d, want a single failed item to fail the batch", got)
+	}
+}
+
+func TestExecuteBatchAggregateRejected(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	trip(cb)
+
+	ran := false
+	r, err := cb.ExecuteBatch(items(3), func(item interface{}) (interface{}, error) {
+		ran = true
+		return nil, nil
+	}, soteria.BatchPolicy{})
+	if !errors.Is(err, soteria.ErrOpenState) || r != nil || ran {
+		t.Errorf("ExecuteBatch = %v, %v; ran %v", r, err, ran)
+	}
+}
@@ -0,0 +1,116 @@
+package soteria
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ResetAll resets every registered CircuitBreaker. See ResetMatching.
+func (r *Registry) ResetAll(o Override) ([]string, error) {
+	return r.ResetMatching("*", o)
+}
+
+// ResetMatching resets the CircuitBreakers whose name matches pattern, in
+// which * matches any run of characters, / included, and ? any single
+// character, such as "*/eu-west-1*". Each reset is recorded as for a single
+// CircuitBreaker. It returns the names reset, and the errors of all the
+// resets that failed; the other bulk operations do the same.
+func (r *Registry) ResetMatching(pattern string, o Override) ([]string, error) {
+	return r.matching(pattern, func(name string) error {
+		return r.Reset(name, o)
+	})
+}
+
+// ForceOpenMatching isolates the CircuitBreakers matching pattern, such as
+// all those to a datacenter that just went dark.
+func (r *Registry) ForceOpenMatching(pattern string, o Override) ([]string, error) {
+	return r.matching(pattern, func(name string) error {
+		return r.ForceState(name, StateOpen, o)
+	})
+}
+
+// DisableMatching disables the CircuitBreakers matching pattern: they let
+// every request through without counting it, as during a MaintenanceObserve
+// window, until EnableMatching.
+func (r *Registry) DisableMatching(pattern string, o Override) ([]string, error) {
+	return r.matching(pattern, func(name string) error {
+		return r.override(name, ActionDisable, o, func(cb *CircuitBreaker) error {
+			cb.ModifySettings(func(settings *Settings) {
+				settings.Maintenance = append([]MaintenanceWindow{{Schedule: disabled{}, Mode: MaintenanceObserve}}, withoutDisabled(settings.Maintenance)...)
+			})
+			return nil
+		})
+	})
+}
+
+// EnableMatching enables again the CircuitBreakers matching pattern
+// disabled by DisableMatching.
+func (r *Registry) EnableMatching(pattern string, o Override) ([]string, error) {
+	return r.matching(pattern, func(name string) error {
+		return r.override(name, ActionEnable, o, func(cb *CircuitBreaker) error {
+			cb.ModifySettings(func(settings *Settings) {
+				settings.Maintenance = withoutDisabled(settings.Maintenance)
+			})
+			return nil
+		})
+	})
+}
+
+func (r *Registry) matching(pattern string, do func(name string) error) ([]string, error) {
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		names []string
+		errs  []error
+	)
+	for _, name := range r.Names() {
+		if !re.MatchString(name) {
+			continue
+		}
+		if err := do(name); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, errors.Join(errs...)
+}
+
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, c := range pattern {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// disabled is the Schedule of the window added by DisableMatching.
+type disabled struct{}
+
+func (disabled) Contains(t time.Time) bool {
+	return true
+}
+
+func withoutDisabled(windows []MaintenanceWindow) []MaintenanceWindow {
+	var kept []MaintenanceWindow
+	for _, w := range windows {
+		if _, ok := w.Schedule.(disabled); !ok {
+			kept = append(kept, w)
+		}
+	}
+	return kept
+}
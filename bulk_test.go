@@ -0,0 +1,86 @@
+package soteria_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func newBulkRegistry() *soteria.Registry {
+	r := soteria.NewRegistry()
+	for _, name := range []string{"db/eu-west-1a", "db/us-east-1a", "api/eu-west-1b", "cache"} {
+		r.GetOrCreate(name, soteria.Settings{})
+	}
+	r.SetAuditLog(soteria.NewMemoryAuditLog(16))
+	return r
+}
+
+func TestForceOpenMatching(t *testing.T) {
+	r := newBulkRegistry()
+
+	names, err := r.ForceOpenMatching("*/eu-west-1?", soteria.Override{Operator: "oncall"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"api/eu-west-1b", "db/eu-west-1a"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ForceOpenMatching = %v, want %v", names, want)
+	}
+	for _, cb := range r.Breakers() {
+		want := soteria.StateClosed
+		if cb.Name() == "api/eu-west-1b" || cb.Name() == "db/eu-west-1a" {
+			want = soteria.StateIsolated
+		}
+		if cb.State() != want {
+			t.Errorf("%s is %v, want %v", cb.Name(), cb.State(), want)
+		}
+	}
+
+	entries, _ := r.AuditLog().Entries("", 0)
+	if len(entries) != 2 || entries[0].Action != soteria.ActionForceOpen || entries[0].Operator != "oncall" {
+		t.Errorf("audit entries = %+v", entries)
+	}
+
+	names, err = r.ResetAll(soteria.Override{})
+	if err != nil || len(names) != 4 {
+		t.Errorf("ResetAll = %v, %v", names, err)
+	}
+	for _, cb := range r.Breakers() {
+		soteriatest.AssertClosed(t, cb)
+	}
+}
+
+func TestDisableMatching(t *testing.T) {
+	r := newBulkRegistry()
+
+	names, err := r.DisableMatching("db/*", soteria.Override{})
+	if err != nil || len(names) != 2 {
+		t.Fatalf("DisableMatching = %v, %v", names, err)
+	}
+	db, _ := r.Get("db/eu-west-1a")
+	for i := 0; i < 10; i++ {
+		fail(db)
+	}
+	soteriatest.AssertClosed(t, db)
+	if s := db.Stats(); s.Requests != 0 {
+		t.Errorf("Stats of a disabled breaker = %+v, want nothing counted", s)
+	}
+
+	if _, err := r.EnableMatching("db/*", soteria.Override{}); err != nil {
+		t.Fatal(err)
+	}
+	soteriatest.Trip(t, db, 10)
+
+	entries, _ := r.AuditLog().Entries("db/eu-west-1a", 0)
+	if len(entries) != 2 || entries[0].Action != soteria.ActionDisable || entries[1].Action != soteria.ActionEnable {
+		t.Errorf("audit entries = %+v", entries)
+	}
+}
+
+func TestMatchingNothing(t *testing.T) {
+	r := newBulkRegistry()
+	if names, err := r.ForceOpenMatching("queue*", soteria.Override{}); names != nil || err != nil {
+		t.Errorf("ForceOpenMatching = %v, %v, want nothing", names, err)
+	}
+}
@@ -0,0 +1,40 @@
+package soteria
+
+// Category is the kind of a failed request.
+type Category string
+
+const (
+	CategoryTimeout    Category = "timeout"
+	CategoryConnection Category = "connection"
+	CategoryDNS        Category = "dns"
+	CategoryTLS        Category = "tls"
+	CategoryServer     Category = "5xx"
+	CategoryOther      Category = "other"
+)
+
+// Classifier returns the Category of the failure err stands for, or ""
+// if err does not count as a failure.
+type Classifier func(err error) Category
+
+// IsSuccessful can be used as Settings.IsSuccessful. A nil error, or an
+// error c does not categorize, is a success.
+func (c Classifier) IsSuccessful(err error) bool {
+	return err == nil || c(err) == ""
+}
+
+// Or categorizes err with c, falling back to o when c does not categorize it.
+func (c Classifier) Or(o Classifier) Classifier {
+	return func(err error) Category {
+		if category := c(err); category != "" {
+			return category
+		}
+		return o(err)
+	}
+}
+
+// DefaultClassifier categorizes network failures with NetClassifier,
+// server error responses with StatusCategory, and anything else as
+// CategoryOther.
+var DefaultClassifier = NetClassifier.Or(StatusCategory).Or(func(error) Category {
+	return CategoryOther
+})
@@ -0,0 +1,80 @@
+package soteria_test
+
+import (
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func failWith(cb *soteria.CircuitBreaker, err error) {
+	cb.Execute(func() (interface{}, error) {
+		return nil, err
+	})
+}
+
+func TestStatsFailuresByCategory(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	failWith(cb, syscall.ECONNREFUSED)
+	failWith(cb, &soteria.StatusError{Code: 503})
+	failWith(cb, &soteria.StatusError{Code: 503})
+	failWith(cb, errFail)
+
+	got := cb.Stats().FailuresByCategory
+	want := map[soteria.Category]uint32{
+		soteria.CategoryConnection: 1,
+		soteria.CategoryServer:     2,
+		soteria.CategoryOther:      1,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("FailuresByCategory = %v, want %v", got, want)
+	}
+	for category, n := range want {
+		if got[category] != n {
+			t.Errorf("FailuresByCategory[%s] = %d, want %d", category, got[category], n)
+		}
+	}
+}
+
+func TestCustomClassifier(t *testing.T) {
+	errQuota := errors.New("quota")
+	cb, _ := newBreaker(t, soteria.Settings{
+		Classifier: func(err error) soteria.Category {
+			if err == errQuota {
+				return "quota"
+			}
+			return ""
+		},
+	})
+
+	failWith(cb, errQuota)
+	failWith(cb, errFail)
+
+	got := cb.Stats().FailuresByCategory
+	if got["quota"] != 1 || got[soteria.CategoryOther] != 1 {
+		t.Errorf("FailuresByCategory = %v", got)
+	}
+}
+
+func TestFailuresByCategoryClearWithInterval(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Interval: time.Minute})
+	failWith(cb, errFail)
+
+	clock.Advance(time.Minute)
+	if got := cb.Stats().FailuresByCategory; len(got) != 0 {
+		t.Errorf("FailuresByCategory = %v after the interval", got)
+	}
+}
+
+func TestStatsSnapshotIsCopied(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	failWith(cb, errFail)
+
+	cb.Stats().FailuresByCategory[soteria.CategoryOther] = 100
+	if got := cb.Stats().FailuresByCategory[soteria.CategoryOther]; got != 1 {
+		t.Errorf("FailuresByCategory[other] = %d, changed through a snapshot", got)
+	}
+}
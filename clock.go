@@ -0,0 +1,26 @@
+package soteria
+
+import "time"
+
+// Clock is the time source of a CircuitBreaker.
+//
+// The times of a Clock should carry a monotonic clock reading, as those of
+// time.Now do, so that expiry keeps to elapsed time and is immune to steps
+// of the wall clock, such as NTP corrections. The wall clock is only used
+// where it is meant to be, to align intervals and for schedules.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// truncate is like t.Truncate(d), but keeps the monotonic clock reading of
+// t, so that comparing the result with later times is not thrown off by
+// steps of the wall clock.
+func truncate(t time.Time, d time.Duration) time.Time {
+	return t.Add(t.Truncate(d).Sub(t))
+}
@@ -0,0 +1,237 @@
+// Command soteriactl inspects and controls the circuit breakers of a
+// process serving a soteria.AdminHandler.
+//
+// Usage:
+//
+//	soteriactl [-addr URL] list
+//	soteriactl [-addr URL] stats NAME
+//	soteriactl [-addr URL] open|close|reset|reset-stats [-operator WHO] [-reason WHY] NAME
+//	soteriactl [-addr URL] shed -percent P [-operator WHO] [-reason WHY] NAME
+//	soteriactl [-addr URL] audit [-limit N] [NAME]
+//	soteriactl [-addr URL] tail [-all]
+//
+// list prints a table of breakers, stats prints a breaker with its stats as
+// JSON, open, close, reset and reset-stats apply a manual override, shed
+// sheds a percentage of the requests of a breaker, 0 to stop, audit prints
+// the recorded overrides, and tail prints state changes as they happen.
+// -operator defaults to $USER.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jtejido/soteria"
+)
+
+var addr = flag.String("addr", "http://localhost:8080/debug/soteria", "base URL of the admin handler")
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch cmd, args := flag.Arg(0), flag.Args()[1:]; cmd {
+	case "list":
+		err = list()
+	case "stats":
+		err = withName(args, stats)
+	case "open", "close", "reset", "reset-stats", "shed":
+		err = control(cmd, args)
+	case "audit":
+		err = audit(args)
+	case "tail":
+		err = tail(args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "soteriactl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: soteriactl [-addr URL] list | stats NAME | open|close|reset|reset-stats [-operator WHO] [-reason WHY] NAME | shed -percent P [-operator WHO] [-reason WHY] NAME | audit [-limit N] [NAME] | tail [-all]\n")
+	flag.PrintDefaults()
+}
+
+func withName(args []string, f func(name string) error) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly one breaker name")
+	}
+	return f(args[0])
+}
+
+func list() error {
+	var statuses []soteria.BreakerStatus
+	if err := call(http.MethodGet, "breakers", &statuses); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATE\tLABELS")
+	for _, s := range statuses {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, s.State, labels(s.Labels))
+	}
+	return w.Flush()
+}
+
+func stats(name string) error {
+	var status soteria.BreakerStatus
+	if err := call(http.MethodGet, "breakers/"+url.PathEscape(name), &status); err != nil {
+		return err
+	}
+	return printJSON(status)
+}
+
+func control(action string, args []string) error {
+	fs := flag.NewFlagSet(action, flag.ExitOnError)
+	operator := fs.String("operator", os.Getenv("USER"), "who applies the override")
+	reason := fs.String("reason", "", "why the override is applied")
+	var percent *float64
+	if action == "shed" {
+		percent = fs.Float64("percent", 0, "percentage of the requests to shed")
+	}
+	fs.Parse(args)
+
+	return withName(fs.Args(), func(name string) error {
+		form := url.Values{}
+		form.Set("operator", *operator)
+		form.Set("reason", *reason)
+		if percent != nil {
+			form.Set("percent", strconv.FormatFloat(*percent, 'g', -1, 64))
+		}
+
+		var status soteria.BreakerStatus
+		path := "breakers/" + url.PathEscape(name) + "/" + action + "?" + form.Encode()
+		if err := call(http.MethodPost, path, &status); err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s\n", status.Name, status.State)
+		return nil
+	})
+}
+
+func audit(args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ExitOnError)
+	limit := fs.Int("limit", 0, "print only the last N entries")
+	fs.Parse(args)
+
+	if fs.NArg() > 1 {
+		return fmt.Errorf("expected at most one breaker name")
+	}
+
+	query := url.Values{}
+	if fs.NArg() == 1 {
+		query.Set("breaker", fs.Arg(0))
+	}
+	if *limit > 0 {
+		query.Set("limit", strconv.Itoa(*limit))
+	}
+
+	var entries []soteria.AuditEntry
+	if err := call(http.MethodGet, "audit?"+query.Encode(), &entries); err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tBREAKER\tACTION\tOPERATOR\tREASON\tERROR")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Time.Format("2006-01-02T15:04:05Z07:00"), e.Breaker, e.Action, e.Operator, e.Reason, e.Error)
+	}
+	return w.Flush()
+}
+
+func tail(args []string) error {
+	fs := flag.NewFlagSet("tail", flag.ExitOnError)
+	all := fs.Bool("all", false, "print every event, not only state changes")
+	fs.Parse(args)
+
+	path := "events"
+	if *all {
+		path += "?all=true"
+	}
+
+	resp, err := request(http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var e soteria.TraceEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return err
+		}
+
+		switch e.Kind {
+		case soteria.TraceTransition:
+			fmt.Printf("%s %s %s -> %s\n", e.Time.Format("2006-01-02T15:04:05.000Z07:00"), e.Breaker, e.From, e.To)
+		default:
+			fmt.Printf("%s %s %s %s%s\n", e.Time.Format("2006-01-02T15:04:05.000Z07:00"), e.Breaker, e.Kind, e.Category, e.Error)
+		}
+	}
+	return scanner.Err()
+}
+
+func request(method, path string) (*http.Response, error) {
+	req, err := http.NewRequest(method, strings.TrimSuffix(*addr, "/")+"/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+func call(method, path string, v interface{}) error {
+	resp, err := request(method, path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func labels(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,88 @@
+package soteria
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// Command runs external processes, such as remote commands over ssh,
+// through a CircuitBreaker, for automation that shells out to flaky
+// systems. Runs that fail to start, exit with a non-zero code or time out
+// count as failures, unless Settings.IsSuccessful says otherwise; see
+// ExitCodes.
+//
+// Timeout, if greater than 0, bounds every run. A run killed because it
+// expired fails with an error matching context.DeadlineExceeded, so that
+// it is categorized as CategoryTimeout.
+type Command struct {
+	Breaker *CircuitBreaker
+	Timeout time.Duration
+}
+
+// Run runs the named program with args.
+func (c *Command) Run(ctx context.Context, name string, args ...string) error {
+	return c.RunCmd(ctx, func(ctx context.Context) *exec.Cmd {
+		return exec.CommandContext(ctx, name, args...)
+	})
+}
+
+// Output runs the named program with args and returns its standard
+// output. The *exec.ExitError of a failed run carries its standard error.
+func (c *Command) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var out []byte
+	err := c.do(ctx, func(ctx context.Context) error {
+		var err error
+		out, err = exec.CommandContext(ctx, name, args...).Output()
+		return err
+	})
+	return out, err
+}
+
+// RunCmd runs the command returned by cmd, for commands that need more
+// setup, such as a directory or input. cmd must create it with
+// exec.CommandContext and the context it is passed.
+func (c *Command) RunCmd(ctx context.Context, cmd func(ctx context.Context) *exec.Cmd) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return cmd(ctx).Run()
+	})
+}
+
+func (c *Command) do(ctx context.Context, run func(ctx context.Context) error) error {
+	return c.Breaker.RunContext(ctx, func(ctx context.Context) error {
+		if c.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+			defer cancel()
+		}
+
+		err := run(ctx)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w: command killed after %v: %v", context.DeadlineExceeded, c.Timeout, err)
+		}
+		return err
+	})
+}
+
+// ExitCodes can be used as Settings.IsSuccessful to count the exits with
+// codes as successes, along with nil errors, such as 1 for grep finding
+// nothing. Other errors are failures.
+func ExitCodes(codes ...int) func(err error) bool {
+	return func(err error) bool {
+		if err == nil {
+			return true
+		}
+
+		var ee *exec.ExitError
+		if errors.As(err, &ee) {
+			for _, code := range codes {
+				if ee.ExitCode() == code {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
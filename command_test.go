@@ -0,0 +1,70 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func requireShell(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("no sh")
+	}
+}
+
+func TestCommandOutput(t *testing.T) {
+	requireShell(t)
+	cb, _ := newBreaker(t, soteria.Settings{})
+	c := &soteria.Command{Breaker: cb}
+
+	out, err := c.Output(context.Background(), "sh", "-c", "echo ok")
+	if err != nil || strings.TrimSpace(string(out)) != "ok" {
+		t.Fatalf("Output = %q, %v", out, err)
+	}
+
+	for i := 0; i < 6; i++ {
+		var ee *exec.ExitError
+		if err := c.Run(context.Background(), "sh", "-c", "exit 3"); !errors.As(err, &ee) || ee.ExitCode() != 3 {
+			t.Fatalf("Run = %v, want exit status 3", err)
+		}
+	}
+	soteriatest.AssertOpen(t, cb)
+
+	if err := c.Run(context.Background(), "sh", "-c", "true"); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Run while open = %v, want ErrOpenState", err)
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	requireShell(t)
+	cb, _ := newBreaker(t, soteria.Settings{})
+	c := &soteria.Command{Breaker: cb, Timeout: 20 * time.Millisecond}
+
+	err := c.RunCmd(context.Background(), func(ctx context.Context) *exec.Cmd {
+		return exec.CommandContext(ctx, "sleep", "5")
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("RunCmd = %v, want DeadlineExceeded", err)
+	}
+	if s := cb.Stats(); s.FailuresByCategory[soteria.CategoryTimeout] != 1 {
+		t.Errorf("FailuresByCategory = %v, want a timeout", s.FailuresByCategory)
+	}
+}
+
+func TestExitCodes(t *testing.T) {
+	requireShell(t)
+	cb, _ := newBreaker(t, soteria.Settings{IsSuccessful: soteria.ExitCodes(1)})
+	c := &soteria.Command{Breaker: cb}
+
+	c.Run(context.Background(), "sh", "-c", "exit 1")
+	c.Run(context.Background(), "sh", "-c", "exit 2")
+	if s := cb.Stats(); s.TotalSuccesses != 1 || s.TotalFailures != 1 {
+		t.Errorf("Stats = %+v, want exit 1 counted as a success", s)
+	}
+}
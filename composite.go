@@ -0,0 +1,92 @@
+package soteria
+
+import (
+	"sort"
+	"time"
+)
+
+// Composite gates an operation that depends on several CircuitBreakers.
+// It is open when at least quorum of its members are open, half-open when
+// it is not open but some member is half-open, and closed otherwise.
+//
+// A Composite does no accounting of its own: the members keep tracking
+// the calls made to their dependencies.
+type Composite struct {
+	name    string
+	quorum  int
+	members []*CircuitBreaker
+}
+
+// NewComposite returns a Composite over members. If quorum is 0 or less,
+// any single open member opens the Composite.
+func NewComposite(name string, quorum int, members ...*CircuitBreaker) *Composite {
+	if quorum <= 0 {
+		quorum = 1
+	}
+
+	return &Composite{
+		name:    name,
+		quorum:  quorum,
+		members: append([]*CircuitBreaker(nil), members...),
+	}
+}
+
+func (c *Composite) Name() string {
+	return c.name
+}
+
+func (c *Composite) State() State {
+	open, halfOpen := 0, 0
+	for _, m := range c.members {
+		switch m.State() {
+		case StateOpen, StateIsolated:
+			open++
+		case StateHalfOpen:
+			halfOpen++
+		}
+	}
+
+	switch {
+	case open >= c.quorum:
+		return StateOpen
+	case halfOpen > 0:
+		return StateHalfOpen
+	}
+	return StateClosed
+}
+
+// RemainingOpenTime returns how long the Composite stays open before
+// fewer than quorum of its members are open, as far as their timeouts
+// tell. It returns 0 if the Composite is not open, or is held open by
+// isolated members.
+func (c *Composite) RemainingOpenTime() time.Duration {
+	var remaining []time.Duration
+	isolated := 0
+	for _, m := range c.members {
+		switch m.State() {
+		case StateOpen:
+			remaining = append(remaining, m.RemainingOpenTime())
+		case StateIsolated:
+			isolated++
+		}
+	}
+
+	// the Composite closes once one more than the surplus over quorum
+	// of its open members left the open state
+	surplus := len(remaining) + isolated - c.quorum
+	if surplus < 0 || surplus >= len(remaining) {
+		return 0
+	}
+
+	sort.Slice(remaining, func(i, j int) bool { return remaining[i] < remaining[j] })
+	return remaining[surplus]
+}
+
+// Execute runs req unless the Composite is open, in which case it
+// returns an *OpenStateError.
+func (c *Composite) Execute(req func() (interface{}, error)) (interface{}, error) {
+	if c.State() == StateOpen {
+		return nil, &OpenStateError{Remaining: c.RemainingOpenTime()}
+	}
+	return req()
+}
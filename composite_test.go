@@ -0,0 +1,77 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func trip(cb *soteria.CircuitBreaker) {
+	for i := 0; i < 6; i++ {
+		fail(cb)
+	}
+}
+
+func TestCompositeAnyMemberOpens(t *testing.T) {
+	a, _ := newBreaker(t, soteria.Settings{})
+	b, _ := newBreaker(t, soteria.Settings{})
+	c := soteria.NewComposite("ab", 0, a, b)
+
+	if c.State() != soteria.StateClosed {
+		t.Errorf("State = %v, want closed", c.State())
+	}
+
+	trip(b)
+	if c.State() != soteria.StateOpen {
+		t.Errorf("State = %v, want open", c.State())
+	}
+
+	called := false
+	_, err := c.Execute(func() (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+	if !errors.Is(err, soteria.ErrOpenState) || called {
+		t.Errorf("Execute = %v, called %v", err, called)
+	}
+}
+
+func TestCompositeQuorum(t *testing.T) {
+	a, _ := newBreaker(t, soteria.Settings{})
+	b, _ := newBreaker(t, soteria.Settings{})
+	d, _ := newBreaker(t, soteria.Settings{})
+	c := soteria.NewComposite("abd", 2, a, b, d)
+
+	trip(a)
+	if c.State() != soteria.StateClosed {
+		t.Errorf("State = %v with one member open, want closed", c.State())
+	}
+
+	d.ForceOpen()
+	if c.State() != soteria.StateOpen {
+		t.Errorf("State = %v with an open and an isolated member, want open", c.State())
+	}
+}
+
+func TestCompositeHalfOpen(t *testing.T) {
+	a, clock := newBreaker(t, soteria.Settings{})
+	b, _ := newBreaker(t, soteria.Settings{})
+	c := soteria.NewComposite("ab", 2, a, b)
+
+	trip(a)
+	clock.Advance(a.Timeout())
+	if c.State() != soteria.StateHalfOpen {
+		t.Errorf("State = %v, want half-open", c.State())
+	}
+}
+
+func TestCompositeDoesNoAccounting(t *testing.T) {
+	a, _ := newBreaker(t, soteria.Settings{})
+	c := soteria.NewComposite("a", 0, a)
+
+	c.Execute(func() (interface{}, error) { return nil, errFail })
+	if st := a.Stats(); st.Requests != 0 {
+		t.Errorf("member Stats = %+v, want untouched", st)
+	}
+}
@@ -0,0 +1,111 @@
+package soteria
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SettingsConfig is the part of Settings a config file can hold. Zero
+// fields leave the Settings it is applied to unchanged.
+type SettingsConfig struct {
+	MaxRequests     uint32
+	ProbeSuccesses  uint32
+	Interval        time.Duration
+	Timeout         time.Duration
+	MinimumRequests uint32
+
+	// FailureRatio, if greater than 0, sets a ReadyToTrip tripping once this
+	// share of the outcomes failed.
+	FailureRatio float64
+}
+
+// settingsConfig is a SettingsConfig as a config file holds it.
+type settingsConfig struct {
+	MaxRequests     uint32       `json:"max_requests,omitempty"`
+	ProbeSuccesses  uint32       `json:"probe_successes,omitempty"`
+	Interval        jsonDuration `json:"interval,omitempty"`
+	Timeout         jsonDuration `json:"timeout,omitempty"`
+	MinimumRequests uint32       `json:"minimum_requests,omitempty"`
+	FailureRatio    float64      `json:"failure_ratio,omitempty"`
+}
+
+func (c SettingsConfig) MarshalJSON() ([]byte, error) {
+	return json.Marshal(settingsConfig{
+		MaxRequests:     c.MaxRequests,
+		ProbeSuccesses:  c.ProbeSuccesses,
+		Interval:        jsonDuration(c.Interval),
+		Timeout:         jsonDuration(c.Timeout),
+		MinimumRequests: c.MinimumRequests,
+		FailureRatio:    c.FailureRatio,
+	})
+}
+
+func (c *SettingsConfig) UnmarshalJSON(data []byte) error {
+	var v settingsConfig
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*c = SettingsConfig{
+		MaxRequests:     v.MaxRequests,
+		ProbeSuccesses:  v.ProbeSuccesses,
+		Interval:        time.Duration(v.Interval),
+		Timeout:         time.Duration(v.Timeout),
+		MinimumRequests: v.MinimumRequests,
+		FailureRatio:    v.FailureRatio,
+	}
+	return nil
+}
+
+// Apply sets the non-zero fields of c on settings.
+func (c SettingsConfig) Apply(settings *Settings) {
+	if c.MaxRequests > 0 {
+		settings.MaxRequests = c.MaxRequests
+	}
+	if c.ProbeSuccesses > 0 {
+		settings.ProbeSuccesses = c.ProbeSuccesses
+	}
+	if c.Interval > 0 {
+		settings.Interval = c.Interval
+	}
+	if c.Timeout > 0 {
+		settings.Timeout = c.Timeout
+	}
+	if c.MinimumRequests > 0 {
+		settings.MinimumRequests = c.MinimumRequests
+	}
+	if c.FailureRatio > 0 {
+		settings.ReadyToTrip = failureRatio(c.FailureRatio)
+	}
+}
+
+// ParseSettingsConfigs parses a JSON object of SettingsConfigs by key, such
+// as breaker names or gRPC methods:
+//
+//	{
+//	  "/payments.Payments/Charge": {"timeout": "2m", "failure_ratio": 0.2, "minimum_requests": 20},
+//	  "inventory.Inventory": {"max_requests": 5, "interval": "1m"}
+//	}
+func ParseSettingsConfigs(data []byte) (map[string]SettingsConfig, error) {
+	var configs map[string]SettingsConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// LoadSettingsConfigs parses the SettingsConfigs of the file at path.
+// See ParseSettingsConfigs.
+func LoadSettingsConfigs(path string) (map[string]SettingsConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	configs, err := ParseSettingsConfigs(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return configs, nil
+}
@@ -0,0 +1,48 @@
+package soteria_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestParseSettingsConfigs(t *testing.T) {
+	configs, err := soteria.ParseSettingsConfigs([]byte(`{
+		"db": {"timeout": "2m", "max_requests": 5, "interval": "1m", "minimum_requests": 4, "failure_ratio": 0.5},
+		"cache": {}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := soteria.SettingsConfig{Timeout: 2 * time.Minute, MaxRequests: 5, Interval: time.Minute, MinimumRequests: 4, FailureRatio: 0.5}
+	if configs["db"] != want || configs["cache"] != (soteria.SettingsConfig{}) {
+		t.Fatalf("configs = %+v", configs)
+	}
+
+	settings := soteria.Settings{Timeout: time.Second, ProbeSuccesses: 2}
+	configs["db"].Apply(&settings)
+	if settings.Timeout != 2*time.Minute || settings.MaxRequests != 5 || settings.ProbeSuccesses != 2 || settings.ReadyToTrip == nil {
+		t.Errorf("applied settings = %+v", settings)
+	}
+	if settings.ReadyToTrip(soteria.Stats{TotalSuccesses: 1, TotalFailures: 1}) != true {
+		t.Error("ReadyToTrip does not trip at the failure ratio")
+	}
+
+	if _, err := soteria.ParseSettingsConfigs([]byte(`{"db": {"timeout": "soon"}}`)); err == nil {
+		t.Error("parsed an invalid timeout")
+	}
+}
+
+func TestLoadSettingsConfigs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breakers.json")
+	os.WriteFile(path, []byte(`{"db": {"timeout": "30s"}}`), 0o600)
+
+	configs, err := soteria.LoadSettingsConfigs(path)
+	if err != nil || configs["db"].Timeout != 30*time.Second {
+		t.Errorf("LoadSettingsConfigs = %+v, %v", configs, err)
+	}
+}
@@ -0,0 +1,64 @@
+package soteria
+
+import (
+	"context"
+	"time"
+)
+
+// Connector guards the establishment of long-lived connections, such as
+// WebSockets or gRPC streams, with a CircuitBreaker, for which accounting
+// per request does not fit. A dial that fails and a connection that closes
+// abnormally count as failures; an established connection counts as a
+// success. While the CircuitBreaker rejects dials, Connect backs off
+// instead of letting every client reconnect at once.
+//
+// Backoff and MaxBackoff bound the delays between rejected dials, as for
+// Consumer.
+type Connector[C any] struct {
+	Breaker *CircuitBreaker
+	Dial    func(ctx context.Context) (C, error)
+
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// Connect dials a connection through the Breaker. It waits while the
+// Breaker is open or isolated and retries dials the Breaker rejects, until
+// one is admitted or ctx is done. The error of an admitted dial that
+// failed is returned as is.
+func (c *Connector[C]) Connect(ctx context.Context) (C, error) {
+	for n := uint32(1); ; n++ {
+		var conn C
+		if err := waitAdmitting(ctx, c.Breaker, c.delay); err != nil {
+			return conn, err
+		}
+
+		admitted := false
+		err := c.Breaker.RunContext(ctx, func(ctx context.Context) error {
+			admitted = true
+			var err error
+			conn, err = c.Dial(ctx)
+			return err
+		})
+		if admitted {
+			return conn, err
+		}
+
+		if err := sleep(ctx, c.delay(err, n)); err != nil {
+			return conn, err
+		}
+	}
+}
+
+// Closed reports that a connection returned by Connect closed with err
+// after lifetime. A nil err is a normal closure, which is not counted;
+// any other counts as a failure, unless Settings.IsSuccessful holds for it.
+func (c *Connector[C]) Closed(err error, lifetime time.Duration) {
+	if err != nil {
+		c.Breaker.ReportFailure(err, lifetime)
+	}
+}
+
+func (c *Connector[C]) delay(err error, n uint32) time.Duration {
+	return backoff(err, n, c.Backoff, c.MaxBackoff)
+}
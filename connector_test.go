@@ -0,0 +1,91 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+type conn struct{ id int32 }
+
+func TestConnector(t *testing.T) {
+	cb := soteria.New(soteria.Settings{Timeout: 20 * time.Millisecond})
+
+	var dials int32
+	down := int32(1)
+	c := &soteria.Connector[*conn]{
+		Breaker: cb,
+		Dial: func(ctx context.Context) (*conn, error) {
+			n := atomic.AddInt32(&dials, 1)
+			if atomic.LoadInt32(&down) == 1 {
+				return nil, errFail
+			}
+			return &conn{n}, nil
+		},
+	}
+
+	for i := 0; i < 6; i++ {
+		if _, err := c.Connect(context.Background()); err != errFail {
+			t.Fatalf("Connect = %v, want the dial error", err)
+		}
+	}
+	soteriatest.AssertOpen(t, cb)
+
+	atomic.StoreInt32(&down, 0)
+	start := time.Now()
+	cn, err := c.Connect(context.Background())
+	if err != nil || cn == nil {
+		t.Fatalf("Connect after recovery = %v, %v", cn, err)
+	}
+	if time.Since(start) < 10*time.Millisecond || dials != 7 {
+		t.Errorf("reconnected after %v and %d dials, want to wait out the open state", time.Since(start), dials)
+	}
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestConnectorClosed(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	c := &soteria.Connector[*conn]{Breaker: cb}
+
+	c.Closed(nil, time.Hour)
+	if s := cb.Stats(); s.Requests != 0 {
+		t.Errorf("Stats = %+v after a normal closure, want nothing counted", s)
+	}
+
+	for i := 0; i < 6; i++ {
+		c.Closed(errors.New("connection reset"), time.Second)
+	}
+	soteriatest.AssertOpen(t, cb)
+}
+
+func TestConnectorRetriesRejectedDials(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{})
+	soteriatest.Trip(t, cb, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	release := make(chan struct{})
+	probing := make(chan struct{})
+	c := &soteria.Connector[*conn]{
+		Breaker: cb,
+		Dial: func(ctx context.Context) (*conn, error) {
+			close(probing)
+			<-release
+			return &conn{}, nil
+		},
+		Backoff: time.Millisecond,
+	}
+	go c.Connect(context.Background())
+	<-probing
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.Connect(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Connect while the probe is in flight = %v, want to retry until the context is done", err)
+	}
+	close(release)
+}
@@ -0,0 +1,136 @@
+package soteria
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// Consumer runs the message handler of a queue consumer, such as a Pub/Sub
+// subscription or an SQS poller, under a CircuitBreaker. Messages the
+// CircuitBreaker rejects are handed back with Nack, to be redelivered
+// later, rather than failed one by one into the dead letter queue, and
+// pull loops can pause with Wait while the CircuitBreaker is open:
+//
+//	c := &soteria.Consumer[*pubsub.Message]{
+//		Breaker: cb,
+//		Handle:  handle,
+//		Nack:    func(m *pubsub.Message, delay time.Duration) { m.Nack() },
+//	}
+//	sub.Receive(ctx, func(ctx context.Context, m *pubsub.Message) {
+//		if err := c.Process(ctx, m); err == nil {
+//			m.Ack()
+//		} else if !soteria.IsRejected(err) {
+//			m.Nack()
+//		}
+//	})
+//
+// With SQS, Nack would change the visibility timeout of the message to
+// delay, and the receive loop would call Wait before every
+// ReceiveMessage, so that messages are not received at all, and do not
+// count towards maxReceiveCount, while the CircuitBreaker is open.
+//
+// Backoff is the delay after the first of consecutive rejections, doubling
+// with every further one up to MaxBackoff. Rejections by an open
+// CircuitBreaker are delayed by its remaining open time instead.
+// If Backoff or MaxBackoff is 0, 1 second and 1 minute are used.
+type Consumer[M any] struct {
+	Breaker *CircuitBreaker
+	Handle  func(ctx context.Context, m M) error
+	Nack    func(m M, delay time.Duration)
+
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	rejections uint32
+}
+
+// Process handles m through the Breaker, as with ExecuteContext. If the
+// Breaker rejects m, Nack is called, if set, and the rejection is returned.
+func (c *Consumer[M]) Process(ctx context.Context, m M) error {
+	admitted := false
+	err := c.Breaker.RunContext(ctx, func(ctx context.Context) error {
+		admitted = true
+		atomic.StoreUint32(&c.rejections, 0)
+		return c.Handle(ctx, m)
+	})
+
+	if !admitted {
+		delay := c.delay(err, atomic.AddUint32(&c.rejections, 1))
+		if c.Nack != nil {
+			c.Nack(m, delay)
+		}
+	}
+	return err
+}
+
+// Wait blocks while the Breaker is open or isolated, or until ctx is done,
+// in which case it returns ctx.Err().
+func (c *Consumer[M]) Wait(ctx context.Context) error {
+	return waitAdmitting(ctx, c.Breaker, c.delay)
+}
+
+// delay returns the delay after the nth consecutive rejection with err.
+func (c *Consumer[M]) delay(err error, n uint32) time.Duration {
+	return backoff(err, n, c.Backoff, c.MaxBackoff)
+}
+
+// waitAdmitting blocks while cb is open or isolated, or until ctx is done,
+// sleeping delay after every check.
+func waitAdmitting(ctx context.Context, cb *CircuitBreaker, delay func(err error, n uint32) time.Duration) error {
+	for n := uint32(1); ; n++ {
+		var err error
+		switch cb.State() {
+		case StateOpen:
+			err = &OpenStateError{Remaining: cb.RemainingOpenTime()}
+		case StateIsolated:
+			err = ErrIsolated
+		default:
+			return nil
+		}
+
+		if err := sleep(ctx, delay(err, n)); err != nil {
+			return err
+		}
+	}
+}
+
+// sleep waits for d, or until ctx is done, in which case it returns ctx.Err().
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// backoff returns the delay after the nth consecutive rejection with err:
+// the remaining open time, or min doubled with every rejection up to max.
+// If min or max is 0, 1 second and 1 minute are used.
+func backoff(err error, n uint32, min, max time.Duration) time.Duration {
+	var open *OpenStateError
+	if errors.As(err, &open) && open.Remaining > 0 {
+		return open.Remaining
+	}
+
+	if min <= 0 {
+		min = time.Second
+	}
+	if max <= 0 {
+		max = time.Minute
+	}
+
+	d := min
+	for i := uint32(1); i < n && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
@@ -0,0 +1,129 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+type nack struct {
+	m     int
+	delay time.Duration
+}
+
+func TestConsumerNacksRejected(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{Timeout: time.Minute})
+
+	var handled []int
+	var nacks []nack
+	c := &soteria.Consumer[int]{
+		Breaker: cb,
+		Handle: func(ctx context.Context, m int) error {
+			handled = append(handled, m)
+			return errFail
+		},
+		Nack: func(m int, delay time.Duration) { nacks = append(nacks, nack{m, delay}) },
+	}
+
+	for m := 0; m < 6; m++ {
+		if err := c.Process(context.Background(), m); err != errFail {
+			t.Fatalf("Process(%d) = %v, want the error of Handle", m, err)
+		}
+	}
+	soteriatest.AssertOpen(t, cb)
+
+	if err := c.Process(context.Background(), 6); !soteria.IsRejected(err) {
+		t.Errorf("Process while open = %v, want a rejection", err)
+	}
+	if len(handled) != 6 || len(nacks) != 1 || nacks[0] != (nack{6, time.Minute}) {
+		t.Errorf("handled %v, nacked %v, want 6 nacked after the open time", handled, nacks)
+	}
+}
+
+func TestConsumerBackoff(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	cb.ForceOpen()
+
+	var delays []time.Duration
+	c := &soteria.Consumer[string]{
+		Breaker:    cb,
+		Handle:     func(ctx context.Context, m string) error { return nil },
+		Nack:       func(m string, delay time.Duration) { delays = append(delays, delay) },
+		Backoff:    time.Second,
+		MaxBackoff: 5 * time.Second,
+	}
+
+	for i := 0; i < 5; i++ {
+		c.Process(context.Background(), "m")
+	}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Fatalf("delays = %v, want %v", delays, want)
+		}
+	}
+
+	cb.Reset()
+	c.Process(context.Background(), "m")
+	cb.ForceOpen()
+	c.Process(context.Background(), "m")
+	if d := delays[len(delays)-1]; d != time.Second {
+		t.Errorf("delay after an admitted message = %v, want the backoff reset", d)
+	}
+}
+
+func TestConsumerHandledRejection(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	nacked := false
+	c := &soteria.Consumer[int]{
+		Breaker: cb,
+		Handle:  func(ctx context.Context, m int) error { return soteria.ErrTooManyRequests },
+		Nack:    func(m int, delay time.Duration) { nacked = true },
+	}
+
+	c.Process(context.Background(), 1)
+	if nacked {
+		t.Error("Nack called for a rejection returned by Handle")
+	}
+}
+
+func TestConsumerWait(t *testing.T) {
+	cb := soteria.New(soteria.Settings{Timeout: 20 * time.Millisecond})
+	c := &soteria.Consumer[int]{Breaker: cb}
+
+	if err := c.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	soteriatest.Trip(t, cb, 10)
+	start := time.Now()
+	if err := c.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < 10*time.Millisecond || cb.State() != soteria.StateHalfOpen {
+		t.Errorf("Wait returned after %v in state %v", time.Since(start), cb.State())
+	}
+
+	cb.ForceOpen()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := c.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait while isolated = %v, want the context error", err)
+	}
+}
+
+func TestIsRejected(t *testing.T) {
+	for _, err := range []error{soteria.ErrOpenState, soteria.ErrIsolated, soteria.ErrMaintenance, soteria.ErrTooManyRequests, &soteria.OpenStateError{}} {
+		if !soteria.IsRejected(err) {
+			t.Errorf("IsRejected(%v) = false", err)
+		}
+	}
+	if soteria.IsRejected(errFail) || soteria.IsRejected(nil) {
+		t.Error("IsRejected holds for other errors")
+	}
+}
@@ -0,0 +1,58 @@
+package soteria_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func cancelled() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func execContext(cb *soteria.CircuitBreaker, ctx context.Context) {
+	cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+}
+
+func TestCallerCancellationCountsByDefault(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	execContext(cb, cancelled())
+
+	if got := cb.Stats().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %d, want 1", got)
+	}
+}
+
+func TestIgnoreCallerCancellation(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{IgnoreCallerCancellation: true})
+	execContext(cb, cancelled())
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Unix(0, 0))
+	defer cancel()
+	execContext(cb, ctx)
+
+	st := cb.Stats()
+	if st.TotalFailures != 0 || st.TotalSuccesses != 0 {
+		t.Errorf("Stats = %+v, want caller cancellations ignored", st)
+	}
+}
+
+func TestIgnoreCallerCancellationKeepsOwnDeadlines(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{IgnoreCallerCancellation: true})
+
+	// the request times out on its own while the caller is still waiting
+	cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return nil, context.DeadlineExceeded
+	})
+
+	if got := cb.Stats().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %d, want 1", got)
+	}
+}
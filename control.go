@@ -0,0 +1,111 @@
+package soteria
+
+// ForceOpen isolates the CircuitBreaker: it moves to StateIsolated and
+// rejects every request with ErrIsolated until ForceClose or Reset, so that
+// a breaker turned off by a human is told apart from one that tripped.
+func (cb *CircuitBreaker) ForceOpen() error {
+	if cb.unprotected() {
+		return nil
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.clock.Now()
+	cb.currentState(now)
+	defer cb.verify(now)
+	return cb.process(Force, now)
+}
+
+// ForceClose closes the CircuitBreaker. It is a no-op if it is closed.
+func (cb *CircuitBreaker) ForceClose() error {
+	if cb.unprotected() {
+		return nil
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.clock.Now()
+	cb.currentState(now)
+	defer cb.verify(now)
+	return cb.process(Reset, now)
+}
+
+// Reset closes the CircuitBreaker and starts a new generation, clearing
+// its Stats even if it was closed already.
+func (cb *CircuitBreaker) Reset() error {
+	if cb.unprotected() {
+		return nil
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.clock.Now()
+	cb.currentState(now)
+	defer cb.verify(now)
+
+	if cb.state() == StateClosed {
+		cb.roll(now)
+		return nil
+	}
+	return cb.process(Reset, now)
+}
+
+// ResetStats clears the Stats and rolling windows of the CircuitBreaker
+// without changing its state or when it expires, such as after a known
+// incident has been dealt with, and traces a TraceStatsReset event with
+// reason. Requests in flight are not counted towards the cleared Stats.
+func (cb *CircuitBreaker) ResetStats(reason string) {
+	if cb.unprotected() {
+		return
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.clock.Now()
+	cb.currentState(now)
+	defer cb.verify(now)
+
+	cb.generation++
+	cb.stats.clear()
+	for _, w := range cb.windows {
+		w.reset()
+	}
+	cb.rolled(now)
+	cb.trace(TraceEvent{Time: now, Kind: TraceStatsReset, Reason: reason})
+}
+
+// UpdateSettings replaces the settings of the CircuitBreaker, applying the
+// same defaults as New, and starts a new generation in the current state.
+// Name and Labels cannot be changed and are ignored.
+func (cb *CircuitBreaker) UpdateSettings(settings Settings) {
+	if cb.unprotected() {
+		return
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.apply(settings)
+
+	now := cb.clock.Now()
+	cb.roll(now)
+	cb.verify(now)
+}
+
+// ModifySettings calls modify with a copy of the settings last given to New
+// or UpdateSettings, and applies the result like UpdateSettings. It allows
+// changing some settings while keeping the rest, such as the hooks.
+func (cb *CircuitBreaker) ModifySettings(modify func(settings *Settings)) {
+	if cb.unprotected() {
+		return
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	settings := cb.settings
+	modify(&settings)
+	cb.apply(settings)
+
+	now := cb.clock.Now()
+	cb.roll(now)
+	cb.verify(now)
+}
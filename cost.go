@@ -0,0 +1,33 @@
+package soteria
+
+import "context"
+
+type costKey struct{}
+
+// WithCost returns a copy of ctx making the request run with it weigh cost
+// in the WeightedRequests, WeightedSuccesses and WeightedFailures of Stats,
+// such as the bytes, rows or downstream calls it involves, so that one huge
+// batch counts proportionally in the decision to trip. Requests weigh 1
+// unless they carry a cost greater than 0. Only ExecuteContext and
+// RunContext see the cost.
+func WithCost(ctx context.Context, cost float64) context.Context {
+	return context.WithValue(ctx, costKey{}, cost)
+}
+
+// costOf returns the cost ctx carries, or 1.
+func costOf(ctx context.Context) float64 {
+	if cost, ok := ctx.Value(costKey{}).(float64); ok && cost > 0 {
+		return cost
+	}
+	return 1
+}
+
+// WeightedFailureRatio returns the share of WeightedFailures in the weight
+// of the outcomes counted, 0 if none was.
+func (c Stats) WeightedFailureRatio() float64 {
+	total := c.WeightedSuccesses + c.WeightedFailures
+	if total == 0 {
+		return 0
+	}
+	return c.WeightedFailures / total
+}
@@ -0,0 +1,42 @@
+package soteria_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestWithCost(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{
+		ReadyToTrip: func(stats soteria.Stats) bool { return stats.WeightedFailureRatio() >= 0.5 },
+	})
+
+	run := func(cost float64, err error) {
+		cb.RunContext(soteria.WithCost(context.Background(), cost), func(ctx context.Context) error { return err })
+	}
+
+	for i := 0; i < 9; i++ {
+		run(1, nil)
+	}
+	soteriatest.AssertClosed(t, cb)
+
+	s := cb.Stats()
+	if s.WeightedRequests != 9 || s.WeightedSuccesses != 9 || s.WeightedFailures != 0 {
+		t.Errorf("Stats = %+v, want requests weighing 1 each", s)
+	}
+
+	run(10, errFail)
+	soteriatest.AssertOpen(t, cb)
+}
+
+func TestWithCostDefaultsToOne(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	succeed(cb)
+	cb.RunContext(soteria.WithCost(context.Background(), -3), func(ctx context.Context) error { return errFail })
+
+	if s := cb.Stats(); s.WeightedRequests != 2 || s.WeightedSuccesses != 1 || s.WeightedFailures != 1 || s.WeightedFailureRatio() != 0.5 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
@@ -0,0 +1,149 @@
+package soteria
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// DefineState defines an application-specific State named name, such as a
+// "degraded" state between closed and open, for Settings.States and
+// Settings.Transitions. It is meant to be called from package-level
+// variable declarations. DefineState panics if name is already taken.
+func DefineState(name string) State {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+
+	for _, taken := range stateNames {
+		if taken == name {
+			panic(fmt.Sprintf("soteria: state %q already defined", name))
+		}
+	}
+
+	s := State(len(stateNames))
+	stateNames[s] = name
+	return s
+}
+
+// CustomState configures a State defined with DefineState.
+//
+// A CustomState counts outcomes like the closed state does, without
+// clearing them every Interval, and is left only through Transitions and
+// manual overrides.
+//
+// Admit is called with the context of every request made in the state.
+// It returns nil to admit the request or the error to reject it with.
+// If Admit is nil, every request is admitted. Like Settings.AllowProbe,
+// Admit is called while the lock of the CircuitBreaker is held.
+type CustomState struct {
+	State State
+	Admit func(ctx context.Context) error
+}
+
+// Transition moves a CircuitBreaker from one state to another. When is
+// called with the stats after every outcome counted in From, once
+// MinimumRequests is reached; the first Transition of From whose When
+// returns true is taken, after ReadyToTrip in the closed state and after
+// MaxRequests successes in the half-open state have had their say.
+type Transition struct {
+	From State
+	To   State
+	When func(stats Stats) bool
+}
+
+// RejectFraction returns an admission policy for CustomState.Admit that
+// rejects the given fraction, between 0 and 1, of requests at random with
+// ErrTooManyRequests.
+func RejectFraction(fraction float64) func(ctx context.Context) error {
+	return RejectFractionFrom(fraction, nil)
+}
+
+// RejectFractionFrom is like RejectFraction, picking the requests to reject
+// from src, or from the global source of math/rand if src is nil.
+func RejectFractionFrom(fraction float64, src rand.Source) func(ctx context.Context) error {
+	r := newRandom(src)
+	return func(ctx context.Context) error {
+		if r.Float64() < fraction {
+			return ErrTooManyRequests
+		}
+		return nil
+	}
+}
+
+// shiftInputs is the first FSM input used by Transitions; the input to a
+// state is shiftInputs plus the state.
+const shiftInputs Input = 1 << 16
+
+func shift(to State) Input {
+	return shiftInputs + Input(to)
+}
+
+// defineStates adds the custom states and the inputs of the transitions of
+// settings to those of an FSM about to be created.
+func defineStates(settings Settings, states []State, inputs []Input) ([]State, []Input) {
+	for _, cs := range settings.States {
+		if _, ok := stateName(cs.State); !ok || cs.State <= StateIsolated {
+			panic(fmt.Sprintf("soteria: %v is not defined with DefineState", cs.State))
+		}
+		states = append(states, cs.State)
+	}
+
+	seen := make(map[Input]bool)
+	for _, t := range settings.Transitions {
+		if in := shift(t.To); !seen[in] {
+			seen[in] = true
+			inputs = append(inputs, in)
+		}
+	}
+	return states, inputs
+}
+
+// initStates keeps the custom states and transitions of settings, whose
+// rules New adds to the FSM.
+func (cb *CircuitBreaker) initStates(settings Settings) {
+	cb.custom = make(map[State]CustomState, len(settings.States))
+	for _, cs := range settings.States {
+		cb.custom[cs.State] = cs
+	}
+
+	for _, t := range settings.Transitions {
+		if !cb.isState(t.From) || !cb.isState(t.To) {
+			panic(fmt.Sprintf("soteria: transition from %v to %v between unknown states", t.From, t.To))
+		}
+		if t.When == nil {
+			panic(fmt.Sprintf("soteria: transition from %v to %v has no When", t.From, t.To))
+		}
+		cb.transitions = append(cb.transitions, t)
+	}
+}
+
+func (cb *CircuitBreaker) isState(s State) bool {
+	_, ok := cb.custom[s]
+	return ok || s == StateClosed || s == StateHalfOpen || s == StateOpen || s == StateIsolated
+}
+
+// transit takes the first Transition of the current state that applies.
+// cb.mutex must be held.
+func (cb *CircuitBreaker) transit(now time.Time) error {
+	if cb.stats.outcomes() < cb.minimumRequests {
+		return nil
+	}
+
+	from := cb.state()
+	for _, t := range cb.transitions {
+		if t.From == from && t.When(cb.snapshot(now)) {
+			return cb.process(shift(t.To), now)
+		}
+	}
+	return nil
+}
+
+// admitCustom applies the admission policy of the current state, if it is
+// a CustomState. cb.mutex must be held.
+func (cb *CircuitBreaker) admitCustom(ctx context.Context) error {
+	if cs, ok := cb.custom[cb.state()]; ok && cs.Admit != nil {
+		return cs.Admit(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package soteria_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+var degraded = soteria.DefineState("degraded")
+
+// degradedSettings moves closed breakers to degraded after 2 failures,
+// back to closed after 3 successes, and open after 3 failures.
+func degradedSettings(admit float64) soteria.Settings {
+	return soteria.Settings{
+		Interval:    time.Minute,
+		ReadyToTrip: func(soteria.Stats) bool { return false },
+		States:      []soteria.CustomState{{State: degraded, Admit: soteria.RejectFraction(admit)}},
+		Transitions: []soteria.Transition{
+			{From: soteria.StateClosed, To: degraded, When: func(s soteria.Stats) bool { return s.ConsecutiveFailures >= 2 }},
+			{From: degraded, To: soteria.StateClosed, When: func(s soteria.Stats) bool { return s.ConsecutiveSuccesses >= 3 }},
+			{From: degraded, To: soteria.StateOpen, When: func(s soteria.Stats) bool { return s.ConsecutiveFailures >= 3 }},
+		},
+	}
+}
+
+func TestCustomStateTransitions(t *testing.T) {
+	cb, _ := newBreaker(t, degradedSettings(0))
+
+	fail(cb)
+	fail(cb)
+	if cb.State() != degraded {
+		t.Fatalf("State = %v, want degraded", cb.State())
+	}
+
+	for i := 0; i < 3; i++ {
+		succeed(cb)
+	}
+	if cb.State() != soteria.StateClosed {
+		t.Fatalf("State = %v, want closed", cb.State())
+	}
+
+	fail(cb)
+	fail(cb)
+	for i := 0; i < 3; i++ {
+		fail(cb)
+	}
+	if cb.State() != soteria.StateOpen {
+		t.Errorf("State = %v, want open", cb.State())
+	}
+}
+
+func TestCustomStateAdmit(t *testing.T) {
+	cb, _ := newBreaker(t, degradedSettings(1))
+	fail(cb)
+	fail(cb)
+
+	_, err := cb.Execute(func() (interface{}, error) { return nil, nil })
+	if err != soteria.ErrTooManyRequests {
+		t.Errorf("Execute = %v, want ErrTooManyRequests", err)
+	}
+}
+
+func TestCustomStateKeepsStatsPastInterval(t *testing.T) {
+	cb, clock := newBreaker(t, degradedSettings(0))
+	fail(cb)
+	fail(cb)
+
+	fail(cb)
+	clock.Advance(time.Hour)
+	if got := cb.Stats().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %d, want the degraded counts kept past Interval", got)
+	}
+}
+
+func TestCustomStateOverrides(t *testing.T) {
+	cb, _ := newBreaker(t, degradedSettings(0))
+	fail(cb)
+	fail(cb)
+
+	cb.ForceOpen()
+	if cb.State() != soteria.StateIsolated {
+		t.Errorf("State = %v after ForceOpen, want isolated", cb.State())
+	}
+
+	cb.Reset()
+	fail(cb)
+	fail(cb)
+	cb.Reset()
+	if cb.State() != soteria.StateClosed {
+		t.Errorf("State = %v after Reset, want closed", cb.State())
+	}
+}
+
+func TestDefineStateTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("defining degraded twice did not panic")
+		}
+	}()
+	soteria.DefineState("degraded")
+}
+
+func TestInvalidCustomStatesPanic(t *testing.T) {
+	for name, settings := range map[string]soteria.Settings{
+		"builtin state": {States: []soteria.CustomState{{State: soteria.StateOpen}}},
+		"undefined":     {States: []soteria.CustomState{{State: soteria.State(1 << 20)}}},
+		"unknown to":    {Transitions: []soteria.Transition{{From: soteria.StateClosed, To: degraded, When: func(soteria.Stats) bool { return true }}}},
+		"no When":       {Transitions: []soteria.Transition{{From: soteria.StateClosed, To: soteria.StateOpen}}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: New did not panic", name)
+				}
+			}()
+			soteria.New(settings)
+		}()
+	}
+}
+
+func TestMarshalCustomState(t *testing.T) {
+	text, err := degraded.MarshalText()
+	if err != nil || string(text) != "degraded" {
+		t.Errorf("MarshalText = %s, %v", text, err)
+	}
+
+	var s soteria.State
+	if err := s.UnmarshalText([]byte("degraded")); err != nil || s != degraded {
+		t.Errorf("UnmarshalText = %v, %v", s, err)
+	}
+}
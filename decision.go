@@ -0,0 +1,38 @@
+package soteria
+
+import "time"
+
+// TripDecision records one failure of a closed CircuitBreaker deciding
+// whether to trip, see Settings.DecisionHistory.
+type TripDecision struct {
+	Time time.Time `json:"time"`
+	// Stats are those ReadyToTrip was, or would have been, called with.
+	Stats   Stats `json:"stats"`
+	Tripped bool  `json:"tripped"`
+	// Skipped is "minimum-requests" if ReadyToTrip was not consulted
+	// because fewer than MinimumRequests outcomes were counted.
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// Decisions returns the last trip decisions recorded, oldest first, or nil
+// unless Settings.DecisionHistory is greater than 0.
+func (cb *CircuitBreaker) Decisions() []TripDecision {
+	if cb.unprotected() {
+		return nil
+	}
+
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return append([]TripDecision(nil), cb.decisions...)
+}
+
+// decide records d, if decisions are kept. cb.mutex must be held.
+func (cb *CircuitBreaker) decide(d TripDecision) {
+	if cb.decisionHistory <= 0 {
+		return
+	}
+	if len(cb.decisions) == cb.decisionHistory {
+		cb.decisions = append(cb.decisions[:0], cb.decisions[1:]...)
+	}
+	cb.decisions = append(cb.decisions, d)
+}
@@ -0,0 +1,41 @@
+package soteria_test
+
+import (
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestDecisions(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{MinimumRequests: 3, DecisionHistory: 3})
+
+	for i := 0; i < 6; i++ {
+		fail(cb)
+	}
+
+	d := cb.Decisions()
+	if len(d) != 3 {
+		t.Fatalf("Decisions = %+v, want the last 3", d)
+	}
+	if d[0].Tripped || d[0].Skipped != "" || d[0].Stats.ConsecutiveFailures != 4 {
+		t.Errorf("first decision = %+v, want not tripped on 4 consecutive failures", d[0])
+	}
+	if !d[2].Tripped || d[2].Stats.ConsecutiveFailures != 6 {
+		t.Errorf("last decision = %+v, want tripped on 6 consecutive failures", d[2])
+	}
+}
+
+func TestDecisionsSkipped(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{MinimumRequests: 10, DecisionHistory: 5})
+	fail(cb)
+
+	if d := cb.Decisions(); len(d) != 1 || d[0].Tripped || d[0].Skipped != "minimum-requests" {
+		t.Errorf("Decisions = %+v, want ReadyToTrip skipped", d)
+	}
+
+	other, _ := newBreaker(t, soteria.Settings{})
+	fail(other)
+	if d := other.Decisions(); d != nil {
+		t.Errorf("Decisions = %+v without DecisionHistory", d)
+	}
+}
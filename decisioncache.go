@@ -0,0 +1,105 @@
+package soteria
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrSharedOpen is returned by a DecisionCache for requests to a
+// CircuitBreaker that is open in the SharedState.
+var ErrSharedOpen = fmt.Errorf("%w: open in the shared state", ErrOpenState)
+
+// SharedState holds the states of CircuitBreakers shared by the processes
+// of a service, such as in Redis, so that a breaker tripping in one
+// process rejects the requests of the others.
+type SharedState interface {
+	// LoadState returns the shared state of the CircuitBreaker called name.
+	LoadState(ctx context.Context, name string) (State, error)
+}
+
+// DecisionCache admits requests on the shared state of their
+// CircuitBreaker, caching the state of each breaker for a TTL so that most
+// requests do not need a round trip to the SharedState. A process follows
+// a change of the shared state up to the TTL late.
+//
+// Requests to a breaker open or isolated in the SharedState are rejected
+// with ErrSharedOpen. Other requests go through their CircuitBreaker,
+// which still decides on them locally.
+type DecisionCache struct {
+	// Clock tells the time of the expiry of cached states. If nil, the
+	// system clock is used.
+	Clock Clock
+
+	// OnError, if set, is called with the errors of the SharedState.
+	// Requests whose state cannot be loaded are only decided locally.
+	OnError func(name string, err error)
+
+	shared SharedState
+	ttl    time.Duration
+
+	mutex  sync.Mutex
+	states map[string]cachedState
+}
+
+type cachedState struct {
+	state   State
+	expires time.Time
+}
+
+// NewDecisionCache returns a DecisionCache caching the states loaded from
+// shared for ttl.
+func NewDecisionCache(shared SharedState, ttl time.Duration) *DecisionCache {
+	return &DecisionCache{shared: shared, ttl: ttl, states: make(map[string]cachedState)}
+}
+
+func (c *DecisionCache) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
+}
+
+// State returns the shared state of the CircuitBreaker called name, from
+// the cache unless it expired. Errors of the SharedState are not cached.
+func (c *DecisionCache) State(ctx context.Context, name string) (State, error) {
+	now := c.now()
+
+	c.mutex.Lock()
+	cached, ok := c.states[name]
+	c.mutex.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.state, nil
+	}
+
+	state, err := c.shared.LoadState(ctx, name)
+	if err != nil {
+		return StateClosed, err
+	}
+
+	c.mutex.Lock()
+	c.states[name] = cachedState{state: state, expires: now.Add(c.ttl)}
+	c.mutex.Unlock()
+	return state, nil
+}
+
+// Invalidate drops the cached state of the CircuitBreaker called name, such
+// as when the SharedState notifies a change of it.
+func (c *DecisionCache) Invalidate(name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.states, name)
+}
+
+// Execute runs req through cb, unless cb is open in the SharedState.
+func (c *DecisionCache) Execute(ctx context.Context, cb *CircuitBreaker, req func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	state, err := c.State(ctx, cb.Name())
+	if err != nil && c.OnError != nil {
+		c.OnError(cb.Name(), err)
+	}
+	if err == nil && (state == StateOpen || state == StateIsolated) {
+		return nil, ErrSharedOpen
+	}
+	return cb.ExecuteContext(ctx, req)
+}
@@ -0,0 +1,82 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+type sharedState struct {
+	state soteria.State
+	err   error
+	loads int
+}
+
+func (s *sharedState) LoadState(ctx context.Context, name string) (soteria.State, error) {
+	s.loads++
+	return s.state, s.err
+}
+
+func TestDecisionCache(t *testing.T) {
+	clock := soteriatest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	shared := &sharedState{state: soteria.StateOpen}
+	cache := soteria.NewDecisionCache(shared, time.Second)
+	cache.Clock = clock
+
+	cb := soteria.New(soteria.Settings{Name: "db"})
+	var calls int
+	execute := func() error {
+		_, err := cache.Execute(context.Background(), cb, func(ctx context.Context) (interface{}, error) {
+			calls++
+			return nil, nil
+		})
+		return err
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := execute(); !errors.Is(err, soteria.ErrOpenState) {
+			t.Fatalf("Execute while shared open = %v, want ErrOpenState", err)
+		}
+	}
+	if shared.loads != 1 || calls != 0 {
+		t.Errorf("%d loads and %d calls, want one load and no call", shared.loads, calls)
+	}
+
+	shared.state = soteria.StateClosed
+	if err := execute(); err == nil {
+		t.Error("Execute within the TTL = nil, want the cached rejection")
+	}
+	clock.Advance(time.Second)
+	if err := execute(); err != nil || calls != 1 || shared.loads != 2 {
+		t.Errorf("Execute after the TTL = %v with %d calls and %d loads", err, calls, shared.loads)
+	}
+
+	shared.state = soteria.StateOpen
+	cache.Invalidate("db")
+	if err := execute(); !errors.Is(err, soteria.ErrSharedOpen) {
+		t.Errorf("Execute after Invalidate = %v, want ErrSharedOpen", err)
+	}
+}
+
+func TestDecisionCacheError(t *testing.T) {
+	errDown := errors.New("redis down")
+	shared := &sharedState{err: errDown}
+	cache := soteria.NewDecisionCache(shared, time.Minute)
+
+	var reported error
+	cache.OnError = func(name string, err error) { reported = err }
+
+	cb := soteria.New(soteria.Settings{Name: "db"})
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Execute(context.Background(), cb, func(ctx context.Context) (interface{}, error) { return nil, nil }); err != nil {
+			t.Errorf("Execute = %v, want the local decision", err)
+		}
+	}
+	if reported != errDown || shared.loads != 2 {
+		t.Errorf("reported %v after %d loads, want errors reported and not cached", reported, shared.loads)
+	}
+}
@@ -0,0 +1,288 @@
+package soteria
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrDeferred is matched by the errors of DeferredQueue.Execute for
+	// work that was rejected and deferred, to be replayed later.
+	ErrDeferred = errors.New("deferred until the circuit breaker closes")
+	// ErrDeferredQueueFull is matched by the errors of
+	// DeferredQueue.Execute for rejected work the store had no room for.
+	ErrDeferredQueueFull = errors.New("deferred queue is full")
+)
+
+// Deferred is work, such as a write, that a DeferredQueue replays once its
+// CircuitBreaker closes if it was rejected. It is plain data so that a
+// DeferredStore can persist it.
+type Deferred struct {
+	// Key, if not empty, identifies the work: pending work of the same Key
+	// is replaced, see DeferredQueue.Dedup.
+	Key string
+	// Priority orders the replay: higher first, then by Time.
+	Priority int
+	Payload  []byte
+	// Time is when the work was first deferred, if not set by the caller.
+	Time time.Time
+}
+
+// DeferredStore holds the pending work of a DeferredQueue. It is only
+// called with the lock of the queue held. See MemoryStore.
+type DeferredStore interface {
+	// Put adds d, replacing the pending work of the same non-empty Key.
+	// It returns ErrDeferredQueueFull if there is no room for d.
+	Put(d Deferred) error
+	// Get returns the pending work of key.
+	Get(key string) (d Deferred, ok bool, err error)
+	// Take removes and returns the pending work to replay first.
+	Take() (d Deferred, ok bool, err error)
+	Len() (int, error)
+}
+
+// DeferredQueue runs work through a CircuitBreaker and defers the work it
+// rejects to a bounded DeferredStore, replaying it once the CircuitBreaker
+// closes, for write-heavy callers that would rather delay writes than
+// lose them.
+type DeferredQueue struct {
+	// Dedup, if set, is called when work is deferred with the Key of
+	// pending work, with the older and the newer of them, and returns the
+	// work to keep, such as the older one to keep the first write, or a
+	// merge of both. If Dedup is nil the newer work is kept.
+	Dedup func(pending, d Deferred) Deferred
+	// OnError, if set, is called with the replayed work that failed and
+	// its error; such work is dropped.
+	OnError func(d Deferred, err error)
+
+	cb     *CircuitBreaker
+	store  DeferredStore
+	replay func(d Deferred) error
+
+	mutex    sync.Mutex // guards store
+	draining sync.Mutex
+}
+
+// NewDeferredQueue returns a DeferredQueue running work with run through
+// cb and deferring it to store. If store is nil, a MemoryStore of 1000
+// entries is used.
+func NewDeferredQueue(cb *CircuitBreaker, store DeferredStore, run func(d Deferred) error) *DeferredQueue {
+	if store == nil {
+		store = NewMemoryStore(1000)
+	}
+	return &DeferredQueue{cb: cb, store: store, replay: run}
+}
+
+// Execute runs d through the CircuitBreaker. If the CircuitBreaker rejects
+// it, d is deferred and the rejection returned, wrapped with ErrDeferred,
+// or with ErrDeferredQueueFull if it could not be.
+func (q *DeferredQueue) Execute(d Deferred) error {
+	_, err := q.cb.Execute(func() (interface{}, error) {
+		return nil, q.replay(d)
+	})
+	if !IsRejected(err) {
+		return err
+	}
+
+	if d.Time.IsZero() {
+		d.Time = time.Now()
+	}
+	if perr := q.put(d); perr != nil {
+		if errors.Is(perr, ErrDeferredQueueFull) {
+			return fmt.Errorf("%w: %w", ErrDeferredQueueFull, err)
+		}
+		return errors.Join(err, perr)
+	}
+	return fmt.Errorf("%w: %w", ErrDeferred, err)
+}
+
+func (q *DeferredQueue) put(d Deferred) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if d.Key != "" && q.Dedup != nil {
+		pending, ok, err := q.store.Get(d.Key)
+		if err != nil {
+			return err
+		}
+		if ok {
+			d = q.Dedup(pending, d)
+		}
+	}
+	return q.store.Put(d)
+}
+
+// requeue defers d again after its replay was rejected. Work of the same
+// Key deferred in the meantime is newer than d.
+func (q *DeferredQueue) requeue(d Deferred) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if d.Key != "" {
+		pending, ok, err := q.store.Get(d.Key)
+		if err != nil {
+			return err
+		}
+		if ok {
+			if q.Dedup != nil {
+				pending = q.Dedup(d, pending)
+			}
+			return q.store.Put(pending)
+		}
+	}
+	return q.store.Put(d)
+}
+
+func (q *DeferredQueue) take() (Deferred, bool, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.store.Take()
+}
+
+// Pending returns the number of works deferred.
+func (q *DeferredQueue) Pending() (int, error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.store.Len()
+}
+
+// Drain replays the deferred work through the CircuitBreaker, in order,
+// until none is left or the CircuitBreaker rejects it again, in which case
+// the work is deferred again and the rejection returned.
+func (q *DeferredQueue) Drain() error {
+	q.draining.Lock()
+	defer q.draining.Unlock()
+
+	for {
+		d, ok, err := q.take()
+		if err != nil || !ok {
+			return err
+		}
+
+		_, err = q.cb.Execute(func() (interface{}, error) {
+			return nil, q.replay(d)
+		})
+		if IsRejected(err) {
+			if perr := q.requeue(d); perr != nil {
+				return errors.Join(err, perr)
+			}
+			return err
+		}
+		if err != nil && q.OnError != nil {
+			q.OnError(d, err)
+		}
+	}
+}
+
+// Watch drains q, on a goroutine of its own, whenever the CircuitBreaker
+// closes, until the returned function is called.
+func (q *DeferredQueue) Watch() (stop func()) {
+	s := q.cb.SubscribeTransitions(16)
+
+	go func() {
+		for e := range s.C {
+			if e.To == StateClosed {
+				q.Drain()
+			}
+		}
+	}()
+
+	return s.Close
+}
+
+// MemoryStore is a bounded in-memory DeferredStore.
+type MemoryStore struct {
+	capacity int
+	seq      uint64
+	items    deferredHeap
+	keys     map[string]*deferredItem
+}
+
+// NewMemoryStore returns a MemoryStore holding up to capacity works.
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{capacity: capacity, keys: make(map[string]*deferredItem)}
+}
+
+func (s *MemoryStore) Put(d Deferred) error {
+	if it, ok := s.keys[d.Key]; ok && d.Key != "" {
+		it.d = d
+		heap.Fix(&s.items, it.index)
+		return nil
+	}
+	if len(s.items) >= s.capacity {
+		return ErrDeferredQueueFull
+	}
+
+	s.seq++
+	it := &deferredItem{d: d, seq: s.seq}
+	heap.Push(&s.items, it)
+	if d.Key != "" {
+		s.keys[d.Key] = it
+	}
+	return nil
+}
+
+func (s *MemoryStore) Get(key string) (Deferred, bool, error) {
+	it, ok := s.keys[key]
+	if !ok {
+		return Deferred{}, false, nil
+	}
+	return it.d, true, nil
+}
+
+func (s *MemoryStore) Take() (Deferred, bool, error) {
+	if len(s.items) == 0 {
+		return Deferred{}, false, nil
+	}
+	it := heap.Pop(&s.items).(*deferredItem)
+	delete(s.keys, it.d.Key)
+	return it.d, true, nil
+}
+
+func (s *MemoryStore) Len() (int, error) {
+	return len(s.items), nil
+}
+
+type deferredItem struct {
+	d     Deferred
+	seq   uint64
+	index int
+}
+
+// deferredHeap orders the works of a MemoryStore by priority, then time,
+// then insertion.
+type deferredHeap []*deferredItem
+
+func (h deferredHeap) Len() int { return len(h) }
+
+func (h deferredHeap) Less(i, j int) bool {
+	if h[i].d.Priority != h[j].d.Priority {
+		return h[i].d.Priority > h[j].d.Priority
+	}
+	if !h[i].d.Time.Equal(h[j].d.Time) {
+		return h[i].d.Time.Before(h[j].d.Time)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h deferredHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *deferredHeap) Push(x interface{}) {
+	it := x.(*deferredItem)
+	it.index = len(*h)
+	*h = append(*h, it)
+}
+
+func (h *deferredHeap) Pop() interface{} {
+	old := *h
+	it := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return it
+}
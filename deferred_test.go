@@ -0,0 +1,72 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestDeferredQueue(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	var replayed []string
+	q := soteria.NewDeferredQueue(cb, soteria.NewMemoryStore(2), func(d soteria.Deferred) error {
+		replayed = append(replayed, d.Key+"="+string(d.Payload))
+		return nil
+	})
+	trip(cb)
+
+	for _, d := range []soteria.Deferred{
+		{Key: "a", Payload: []byte("1")},
+		{Key: "b", Payload: []byte("2"), Priority: 1},
+		{Key: "a", Payload: []byte("3")},
+	} {
+		if err := q.Execute(d); !errors.Is(err, soteria.ErrDeferred) || !errors.Is(err, soteria.ErrOpenState) {
+			t.Fatalf("Execute(%s) = %v, want it deferred", d.Key, err)
+		}
+	}
+	if err := q.Execute(soteria.Deferred{Key: "c"}); !errors.Is(err, soteria.ErrDeferredQueueFull) {
+		t.Errorf("Execute(c) = %v, want the queue full", err)
+	}
+	if n, _ := q.Pending(); n != 2 {
+		t.Errorf("Pending = %d, want a and b", n)
+	}
+
+	if err := q.Drain(); !errors.Is(err, soteria.ErrOpenState) || len(replayed) != 0 {
+		t.Errorf("Drain while open = %v after %v", err, replayed)
+	}
+
+	cb.Reset()
+	if err := q.Drain(); err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed) != 2 || replayed[0] != "b=2" || replayed[1] != "a=3" {
+		t.Errorf("replayed %v, want b first and the last write of a", replayed)
+	}
+	if n, _ := q.Pending(); n != 0 {
+		t.Errorf("Pending = %d after Drain", n)
+	}
+}
+
+func TestDeferredQueueDedup(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	var replayed []string
+	q := soteria.NewDeferredQueue(cb, nil, func(d soteria.Deferred) error {
+		replayed = append(replayed, string(d.Payload))
+		return nil
+	})
+	q.Dedup = func(pending, d soteria.Deferred) soteria.Deferred {
+		d.Payload = append(pending.Payload, d.Payload...)
+		return d
+	}
+	trip(cb)
+
+	q.Execute(soteria.Deferred{Key: "k", Payload: []byte("ab")})
+	q.Execute(soteria.Deferred{Key: "k", Payload: []byte("cd")})
+	cb.Reset()
+	q.Drain()
+
+	if len(replayed) != 1 || replayed[0] != "abcd" {
+		t.Errorf("replayed %q, want the merged work", replayed)
+	}
+}
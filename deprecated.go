@@ -0,0 +1,63 @@
+package soteria
+
+// The FSM actions below were exported before the state machine got its
+// Trip, Expire and Recover inputs. The breaker no longer calls them; they
+// are kept so existing callers still compile.
+
+// ClosedOkAction counts a successful request if the CircuitBreaker is
+// closed, as if it had gone through Execute.
+//
+// Deprecated: report outcomes through Execute and its variants.
+func (cb *CircuitBreaker) ClosedOkAction() error {
+	return cb.reportAs(StateClosed, true)
+}
+
+// HalfOpenOkAction counts a successful probe if the CircuitBreaker is
+// half-open, as if it had gone through Execute.
+//
+// Deprecated: report outcomes through Execute and its variants.
+func (cb *CircuitBreaker) HalfOpenOkAction() error {
+	return cb.reportAs(StateHalfOpen, true)
+}
+
+// ClosedNotOkAction counts a failed request if the CircuitBreaker is
+// closed, as if it had gone through Execute.
+//
+// Deprecated: report outcomes through Execute and its variants.
+func (cb *CircuitBreaker) ClosedNotOkAction() error {
+	return cb.reportAs(StateClosed, false)
+}
+
+// HalfOpenNotOkAction counts a failed probe if the CircuitBreaker is
+// half-open, as if it had gone through Execute.
+//
+// Deprecated: report outcomes through Execute and its variants.
+func (cb *CircuitBreaker) HalfOpenNotOkAction() error {
+	return cb.reportAs(StateHalfOpen, false)
+}
+
+// reportAs accounts for a request that succeeded or not, if cb is in state.
+func (cb *CircuitBreaker) reportAs(state State, success bool) error {
+	if cb.unprotected() {
+		return nil
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.clock.Now()
+	cb.currentState(now)
+	defer cb.verify(now)
+
+	if cb.state() != state {
+		return nil
+	}
+	if state == StateHalfOpen && cb.stats.Requests >= cb.maxRequests {
+		return ErrTooManyRequests
+	}
+
+	cb.stats.request()
+	if success {
+		return cb.onSuccess(now)
+	}
+	return cb.onFailure(now, CategoryOther)
+}
@@ -0,0 +1,20 @@
+package soteria
+
+import "time"
+
+// jsonDuration encodes a time.Duration as text, such as "1m30s", so that
+// durations read the same in every JSON representation.
+type jsonDuration time.Duration
+
+func (d jsonDuration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *jsonDuration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = jsonDuration(v)
+	return nil
+}
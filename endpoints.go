@@ -0,0 +1,104 @@
+package soteria
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Endpoint is an operation of an API, such as from an OpenAPI spec or a
+// service discovery client, for KeyedBreaker.Precreate.
+type Endpoint struct {
+	// Key is the key of the CircuitBreaker of the endpoint.
+	Key    string
+	Method string
+	Path   string
+	Tags   []string
+}
+
+// Discovery lists the endpoints of a service, such as a client of a
+// service registry.
+type Discovery interface {
+	Endpoints(ctx context.Context) ([]Endpoint, error)
+}
+
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// OpenAPIEndpoints returns the operations of the OpenAPI 3 or Swagger 2
+// spec read from spec, in JSON, sorted by path and method. An operation is
+// keyed by its operationId or, without one, by METHOD PATH, such as
+// "GET /users/{id}".
+func OpenAPIEndpoints(spec io.Reader) ([]Endpoint, error) {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.NewDecoder(spec).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var endpoints []Endpoint
+	for _, path := range paths {
+		for _, method := range openAPIMethods {
+			raw, ok := doc.Paths[path][method]
+			if !ok {
+				continue
+			}
+
+			var op struct {
+				OperationID string   `json:"operationId"`
+				Tags        []string `json:"tags"`
+			}
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("openapi: %s %s: %w", method, path, err)
+			}
+
+			e := Endpoint{Key: op.OperationID, Method: strings.ToUpper(method), Path: path, Tags: op.Tags}
+			if e.Key == "" {
+				e.Key = e.Method + " " + path
+			}
+			endpoints = append(endpoints, e)
+		}
+	}
+	return endpoints, nil
+}
+
+// Precreate creates the CircuitBreakers of the keys of endpoints that do not
+// have one yet, so that every endpoint is covered before its first request.
+// If template is not nil, it modifies the settings of each endpoint, which
+// are otherwise those of k.
+func (k *KeyedBreaker) Precreate(endpoints []Endpoint, template func(e Endpoint, settings *Settings)) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	for _, e := range endpoints {
+		if _, ok := k.keys[e.Key]; ok {
+			continue
+		}
+
+		settings := k.settings
+		if template != nil {
+			template(e, &settings)
+		}
+		k.keys[e.Key] = k.create(e.Key, settings)
+	}
+}
+
+// Discover precreates the CircuitBreakers of the endpoints listed by d, as
+// with Precreate.
+func (k *KeyedBreaker) Discover(ctx context.Context, d Discovery, template func(e Endpoint, settings *Settings)) error {
+	endpoints, err := d.Endpoints(ctx)
+	if err != nil {
+		return err
+	}
+	k.Precreate(endpoints, template)
+	return nil
+}
@@ -0,0 +1,81 @@
+package soteria_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+const petstore = `{
+	"openapi": "3.0.0",
+	"paths": {
+		"/pets": {
+			"get": {"operationId": "listPets", "tags": ["pets"]},
+			"post": {"operationId": "createPet", "tags": ["pets", "write"]},
+			"parameters": []
+		},
+		"/pets/{id}": {
+			"delete": {}
+		}
+	}
+}`
+
+func TestOpenAPIEndpoints(t *testing.T) {
+	endpoints, err := soteria.OpenAPIEndpoints(strings.NewReader(petstore))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for _, e := range endpoints {
+		keys = append(keys, e.Key)
+	}
+	if got := strings.Join(keys, ","); got != "listPets,createPet,DELETE /pets/{id}" {
+		t.Errorf("keys = %s", got)
+	}
+	if e := endpoints[1]; e.Method != "POST" || e.Path != "/pets" || len(e.Tags) != 2 {
+		t.Errorf("createPet = %+v", e)
+	}
+
+	if _, err := soteria.OpenAPIEndpoints(strings.NewReader("openapi: 3.0.0")); err == nil {
+		t.Error("YAML spec parsed, want an error")
+	}
+}
+
+type discovery []soteria.Endpoint
+
+func (d discovery) Endpoints(ctx context.Context) ([]soteria.Endpoint, error) {
+	return d, nil
+}
+
+func TestPrecreate(t *testing.T) {
+	k := soteria.NewKeyedBreaker(soteria.Settings{Name: "api", Timeout: time.Minute}, soteria.Quota{})
+	existing := k.Breaker("listPets")
+
+	endpoints, _ := soteria.OpenAPIEndpoints(strings.NewReader(petstore))
+	k.Precreate(endpoints, func(e soteria.Endpoint, settings *soteria.Settings) {
+		if e.Method != "GET" {
+			settings.Timeout = time.Hour
+		}
+	})
+
+	if keys := k.Keys(); len(keys) != 3 {
+		t.Errorf("Keys = %v, want one per operation", keys)
+	}
+	if k.Breaker("listPets") != existing {
+		t.Error("Precreate replaced an existing breaker")
+	}
+	if cb := k.Breaker("createPet"); cb.Name() != "api/createPet" || cb.Timeout() != time.Hour {
+		t.Errorf("createPet = %q with timeout %v, want api/createPet with the templated timeout", cb.Name(), cb.Timeout())
+	}
+
+	if err := k.Discover(context.Background(), discovery{{Key: "search"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if cb := k.Breaker("search"); cb.Timeout() != time.Minute {
+		t.Errorf("discovered timeout = %v, want the settings of the KeyedBreaker", cb.Timeout())
+	}
+}
@@ -0,0 +1,57 @@
+package soteria_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+var errUnavailable = errors.New("service unavailable")
+
+func TestErrorMapper(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{
+		Timeout: time.Minute,
+		ErrorMapper: func(err error) error {
+			if soteria.IsRejected(err) {
+				return fmt.Errorf("%w: %w", errUnavailable, err)
+			}
+			return fmt.Errorf("db: %w", err)
+		},
+	})
+
+	if err := succeed(cb); err != nil {
+		t.Errorf("Execute = %v, want nil", err)
+	}
+	if err := fail(cb); !errors.Is(err, errFail) || err.Error() != "db: fail" {
+		t.Errorf("Execute = %v, want the mapped failure", err)
+	}
+
+	soteriatest.Trip(t, cb, 10)
+	if err := succeed(cb); !errors.Is(err, errUnavailable) || !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Execute while open = %v, want errUnavailable wrapping ErrOpenState", err)
+	}
+	if _, err := cb.ExecuteAsync(func() (interface{}, error) { return nil, nil }).Get(); !errors.Is(err, errUnavailable) {
+		t.Errorf("ExecuteAsync while open = %v, want errUnavailable", err)
+	}
+	if err := cb.ReportSuccess(time.Second); !errors.Is(err, errUnavailable) {
+		t.Errorf("ReportSuccess while open = %v, want errUnavailable", err)
+	}
+}
+
+func TestErrorMapperJudgesUnmappedErrors(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{
+		IsSuccessful: func(err error) bool { return err == nil || err == errFail },
+		ErrorMapper:  func(err error) error { return errUnavailable },
+	})
+
+	if err := fail(cb); err != errUnavailable {
+		t.Errorf("Execute = %v, want errUnavailable", err)
+	}
+	if s := cb.Stats(); s.TotalSuccesses != 1 {
+		t.Errorf("Stats = %+v, want the unmapped error judged a success", s)
+	}
+}
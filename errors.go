@@ -0,0 +1,66 @@
+package soteria
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrIsolated is returned for requests rejected by a CircuitBreaker forced
+// open with ForceOpen. It wraps ErrOpenState.
+var ErrIsolated = fmt.Errorf("%w: isolated by an operator", ErrOpenState)
+
+// OpenStateError is returned for requests rejected by an open
+// CircuitBreaker. errors.Is(err, ErrOpenState) holds for it, but
+// err == ErrOpenState does not: callers comparing errors directly must
+// switch to errors.Is.
+type OpenStateError struct {
+	// Remaining is how long the CircuitBreaker stays open before becoming
+	// half-open, as of the rejection.
+	Remaining time.Duration
+}
+
+func (e *OpenStateError) Error() string {
+	return fmt.Sprintf("%s (half-open in %v)", ErrOpenState, e.Remaining)
+}
+
+func (e *OpenStateError) Is(target error) bool {
+	return target == ErrOpenState
+}
+
+// BreakerError wraps the errors a named CircuitBreaker returns of its own,
+// such as its rejections, with its name, so that the logs of a service
+// with many breakers tell which one returned them. errors.Is and errors.As
+// see through it: errors.Is(err, ErrTooManyRequests) holds for a
+// BreakerError wrapping ErrTooManyRequests.
+type BreakerError struct {
+	Breaker string
+	Err     error
+}
+
+func (e *BreakerError) Error() string {
+	return fmt.Sprintf("circuit breaker %q: %v", e.Breaker, e.Err)
+}
+
+func (e *BreakerError) Unwrap() error {
+	return e.Err
+}
+
+// named wraps err in a BreakerError if cb has a name.
+func (cb *CircuitBreaker) named(err error) error {
+	if cb.name == "" {
+		return err
+	}
+	return &BreakerError{Breaker: cb.name, Err: err}
+}
+
+// IsRejected reports whether err is a rejection of a CircuitBreaker, that
+// is matches ErrOpenState, which ErrIsolated and ErrMaintenance wrap,
+// ErrTooManyRequests, ErrDeadlineBudget or ErrShed, or ErrQuotaExceeded and
+// ErrConcurrencyLimit of a KeyedBreaker and an AdaptiveLimiter. The errors
+// of CustomState.Admit are not recognized.
+func IsRejected(err error) bool {
+	return errors.Is(err, ErrOpenState) || errors.Is(err, ErrTooManyRequests) ||
+		errors.Is(err, ErrDeadlineBudget) || errors.Is(err, ErrShed) ||
+		errors.Is(err, ErrQuotaExceeded) || errors.Is(err, ErrConcurrencyLimit)
+}
@@ -0,0 +1,98 @@
+package soteria
+
+import (
+	"context"
+	"fmt"
+)
+
+// BreakerCall is a request run by ExecuteAll through its Breaker.
+type BreakerCall struct {
+	Breaker *CircuitBreaker
+	Req     func() (interface{}, error)
+	// Critical makes a rejection of the call reject all the calls.
+	// The other calls are skipped when they are rejected.
+	Critical bool
+}
+
+// CallResult is the outcome of a BreakerCall.
+type CallResult struct {
+	Result interface{}
+	Err    error
+	// Skipped is true if the call did not run because it was rejected.
+	Skipped bool
+}
+
+// ExecuteAll admits every call through its CircuitBreaker up front, and
+// only then runs them, in order, so that a composite operation does no
+// partial work before running into an open breaker midway. If a Critical
+// call is rejected, no call runs, those already admitted are released
+// without counting, and the rejection is returned. Other rejected calls
+// are skipped, with their rejection as Err. A call that panics counts as a
+// failure, and the calls after it are released without counting.
+func ExecuteAll(calls []BreakerCall) ([]CallResult, error) {
+	results := make([]CallResult, len(calls))
+	tickets := make([]ticket, len(calls))
+	admitted := make([]bool, len(calls))
+
+	for _, c := range calls {
+		if c.Req == nil {
+			return nil, c.Breaker.errNilRequest()
+		}
+	}
+
+	for i, c := range calls {
+		if c.Breaker.unprotected() {
+			continue
+		}
+
+		t, err := c.Breaker.beforeRequest(context.Background())
+		if err == nil {
+			tickets[i], admitted[i] = t, true
+			continue
+		}
+
+		err = t.mapError(err)
+		if c.Critical {
+			for j := range calls[:i] {
+				if admitted[j] {
+					calls[j].Breaker.afterRequest(tickets[j], outcomeIgnored, nil)
+				}
+			}
+			return nil, err
+		}
+		results[i] = CallResult{Err: err, Skipped: true}
+	}
+
+	running := 0
+	defer func() {
+		if r := recover(); r != nil {
+			err := fmt.Errorf("panic: %v", r)
+			for j := running; j < len(calls); j++ {
+				if admitted[j] {
+					outcome := outcomeIgnored
+					if j == running {
+						outcome = outcomeFailure
+					}
+					calls[j].Breaker.afterRequest(tickets[j], outcome, err)
+				}
+			}
+			panic(r)
+		}
+	}()
+
+	for i, c := range calls {
+		running = i
+		if results[i].Skipped {
+			continue
+		}
+
+		result, err := c.Req()
+		if admitted[i] {
+			t := tickets[i]
+			c.Breaker.afterRequest(t, t.outcomeOf(err), err)
+			err = t.mapError(err)
+		}
+		results[i] = CallResult{Result: result, Err: err}
+	}
+	return results, nil
+}
@@ -0,0 +1,80 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestExecuteAll(t *testing.T) {
+	a, _ := newBreaker(t, soteria.Settings{})
+	b, _ := newBreaker(t, soteria.Settings{})
+	trip(b)
+
+	ran := 0
+	req := func() (interface{}, error) {
+		ran++
+		return ran, nil
+	}
+
+	results, err := soteria.ExecuteAll([]soteria.BreakerCall{
+		{Breaker: a, Req: req, Critical: true},
+		{Breaker: b, Req: req},
+		{Breaker: nil, Req: func() (interface{}, error) { return nil, errFail }},
+	})
+	if err != nil || ran != 1 {
+		t.Fatalf("ExecuteAll = %v after %d calls", err, ran)
+	}
+	if results[0].Result != 1 || results[0].Err != nil {
+		t.Errorf("critical call = %+v", results[0])
+	}
+	if !results[1].Skipped || !errors.Is(results[1].Err, soteria.ErrOpenState) {
+		t.Errorf("call to an open breaker = %+v, want it skipped", results[1])
+	}
+	if results[2].Err != errFail {
+		t.Errorf("unprotected call = %+v", results[2])
+	}
+	if s := a.Stats(); s.Requests != 1 || s.TotalSuccesses != 1 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
+
+func TestExecuteAllCriticalRejection(t *testing.T) {
+	a, _ := newBreaker(t, soteria.Settings{})
+	b, _ := newBreaker(t, soteria.Settings{})
+	trip(b)
+
+	ran := false
+	req := func() (interface{}, error) {
+		ran = true
+		return nil, nil
+	}
+
+	_, err := soteria.ExecuteAll([]soteria.BreakerCall{
+		{Breaker: a, Req: req},
+		{Breaker: b, Req: req, Critical: true},
+	})
+	if !errors.Is(err, soteria.ErrOpenState) || ran {
+		t.Errorf("ExecuteAll = %v, ran = %v, want the rejection before any call", err, ran)
+	}
+	if s := a.Stats(); s.Requests != 0 {
+		t.Errorf("Stats = %+v, want the admitted call released", s)
+	}
+}
+
+func TestExecuteAllNilRequest(t *testing.T) {
+	a, _ := newBreaker(t, soteria.Settings{})
+	b, _ := newBreaker(t, soteria.Settings{})
+
+	_, err := soteria.ExecuteAll([]soteria.BreakerCall{
+		{Breaker: a, Req: func() (interface{}, error) { return nil, nil }},
+		{Breaker: b},
+	})
+	if !errors.Is(err, soteria.ErrMisuse) {
+		t.Errorf("ExecuteAll = %v, want ErrMisuse", err)
+	}
+	if s := a.Stats(); s.Requests != 0 {
+		t.Errorf("Stats = %+v, want nothing admitted", s)
+	}
+}
@@ -0,0 +1,43 @@
+package soteria
+
+import "errors"
+
+// Backend pairs a request with the CircuitBreaker protecting it.
+type Backend struct {
+	Breaker *CircuitBreaker
+	Request func() (interface{}, error)
+}
+
+// FailoverExecutor runs a request against an ordered list of backends,
+// moving on to the next backend whenever one rejects or fails the request.
+type FailoverExecutor struct {
+	backends []Backend
+}
+
+// NewFailoverExecutor returns a FailoverExecutor preferring backends in
+// the given order.
+func NewFailoverExecutor(backends ...Backend) *FailoverExecutor {
+	return &FailoverExecutor{backends: append([]Backend(nil), backends...)}
+}
+
+// Execute returns the result of the first backend that succeeds. If all
+// of them reject or fail, it returns the errors of every backend joined,
+// so errors.Is(err, ErrOpenState) reports whether any of them was open.
+func (f *FailoverExecutor) Execute() (interface{}, error) {
+	errs := make([]error, 0, len(f.backends))
+	for _, b := range f.backends {
+		result, err := b.Breaker.Execute(b.Request)
+		if err == nil {
+			return result, nil
+		}
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil, errNoBackends
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+var errNoBackends = errors.New("no backends")
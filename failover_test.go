@@ -0,0 +1,85 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func backend(cb *soteria.CircuitBreaker, result interface{}, err error, calls *int) soteria.Backend {
+	return soteria.Backend{
+		Breaker: cb,
+		Request: func() (interface{}, error) {
+			*calls++
+			return result, err
+		},
+	}
+}
+
+func TestFailoverPrefersFirstBackend(t *testing.T) {
+	primary, _ := newBreaker(t, soteria.Settings{})
+	secondary, _ := newBreaker(t, soteria.Settings{})
+
+	var p, s int
+	f := soteria.NewFailoverExecutor(backend(primary, "primary", nil, &p), backend(secondary, "secondary", nil, &s))
+
+	got, err := f.Execute()
+	if err != nil || got != "primary" || s != 0 {
+		t.Errorf("Execute = %v, %v; secondary called %d times", got, err, s)
+	}
+}
+
+func TestFailoverMovesOnAfterFailure(t *testing.T) {
+	primary, _ := newBreaker(t, soteria.Settings{})
+	secondary, _ := newBreaker(t, soteria.Settings{})
+
+	var p, s int
+	f := soteria.NewFailoverExecutor(backend(primary, nil, errFail, &p), backend(secondary, "secondary", nil, &s))
+
+	got, err := f.Execute()
+	if err != nil || got != "secondary" {
+		t.Errorf("Execute = %v, %v", got, err)
+	}
+	if primary.Stats().TotalFailures != 1 {
+		t.Error("the primary failure was not counted")
+	}
+}
+
+func TestFailoverSkipsOpenBackend(t *testing.T) {
+	primary, _ := newBreaker(t, soteria.Settings{})
+	secondary, _ := newBreaker(t, soteria.Settings{})
+	for i := 0; i < 6; i++ {
+		fail(primary)
+	}
+
+	var p, s int
+	f := soteria.NewFailoverExecutor(backend(primary, "primary", nil, &p), backend(secondary, "secondary", nil, &s))
+
+	got, err := f.Execute()
+	if err != nil || got != "secondary" || p != 0 {
+		t.Errorf("Execute = %v, %v; open primary called %d times", got, err, p)
+	}
+}
+
+func TestFailoverJoinsErrors(t *testing.T) {
+	primary, _ := newBreaker(t, soteria.Settings{})
+	secondary, _ := newBreaker(t, soteria.Settings{})
+	for i := 0; i < 6; i++ {
+		fail(primary)
+	}
+
+	var p, s int
+	f := soteria.NewFailoverExecutor(backend(primary, nil, nil, &p), backend(secondary, nil, errFail, &s))
+
+	_, err := f.Execute()
+	if !errors.Is(err, soteria.ErrOpenState) || !errors.Is(err, errFail) {
+		t.Errorf("Execute = %v, want both ErrOpenState and the failure", err)
+	}
+}
+
+func TestFailoverWithoutBackends(t *testing.T) {
+	if _, err := soteria.NewFailoverExecutor().Execute(); err == nil {
+		t.Error("Execute without backends succeeded")
+	}
+}
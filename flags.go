@@ -0,0 +1,213 @@
+package soteria
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Flags evaluates feature flags, returning def for flags that are not
+// set. An OpenFeature client fits it with a small adapter:
+//
+//	type openFeatureFlags struct{ c *openfeature.Client }
+//
+//	func (f openFeatureFlags) Bool(ctx context.Context, flag string, def bool) bool {
+//		v, _ := f.c.BooleanValue(ctx, flag, def, openfeature.EvaluationContext{})
+//		return v
+//	}
+type Flags interface {
+	Bool(ctx context.Context, flag string, def bool) bool
+	String(ctx context.Context, flag string, def string) string
+	Float(ctx context.Context, flag string, def float64) float64
+}
+
+// FlagOverrides drives the CircuitBreakers of a Registry from feature
+// flags, so that rollouts and emergency disables go through the flag
+// tooling. The flags of a breaker are named PREFIX.NAME.FLAG, or NAME.FLAG
+// without a Prefix:
+//
+//	enabled           bool, false lets every request through uncounted
+//	state             "open" isolates the breaker, "closed" forces it closed
+//	timeout           the open Timeout, such as "30s"
+//	max_requests      MaxRequests
+//	minimum_requests  MinimumRequests
+//	failure_ratio     trips once this share of the outcomes failed
+//
+// Flags take effect when their value changes, as overrides recorded by the
+// Registry with the operator "feature-flags". Settings overridden by flags
+// return to the ones the breaker had before once the flags are unset.
+type FlagOverrides struct {
+	Registry *Registry
+	Flags    Flags
+	Prefix   string
+
+	mutex   sync.Mutex
+	applied map[string]flagValues
+	base    map[string]Settings
+}
+
+// flagValues are the flags of a breaker; unset flags are zero.
+type flagValues struct {
+	disabled        bool
+	state           string
+	timeout         time.Duration
+	maxRequests     uint32
+	minimumRequests uint32
+	failureRatio    float64
+}
+
+func (v flagValues) settings() flagValues {
+	v.state = ""
+	return v
+}
+
+const flagsOperator = "feature-flags"
+
+// Sync evaluates the flags of every registered CircuitBreaker and applies
+// those that changed since the last Sync. It returns the errors of all the
+// overrides that failed.
+func (f *FlagOverrides) Sync(ctx context.Context) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.applied == nil {
+		f.applied = make(map[string]flagValues)
+		f.base = make(map[string]Settings)
+	}
+
+	var errs []error
+	for _, name := range f.Registry.Names() {
+		v, err := f.evaluate(ctx, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		prev := f.applied[name]
+		if v.settings() != prev.settings() {
+			if err := f.applySettings(name, v); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if v.state != prev.state {
+			if err := f.applyState(name, prev.state, v.state); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		f.applied[name] = v
+	}
+	return errors.Join(errs...)
+}
+
+// Run calls Sync every interval until ctx is done. Errors are passed to
+// onError, if not nil.
+func (f *FlagOverrides) Run(ctx context.Context, interval time.Duration, onError func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.Sync(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *FlagOverrides) flag(name, flag string) string {
+	if f.Prefix == "" {
+		return name + "." + flag
+	}
+	return f.Prefix + "." + name + "." + flag
+}
+
+func (f *FlagOverrides) evaluate(ctx context.Context, name string) (flagValues, error) {
+	v := flagValues{
+		disabled:        !f.Flags.Bool(ctx, f.flag(name, "enabled"), true),
+		state:           f.Flags.String(ctx, f.flag(name, "state"), ""),
+		maxRequests:     uint32(f.Flags.Float(ctx, f.flag(name, "max_requests"), 0)),
+		minimumRequests: uint32(f.Flags.Float(ctx, f.flag(name, "minimum_requests"), 0)),
+		failureRatio:    f.Flags.Float(ctx, f.flag(name, "failure_ratio"), 0),
+	}
+
+	switch v.state {
+	case "", "open", "closed":
+	default:
+		return v, fmt.Errorf("soteria: flag %s: unknown state %q", f.flag(name, "state"), v.state)
+	}
+
+	if timeout := f.Flags.String(ctx, f.flag(name, "timeout"), ""); timeout != "" {
+		var err error
+		if v.timeout, err = time.ParseDuration(timeout); err != nil {
+			return v, fmt.Errorf("soteria: flag %s: %w", f.flag(name, "timeout"), err)
+		}
+	}
+	return v, nil
+}
+
+func (f *FlagOverrides) applySettings(name string, v flagValues) error {
+	return f.Registry.ModifySettings(name, func(settings *Settings) {
+		base, ok := f.base[name]
+		if !ok {
+			base = *settings
+			f.base[name] = base
+		}
+		*settings = base
+
+		if v.disabled {
+			settings.Maintenance = append([]MaintenanceWindow{{Schedule: always{}, Mode: MaintenanceObserve}}, settings.Maintenance...)
+		}
+		if v.timeout > 0 {
+			settings.Timeout = v.timeout
+		}
+		if v.maxRequests > 0 {
+			settings.MaxRequests = v.maxRequests
+		}
+		if v.minimumRequests > 0 {
+			settings.MinimumRequests = v.minimumRequests
+		}
+		if v.failureRatio > 0 {
+			settings.ReadyToTrip = failureRatio(v.failureRatio)
+		}
+
+		if v.settings() == (flagValues{}) {
+			delete(f.base, name)
+		}
+	}, Override{Operator: flagsOperator, Reason: "flags " + f.flag(name, "*")})
+}
+
+func (f *FlagOverrides) applyState(name, prev, state string) error {
+	o := Override{Operator: flagsOperator, Reason: fmt.Sprintf("flag %s = %q", f.flag(name, "state"), state)}
+	switch state {
+	case "open":
+		return f.Registry.ForceState(name, StateOpen, o)
+	case "closed":
+		return f.Registry.ForceState(name, StateClosed, o)
+	}
+	if prev == "open" {
+		return f.Registry.Reset(name, o)
+	}
+	return nil
+}
+
+// always is a Schedule containing every point in time.
+type always struct{}
+
+func (always) Contains(t time.Time) bool {
+	return true
+}
+
+// failureRatio returns a ReadyToTrip tripping once ratio of the outcomes
+// failed.
+func failureRatio(ratio float64) func(stats Stats) bool {
+	return func(stats Stats) bool {
+		n := stats.TotalSuccesses + stats.TotalFailures
+		return n > 0 && float64(stats.TotalFailures)/float64(n) >= ratio
+	}
+}
@@ -0,0 +1,139 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+type fakeFlags map[string]interface{}
+
+func (f fakeFlags) Bool(ctx context.Context, flag string, def bool) bool {
+	if v, ok := f[flag].(bool); ok {
+		return v
+	}
+	return def
+}
+
+func (f fakeFlags) String(ctx context.Context, flag string, def string) string {
+	if v, ok := f[flag].(string); ok {
+		return v
+	}
+	return def
+}
+
+func (f fakeFlags) Float(ctx context.Context, flag string, def float64) float64 {
+	if v, ok := f[flag].(float64); ok {
+		return v
+	}
+	return def
+}
+
+func newFlagOverrides(t *testing.T) (*soteria.FlagOverrides, fakeFlags, *soteria.CircuitBreaker) {
+	t.Helper()
+	cb, _ := newBreaker(t, soteria.Settings{Name: "db", Timeout: time.Minute})
+	r := soteria.NewRegistry()
+	r.Add(cb)
+	r.SetAuditLog(soteria.NewMemoryAuditLog(16))
+
+	flags := fakeFlags{}
+	return &soteria.FlagOverrides{Registry: r, Flags: flags, Prefix: "breakers"}, flags, cb
+}
+
+func TestFlagOverridesState(t *testing.T) {
+	f, flags, cb := newFlagOverrides(t)
+
+	flags["breakers.db.state"] = "open"
+	if err := f.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := succeed(cb); !errors.Is(err, soteria.ErrIsolated) {
+		t.Errorf("Execute with state=open = %v, want ErrIsolated", err)
+	}
+
+	delete(flags, "breakers.db.state")
+	if err := f.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	soteriatest.AssertClosed(t, cb)
+
+	entries, _ := f.Registry.AuditLog().Entries("db", 0)
+	if len(entries) != 2 || entries[0].Action != soteria.ActionForceOpen || entries[1].Action != soteria.ActionReset {
+		t.Fatalf("audit entries = %+v, want force open and reset", entries)
+	}
+	if entries[0].Operator != "feature-flags" || entries[0].Reason != `flag breakers.db.state = "open"` {
+		t.Errorf("audit entry = %+v", entries[0])
+	}
+}
+
+func TestFlagOverridesEdgeTriggered(t *testing.T) {
+	f, flags, cb := newFlagOverrides(t)
+
+	flags["breakers.db.state"] = "open"
+	f.Sync(context.Background())
+	cb.ForceClose()
+
+	if err := f.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestFlagOverridesDisabled(t *testing.T) {
+	f, flags, cb := newFlagOverrides(t)
+
+	flags["breakers.db.enabled"] = false
+	if err := f.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		fail(cb)
+	}
+	soteriatest.AssertClosed(t, cb)
+
+	flags["breakers.db.enabled"] = true
+	if err := f.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	soteriatest.Trip(t, cb, 10)
+}
+
+func TestFlagOverridesThresholds(t *testing.T) {
+	f, flags, cb := newFlagOverrides(t)
+
+	flags["breakers.db.failure_ratio"] = 0.5
+	if err := f.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	succeed(cb)
+	fail(cb)
+	soteriatest.AssertOpen(t, cb)
+
+	delete(flags, "breakers.db.failure_ratio")
+	if err := f.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	cb.Reset()
+	succeed(cb)
+	fail(cb)
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestFlagOverridesInvalid(t *testing.T) {
+	f, flags, _ := newFlagOverrides(t)
+
+	flags["breakers.db.timeout"] = "soon"
+	if err := f.Sync(context.Background()); err == nil {
+		t.Error("Sync with an invalid timeout succeeded")
+	}
+
+	flags["breakers.db.timeout"] = "1s"
+	flags["breakers.db.state"] = "ajar"
+	if err := f.Sync(context.Background()); err == nil {
+		t.Error("Sync with an unknown state succeeded")
+	}
+}
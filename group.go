@@ -0,0 +1,36 @@
+package soteria
+
+// Group runs functions concurrently and collects the first error, as
+// *errgroup.Group of golang.org/x/sync does.
+type Group interface {
+	Go(fn func() error)
+}
+
+// Go runs fn on group through the CircuitBreaker. A rejection fails the
+// member, and so the group, as an error of fn would. See GoSkip for
+// fan-out workloads that can do without some of their members.
+func (cb *CircuitBreaker) Go(group Group, fn func() error) {
+	cb.goGroup(group, fn, false, nil)
+}
+
+// GoSkip is like Go, but a member rejected by the CircuitBreaker is
+// skipped: it succeeds without running fn, so that a fan-out carries on
+// with the members whose dependency is healthy. onSkip, if not nil, is
+// called with the rejection.
+func (cb *CircuitBreaker) GoSkip(group Group, fn func() error, onSkip func(err error)) {
+	cb.goGroup(group, fn, true, onSkip)
+}
+
+func (cb *CircuitBreaker) goGroup(group Group, fn func() error, skip bool, onSkip func(err error)) {
+	group.Go(func() error {
+		err := cb.Run(fn)
+		if !skip || !IsRejected(err) {
+			return err
+		}
+
+		if onSkip != nil {
+			onSkip(err)
+		}
+		return nil
+	})
+}
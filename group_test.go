@@ -0,0 +1,51 @@
+package soteria_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/jtejido/soteria"
+)
+
+var _ soteria.Group = (*errgroup.Group)(nil)
+
+func TestGoFailsOnRejection(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	trip(cb)
+
+	var g errgroup.Group
+	cb.Go(&g, func() error { return nil })
+	if err := g.Wait(); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Wait = %v, want the rejection", err)
+	}
+}
+
+func TestGoSkipsRejections(t *testing.T) {
+	healthy, _ := newBreaker(t, soteria.Settings{})
+	broken, _ := newBreaker(t, soteria.Settings{})
+	trip(broken)
+
+	var (
+		g            errgroup.Group
+		ran, skipped int32
+	)
+	onSkip := func(err error) { atomic.AddInt32(&skipped, 1) }
+	for _, cb := range []*soteria.CircuitBreaker{healthy, broken, healthy} {
+		cb.GoSkip(&g, func() error {
+			atomic.AddInt32(&ran, 1)
+			return nil
+		}, onSkip)
+	}
+
+	if err := g.Wait(); err != nil || ran != 2 || skipped != 1 {
+		t.Errorf("Wait = %v, %d ran, %d skipped", err, ran, skipped)
+	}
+
+	healthy.GoSkip(&g, func() error { return errFail }, nil)
+	if err := g.Wait(); err != errFail {
+		t.Errorf("Wait = %v, want the error of a member that ran", err)
+	}
+}
@@ -0,0 +1,76 @@
+package soteria_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestHalfOpenTimeoutReopens(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{HalfOpenTimeout: 10 * time.Second, MaxRequests: 3})
+	trip(cb)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	succeed(cb)
+	clock.Advance(9 * time.Second)
+	soteriatest.AssertHalfOpen(t, cb)
+
+	clock.Advance(time.Second)
+	soteriatest.AssertOpen(t, cb)
+	if got := cb.TripRate().Total; got != 2 {
+		t.Errorf("TripRate.Total = %d, want the half-open timeout counted as a trip", got)
+	}
+}
+
+func TestHalfOpenTimeoutCloses(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{HalfOpenTimeout: 10 * time.Second, HalfOpenTimeoutCloses: true})
+	trip(cb)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	clock.Advance(10 * time.Second)
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestHalfOpenWithoutTimeoutLingers(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{})
+	trip(cb)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	clock.Advance(24 * time.Hour)
+	soteriatest.AssertHalfOpen(t, cb)
+}
+
+func TestPanickingProbeReopens(t *testing.T) {
+	for name, execute := range map[string]func(cb *soteria.CircuitBreaker, req func() (interface{}, error)){
+		"Execute": func(cb *soteria.CircuitBreaker, req func() (interface{}, error)) {
+			cb.Execute(req)
+		},
+		"ExecuteContext": func(cb *soteria.CircuitBreaker, req func() (interface{}, error)) {
+			cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) { return req() })
+		},
+		"ExecuteAll": func(cb *soteria.CircuitBreaker, req func() (interface{}, error)) {
+			soteria.ExecuteAll([]soteria.BreakerCall{{Breaker: cb, Req: req}})
+		},
+	} {
+		cb, clock := newBreaker(t, soteria.Settings{})
+		trip(cb)
+		soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+		func() {
+			defer func() {
+				if r := recover(); r != "boom" {
+					t.Errorf("%s recovered %v, want the panic of the request", name, r)
+				}
+			}()
+			execute(cb, func() (interface{}, error) { panic("boom") })
+		}()
+
+		soteriatest.AssertOpen(t, cb)
+		if s := cb.Stats(); s.Requests != 0 {
+			t.Errorf("%s: Stats = %+v, want the probe settled", name, s)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package soteria
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// StatusClassifier reports whether an HTTP status code counts as a failure.
+type StatusClassifier func(code int) bool
+
+// ServerErrors classifies every 5xx status code as a failure.
+var ServerErrors = HTTPStatusRange(500, 599)
+
+// HTTPStatusClassifier classifies exactly codes as failures.
+func HTTPStatusClassifier(codes ...int) StatusClassifier {
+	failures := make(map[int]bool, len(codes))
+	for _, code := range codes {
+		failures[code] = true
+	}
+
+	return func(code int) bool {
+		return failures[code]
+	}
+}
+
+// HTTPStatusRange classifies the status codes from min to max inclusive as failures.
+func HTTPStatusRange(min, max int) StatusClassifier {
+	return func(code int) bool {
+		return code >= min && code <= max
+	}
+}
+
+// Treat429AsFailure extends c to also classify 429 Too Many Requests as a failure.
+func Treat429AsFailure(c StatusClassifier) StatusClassifier {
+	return c.Or(HTTPStatusClassifier(http.StatusTooManyRequests))
+}
+
+// Or classifies a status code as a failure if c or o does.
+func (c StatusClassifier) Or(o StatusClassifier) StatusClassifier {
+	return func(code int) bool {
+		return c(code) || o(code)
+	}
+}
+
+// IsSuccessful can be used as Settings.IsSuccessful. A nil error is a
+// success, a *StatusError is judged by its status code, and any other
+// error is a failure.
+func (c StatusClassifier) IsSuccessful(err error) bool {
+	if err == nil {
+		return true
+	}
+
+	var se *StatusError
+	if errors.As(err, &se) {
+		return !c(se.Code)
+	}
+
+	return false
+}
+
+// StatusCategory categorizes a *StatusError with a 5xx code as
+// CategoryServer, and nothing else.
+func StatusCategory(err error) Category {
+	var se *StatusError
+	if errors.As(err, &se) && se.Code >= 500 && se.Code <= 599 {
+		return CategoryServer
+	}
+	return ""
+}
+
+// StatusError carries the status of an HTTP response through a
+// CircuitBreaker, so that it can be classified.
+type StatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("http status %s", e.Status)
+}
+
+// RoundTripper is an http.RoundTripper sending requests through a
+// CircuitBreaker. Transport errors and responses whose status Classifier
+// reports as a failure count as failures; responses are still returned
+// to the caller either way.
+//
+// Requests rejected by the CircuitBreaker fail with an error matching
+// ErrOpenState or ErrTooManyRequests without reaching Next.
+type RoundTripper struct {
+	Breaker *CircuitBreaker
+
+	// Next performs the requests. If nil, http.DefaultTransport is used.
+	Next http.RoundTripper
+
+	// Classifier decides on the status code. If nil, ServerErrors is used.
+	Classifier StatusClassifier
+
+	// ProbeHeader, if not empty, names a header set to "1" on the
+	// requests admitted as half-open probes, such as the ProbeHeader
+	// constant, so that the server can tell them apart.
+	ProbeHeader string
+}
+
+// ProbeHeader is the conventional header marking half-open probes, for
+// RoundTripper.ProbeHeader.
+const ProbeHeader = "Soteria-Probe"
+
+func (t *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	classifier := t.Classifier
+	if classifier == nil {
+		classifier = ServerErrors
+	}
+
+	var resp *http.Response
+	ctx := context.WithValue(req.Context(), requestKey{}, req)
+	_, err := t.Breaker.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		out := req
+		if t.ProbeHeader != "" && IsProbe(ctx) {
+			out = req.Clone(req.Context())
+			out.Header.Set(t.ProbeHeader, "1")
+		}
+
+		var err error
+		resp, err = next.RoundTrip(out)
+		if err != nil {
+			return nil, err
+		}
+
+		if classifier(resp.StatusCode) {
+			return nil, &StatusError{Code: resp.StatusCode, Status: resp.Status}
+		}
+
+		return nil, nil
+	})
+
+	var se *StatusError
+	if errors.As(err, &se) && resp != nil {
+		return resp, nil
+	}
+
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+type requestKey struct{}
+
+// RequestFromContext returns the request a RoundTripper is sending, from
+// the context it passes to the CircuitBreaker, such as in AllowProbe.
+func RequestFromContext(ctx context.Context) (*http.Request, bool) {
+	req, ok := ctx.Value(requestKey{}).(*http.Request)
+	return req, ok
+}
+
+// SafeProbes can be used as Settings.AllowProbe to only let requests with
+// a safe HTTP method (GET, HEAD, OPTIONS, TRACE) probe a half-open
+// CircuitBreaker behind a RoundTripper, so that a probe never changes
+// anything on a backend that may still be unhealthy.
+func SafeProbes(ctx context.Context) bool {
+	req, ok := RequestFromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
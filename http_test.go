@@ -0,0 +1,168 @@
+package soteria_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// respond answers every request with code, counting the requests in n.
+func respond(code int, n *int) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		*n++
+		return &http.Response{
+			StatusCode: code,
+			Status:     fmt.Sprintf("%d %s", code, http.StatusText(code)),
+			Body:       io.NopCloser(strings.NewReader("")),
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestStatusClassifiers(t *testing.T) {
+	only503 := soteria.HTTPStatusClassifier(503)
+	with429 := soteria.Treat429AsFailure(soteria.ServerErrors)
+
+	for _, c := range []struct {
+		name       string
+		classifier soteria.StatusClassifier
+		code       int
+		want       bool
+	}{
+		{"only503", only503, 503, true},
+		{"only503", only503, 500, false},
+		{"ServerErrors", soteria.ServerErrors, 599, true},
+		{"ServerErrors", soteria.ServerErrors, 404, false},
+		{"with429", with429, 429, true},
+		{"with429", with429, 502, true},
+		{"with429", with429, 200, false},
+	} {
+		if got := c.classifier(c.code); got != c.want {
+			t.Errorf("%s(%d) = %v, want %v", c.name, c.code, got, c.want)
+		}
+	}
+}
+
+func TestStatusClassifierIsSuccessful(t *testing.T) {
+	is := soteria.ServerErrors.IsSuccessful
+
+	if !is(nil) {
+		t.Error("nil error is a failure")
+	}
+	if !is(fmt.Errorf("wrapped: %w", &soteria.StatusError{Code: 404})) {
+		t.Error("404 is a failure")
+	}
+	if is(&soteria.StatusError{Code: 500}) {
+		t.Error("500 is a success")
+	}
+	if is(errors.New("transport")) {
+		t.Error("a transport error is a success")
+	}
+}
+
+func TestRoundTripperCountsFailedResponses(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	var n int
+	client := &http.Client{Transport: &soteria.RoundTripper{Breaker: cb, Next: respond(503, &n)}}
+
+	resp, err := client.Get("http://backend/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 503 {
+		t.Errorf("StatusCode = %d, want the response returned anyway", resp.StatusCode)
+	}
+	if got := cb.Stats().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %d, want 1", got)
+	}
+}
+
+func TestRoundTripperRejectsWhenOpen(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	var n int
+	client := &http.Client{Transport: &soteria.RoundTripper{Breaker: cb, Next: respond(500, &n)}}
+	for i := 0; i < 6; i++ {
+		resp, err := client.Get("http://backend/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	_, err := client.Get("http://backend/")
+	if !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Get = %v, want ErrOpenState", err)
+	}
+	if n != 6 {
+		t.Errorf("Next saw %d requests, want 6", n)
+	}
+}
+
+func TestRoundTripperClassifier(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	var n int
+	client := &http.Client{Transport: &soteria.RoundTripper{
+		Breaker:    cb,
+		Next:       respond(429, &n),
+		Classifier: soteria.HTTPStatusClassifier(429),
+	}}
+	resp, err := client.Get("http://backend/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got := cb.Stats().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %d, want 1", got)
+	}
+}
+
+func TestRoundTripperProbeHeader(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{})
+
+	var probes []string
+	rt := &soteria.RoundTripper{
+		Breaker:     cb,
+		ProbeHeader: soteria.ProbeHeader,
+		Next: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			probes = append(probes, req.Header.Get(soteria.ProbeHeader))
+			return &http.Response{StatusCode: 200, Body: http.NoBody}, nil
+		}),
+	}
+	send := func() {
+		req, _ := http.NewRequest(http.MethodGet, "http://backend/", nil)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if req.Header.Get(soteria.ProbeHeader) != "" {
+			t.Error("RoundTrip modified the request")
+		}
+	}
+
+	send()
+	trip(cb)
+	clock.Advance(cb.Timeout())
+	send()
+
+	if got := strings.Join(probes, " "); got != " 1" {
+		t.Errorf("probe headers = %q, want only the probe marked", got)
+	}
+}
@@ -0,0 +1,104 @@
+package soteria
+
+import (
+	"net/http"
+	"time"
+)
+
+// HTTPClientOption configures NewHTTPClient.
+type HTTPClientOption func(o *httpClientOptions)
+
+type httpClientOptions struct {
+	perHost    bool
+	registry   *Registry
+	classifier StatusClassifier
+	timeout    time.Duration
+	next       http.RoundTripper
+	probe      string
+}
+
+// PerHost gives every host its own CircuitBreaker, named Settings.Name/HOST,
+// so that one failing host does not reject the requests to the others.
+// The breakers are registered in registry; if nil, in a Registry of their
+// own.
+func PerHost(registry *Registry) HTTPClientOption {
+	return func(o *httpClientOptions) {
+		o.perHost = true
+		o.registry = registry
+	}
+}
+
+// WithStatusClassifier classifies responses with c rather than
+// ServerErrors.
+func WithStatusClassifier(c StatusClassifier) HTTPClientOption {
+	return func(o *httpClientOptions) {
+		o.classifier = c
+	}
+}
+
+// WithExecutionTimeout bounds every request, including reading the body of
+// the response, to d, as http.Client.Timeout does. A request timing out
+// before the response arrives counts as a failure, unless
+// Settings.IgnoreCallerCancellation is set.
+func WithExecutionTimeout(d time.Duration) HTTPClientOption {
+	return func(o *httpClientOptions) {
+		o.timeout = d
+	}
+}
+
+// WithTransport sends the requests with next rather than
+// http.DefaultTransport.
+func WithTransport(next http.RoundTripper) HTTPClientOption {
+	return func(o *httpClientOptions) {
+		o.next = next
+	}
+}
+
+// WithProbeHeader marks the half-open probes with header, as with
+// RoundTripper.ProbeHeader.
+func WithProbeHeader(header string) HTTPClientOption {
+	return func(o *httpClientOptions) {
+		o.probe = header
+	}
+}
+
+// NewHTTPClient returns an http.Client sending its requests through a
+// RoundTripper guarded by a CircuitBreaker built from settings, or one per
+// host with PerHost.
+func NewHTTPClient(settings Settings, opts ...HTTPClientOption) *http.Client {
+	var o httpClientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var rt http.RoundTripper
+	if o.perHost {
+		registry := o.registry
+		if registry == nil {
+			registry = NewRegistry()
+		}
+		rt = &hostTransport{settings: settings, registry: registry, next: o.next, classifier: o.classifier, probe: o.probe}
+	} else {
+		rt = &RoundTripper{Breaker: New(settings), Next: o.next, Classifier: o.classifier, ProbeHeader: o.probe}
+	}
+	return &http.Client{Transport: rt, Timeout: o.timeout}
+}
+
+// hostTransport is a RoundTripper with a CircuitBreaker per host.
+type hostTransport struct {
+	settings   Settings
+	registry   *Registry
+	next       http.RoundTripper
+	classifier StatusClassifier
+	probe      string
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	name := req.URL.Host
+	if t.settings.Name != "" {
+		name = t.settings.Name + "/" + name
+	}
+
+	rt := RoundTripper{Breaker: t.registry.GetOrCreate(name, t.settings), Next: t.next, Classifier: t.classifier, ProbeHeader: t.probe}
+	return rt.RoundTrip(req)
+}
@@ -0,0 +1,79 @@
+package soteria_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := soteria.NewHTTPClient(soteria.Settings{}, soteria.WithStatusClassifier(soteria.HTTPStatusClassifier(http.StatusBadGateway)))
+	for i := 0; i < 6; i++ {
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if _, err := client.Get(srv.URL); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Get once tripped = %v, want ErrOpenState", err)
+	}
+}
+
+func TestNewHTTPClientPerHost(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer healthy.Close()
+
+	r := soteria.NewRegistry()
+	client := soteria.NewHTTPClient(soteria.Settings{Name: "api"}, soteria.PerHost(r))
+	for i := 0; i < 7; i++ {
+		if resp, err := client.Get(failing.URL); err == nil {
+			resp.Body.Close()
+		}
+	}
+
+	resp, err := client.Get(healthy.URL)
+	if err != nil {
+		t.Fatalf("Get to a healthy host = %v", err)
+	}
+	resp.Body.Close()
+
+	cb, ok := r.Get("api/" + failing.Listener.Addr().String())
+	if !ok || cb.State() != soteria.StateOpen || len(r.Names()) != 2 {
+		t.Errorf("breakers %v, failing host open: %v", r.Names(), ok && cb.State() == soteria.StateOpen)
+	}
+}
+
+func TestNewHTTPClientExecutionTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	r := soteria.NewRegistry()
+	client := soteria.NewHTTPClient(soteria.Settings{}, soteria.PerHost(r), soteria.WithExecutionTimeout(10*time.Millisecond))
+	if _, err := client.Get(srv.URL); err == nil {
+		t.Fatal("Get outliving the timeout succeeded")
+	}
+	if s := r.Breakers()[0].Stats(); s.TotalFailures != 1 {
+		t.Errorf("Stats = %+v, want the timeout counted as a failure", s)
+	}
+}
@@ -0,0 +1,180 @@
+package soteria
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Defaults of hystrix-go, applied to zero fields of CommandConfig.
+const (
+	DefaultHystrixTimeout                = 1000
+	DefaultHystrixMaxConcurrentRequests  = 10
+	DefaultHystrixRequestVolumeThreshold = 20
+	DefaultHystrixSleepWindow            = 5000
+	DefaultHystrixErrorPercentThreshold  = 50
+
+	// hystrix-go evaluates health over a rolling window of 10 seconds
+	hystrixWindow = 10 * time.Second
+)
+
+// ErrHystrixTimeout and ErrHystrixMaxConcurrency are returned wrapped in a
+// BreakerError naming the command.
+var (
+	// ErrHystrixTimeout is returned by Go and Do when run outlives the
+	// Timeout of its command.
+	ErrHystrixTimeout = errors.New("command timed out")
+	// ErrHystrixMaxConcurrency is returned by Go and Do when the command
+	// already runs MaxConcurrentRequests requests.
+	ErrHystrixMaxConcurrency = errors.New("max concurrency")
+)
+
+// CommandConfig mirrors hystrix.CommandConfig of hystrix-go, so existing
+// configuration can be carried over field by field.
+//
+// Timeout and SleepWindow are in milliseconds; ErrorPercentThreshold is
+// a percentage of the requests in the 10 second window.
+type CommandConfig struct {
+	Timeout                int `json:"timeout"`
+	MaxConcurrentRequests  int `json:"max_concurrent_requests"`
+	RequestVolumeThreshold int `json:"request_volume_threshold"`
+	SleepWindow            int `json:"sleep_window"`
+	ErrorPercentThreshold  int `json:"error_percent_threshold"`
+}
+
+func (c CommandConfig) withDefaults() CommandConfig {
+	if c.Timeout == 0 {
+		c.Timeout = DefaultHystrixTimeout
+	}
+	if c.MaxConcurrentRequests == 0 {
+		c.MaxConcurrentRequests = DefaultHystrixMaxConcurrentRequests
+	}
+	if c.RequestVolumeThreshold == 0 {
+		c.RequestVolumeThreshold = DefaultHystrixRequestVolumeThreshold
+	}
+	if c.SleepWindow == 0 {
+		c.SleepWindow = DefaultHystrixSleepWindow
+	}
+	if c.ErrorPercentThreshold == 0 {
+		c.ErrorPercentThreshold = DefaultHystrixErrorPercentThreshold
+	}
+	return c
+}
+
+// HystrixSettings maps a hystrix-go command configuration onto Settings.
+// The breaker trips once RequestVolumeThreshold requests completed within
+// the last 10 seconds, a rolling window of Settings.Windows, and at least
+// ErrorPercentThreshold percent of them failed.
+// Timeout and MaxConcurrentRequests have no Settings equivalent; they are
+// enforced by Go and Do.
+func HystrixSettings(name string, config CommandConfig) Settings {
+	config = config.withDefaults()
+	volume := uint64(config.RequestVolumeThreshold)
+	percent := uint64(config.ErrorPercentThreshold)
+
+	return Settings{
+		Name:    name,
+		Windows: []time.Duration{hystrixWindow},
+		Timeout: time.Duration(config.SleepWindow) * time.Millisecond,
+		ReadyToTrip: func(stats Stats) bool {
+			w, _ := stats.Window(hystrixWindow)
+			completed := uint64(w.Requests())
+			return completed >= volume && uint64(w.Failures)*100 >= completed*percent
+		},
+	}
+}
+
+type command struct {
+	cb      *CircuitBreaker
+	timeout time.Duration
+	tickets chan struct{}
+}
+
+var (
+	commandsMutex sync.RWMutex
+	commands      = make(map[string]*command)
+)
+
+// ConfigureCommand applies config to the named command, replacing its
+// breaker, like hystrix.ConfigureCommand.
+func ConfigureCommand(name string, config CommandConfig) {
+	commandsMutex.Lock()
+	defer commandsMutex.Unlock()
+	commands[name] = newCommand(name, config)
+}
+
+func newCommand(name string, config CommandConfig) *command {
+	config = config.withDefaults()
+	return &command{
+		cb:      New(HystrixSettings(name, config)),
+		timeout: time.Duration(config.Timeout) * time.Millisecond,
+		tickets: make(chan struct{}, config.MaxConcurrentRequests),
+	}
+}
+
+func getCommand(name string) *command {
+	commandsMutex.RLock()
+	c, ok := commands[name]
+	commandsMutex.RUnlock()
+	if ok {
+		return c
+	}
+
+	commandsMutex.Lock()
+	defer commandsMutex.Unlock()
+	if c, ok = commands[name]; !ok {
+		c = newCommand(name, CommandConfig{})
+		commands[name] = c
+	}
+	return c
+}
+
+// Go runs run asynchronously under the breaker of the named command, the
+// way hystrix.Go does. If run fails, times out, or is rejected, fallback
+// is called with the error when not nil. The returned channel receives
+// the final error, if any, and is never closed.
+func Go(name string, run func() error, fallback func(error) error) chan error {
+	errs := make(chan error, 1)
+	go func() {
+		if err := Do(name, run, fallback); err != nil {
+			errs <- err
+		}
+	}()
+	return errs
+}
+
+// Do is the synchronous counterpart of Go, like hystrix.Do.
+func Do(name string, run func() error, fallback func(error) error) error {
+	err := getCommand(name).do(run)
+	if err != nil && fallback != nil {
+		return fallback(err)
+	}
+	return err
+}
+
+func (c *command) do(run func() error) error {
+	select {
+	case c.tickets <- struct{}{}:
+		defer func() { <-c.tickets }()
+	default:
+		return c.cb.named(ErrHystrixMaxConcurrency)
+	}
+
+	_, err := c.cb.Execute(func() (interface{}, error) {
+		done := make(chan error, 1)
+		go func() {
+			done <- run()
+		}()
+
+		timer := time.NewTimer(c.timeout)
+		defer timer.Stop()
+
+		select {
+		case err := <-done:
+			return nil, err
+		case <-timer.C:
+			return nil, c.cb.named(ErrHystrixTimeout)
+		}
+	})
+	return err
+}
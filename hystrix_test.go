@@ -0,0 +1,119 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestHystrixSettingsDefaults(t *testing.T) {
+	settings := soteria.HystrixSettings("h", soteria.CommandConfig{})
+	if settings.Timeout.Milliseconds() != soteria.DefaultHystrixSleepWindow {
+		t.Errorf("Timeout = %v, want the default sleep window", settings.Timeout)
+	}
+
+	window := func(requests, successes, failures uint32) soteria.Stats {
+		return soteria.Stats{
+			Requests: requests,
+			Windows:  []soteria.WindowStats{{Window: 10 * time.Second, Successes: successes, Failures: failures}},
+		}
+	}
+
+	if settings.ReadyToTrip(window(40, 0, 19)) {
+		t.Error("tripped below the request volume threshold, counting requests in flight")
+	}
+	if settings.ReadyToTrip(window(20, 11, 9)) {
+		t.Error("tripped below the error percent threshold")
+	}
+	if !settings.ReadyToTrip(window(20, 10, 10)) {
+		t.Error("did not trip at the error percent threshold")
+	}
+	if settings.ReadyToTrip(window(0, 50_000_000, 40_000_000)) {
+		t.Error("tripped below the error percent threshold on large counts")
+	}
+}
+
+func TestHystrixSettingsRollingWindow(t *testing.T) {
+	settings := soteria.HystrixSettings("h", soteria.CommandConfig{RequestVolumeThreshold: 4})
+	cb, clock := newBreaker(t, settings)
+
+	clock.Advance(8 * time.Second)
+	fail(cb)
+	fail(cb)
+	clock.Advance(4 * time.Second)
+	fail(cb)
+	soteriatest.AssertClosed(t, cb)
+	fail(cb)
+	soteriatest.AssertOpen(t, cb)
+}
+
+func TestDoFallback(t *testing.T) {
+	soteria.ConfigureCommand(t.Name(), soteria.CommandConfig{})
+
+	var got error
+	err := soteria.Do(t.Name(), func() error {
+		return errFail
+	}, func(err error) error {
+		got = err
+		return nil
+	})
+	if err != nil || got != errFail {
+		t.Errorf("Do = %v, fallback got %v", err, got)
+	}
+}
+
+func TestDoTimeout(t *testing.T) {
+	soteria.ConfigureCommand(t.Name(), soteria.CommandConfig{Timeout: 1})
+
+	release := make(chan struct{})
+	defer close(release)
+
+	err := soteria.Do(t.Name(), func() error {
+		<-release
+		return nil
+	}, nil)
+	if !errors.Is(err, soteria.ErrHystrixTimeout) {
+		t.Errorf("Do = %v, want ErrHystrixTimeout", err)
+	}
+}
+
+func TestDoMaxConcurrency(t *testing.T) {
+	soteria.ConfigureCommand(t.Name(), soteria.CommandConfig{MaxConcurrentRequests: 1})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	errs := soteria.Go(t.Name(), func() error {
+		close(started)
+		<-release
+		return nil
+	}, nil)
+	<-started
+
+	err := soteria.Do(t.Name(), func() error { return nil }, nil)
+	if !errors.Is(err, soteria.ErrHystrixMaxConcurrency) {
+		t.Errorf("Do = %v, want ErrHystrixMaxConcurrency", err)
+	}
+
+	close(release)
+	select {
+	case err := <-errs:
+		t.Errorf("Go = %v, want no error", err)
+	default:
+	}
+}
+
+func TestDoTrips(t *testing.T) {
+	soteria.ConfigureCommand(t.Name(), soteria.CommandConfig{RequestVolumeThreshold: 4})
+
+	for i := 0; i < 4; i++ {
+		soteria.Do(t.Name(), func() error { return errFail }, nil)
+	}
+
+	err := soteria.Do(t.Name(), func() error { return nil }, nil)
+	if !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Do = %v, want ErrOpenState", err)
+	}
+}
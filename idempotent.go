@@ -0,0 +1,41 @@
+package soteria
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type idempotentKey struct{}
+
+// Idempotent returns a copy of ctx marking the request made with it as
+// safe to attempt more than once. See Settings.AwaitHalfOpen.
+func Idempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// IsIdempotent reports whether ctx was marked with Idempotent.
+func IsIdempotent(ctx context.Context) bool {
+	ok, _ := ctx.Value(idempotentKey{}).(bool)
+	return ok
+}
+
+// waitHalfOpen reports whether a request rejected with err should be
+// attempted again, having waited for the CircuitBreaker to become
+// half-open. See Settings.AwaitHalfOpen.
+func (cb *CircuitBreaker) waitHalfOpen(ctx context.Context, err error) bool {
+	var open *OpenStateError
+	if !errors.As(err, &open) || !IsIdempotent(ctx) {
+		return false
+	}
+
+	cb.mutex.Lock()
+	enabled := cb.awaitHalfOpen
+	cb.mutex.Unlock()
+
+	deadline, ok := ctx.Deadline()
+	if !enabled || !ok || time.Until(deadline) <= open.Remaining {
+		return false
+	}
+	return sleep(ctx, open.Remaining) == nil
+}
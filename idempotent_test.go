@@ -0,0 +1,52 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestAwaitHalfOpen(t *testing.T) {
+	cb := soteria.New(soteria.Settings{Timeout: 20 * time.Millisecond, AwaitHalfOpen: true})
+	trip(cb)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	calls := 0
+	err := cb.RunContext(soteria.Idempotent(ctx), func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+	if err != nil || calls != 1 {
+		t.Fatalf("RunContext = %v after %d calls, want the request retried once half-open", err, calls)
+	}
+	if cb.State() != soteria.StateClosed {
+		t.Errorf("State = %v, want the retried probe to close the breaker", cb.State())
+	}
+}
+
+func TestAwaitHalfOpenRequiresIdempotentAndDeadline(t *testing.T) {
+	cb := soteria.New(soteria.Settings{Timeout: time.Minute, AwaitHalfOpen: true})
+	trip(cb)
+
+	short, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	long, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+
+	for name, ctx := range map[string]context.Context{
+		"not idempotent": long,
+		"no deadline":    soteria.Idempotent(context.Background()),
+		"short deadline": soteria.Idempotent(short),
+	} {
+		start := time.Now()
+		err := cb.RunContext(ctx, func(ctx context.Context) error { return nil })
+		if !errors.Is(err, soteria.ErrOpenState) || time.Since(start) > time.Second {
+			t.Errorf("%s: RunContext = %v after %v, want an immediate rejection", name, err, time.Since(start))
+		}
+	}
+}
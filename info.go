@@ -0,0 +1,66 @@
+package soteria
+
+import (
+	"context"
+	"time"
+)
+
+// BreakerInfo describes the CircuitBreaker that admitted a request.
+type BreakerInfo struct {
+	Name string
+	// State is the state of the CircuitBreaker when the request was admitted.
+	State State
+}
+
+type infoKey struct{}
+
+// NewInfoContext returns a copy of ctx carrying info.
+func NewInfoContext(ctx context.Context, info BreakerInfo) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// InfoFromContext returns the BreakerInfo carried by ctx, if any.
+// It is set on the context ExecuteContext passes to the request, so that
+// logging and tracing in the request can tell which breaker protected it.
+func InfoFromContext(ctx context.Context) (BreakerInfo, bool) {
+	info, ok := ctx.Value(infoKey{}).(BreakerInfo)
+	return info, ok
+}
+
+// CallInfo describes how a CircuitBreaker admitted a request, for
+// correlating its decisions with the logs of the application.
+type CallInfo struct {
+	Name string
+	// Generation is the generation of the CircuitBreaker the request was
+	// admitted in. Its outcome only counts towards the same generation.
+	Generation uint64
+	// State is the state of the CircuitBreaker when the request was admitted.
+	State State
+	// Start is when the request was admitted.
+	Start time.Time
+	// Probe is true if the request was admitted as a half-open probe.
+	Probe bool
+}
+
+type callKey struct{}
+
+// NewCallContext returns a copy of ctx carrying info.
+func NewCallContext(ctx context.Context, info CallInfo) context.Context {
+	return context.WithValue(ctx, callKey{}, info)
+}
+
+// CallFromContext returns the CallInfo carried by ctx, if any. It is set
+// on the context ExecuteContext passes to the request; outcome TraceEvents
+// carry the same Generation and Probe.
+func CallFromContext(ctx context.Context) (CallInfo, bool) {
+	info, ok := ctx.Value(callKey{}).(CallInfo)
+	return info, ok
+}
+
+// IsProbe reports whether ctx is the context of a request a CircuitBreaker
+// admitted as a half-open probe, so that downstream code and logs can tell
+// probe traffic apart.
+func IsProbe(ctx context.Context) bool {
+	info, ok := CallFromContext(ctx)
+	return ok && info.Probe
+}
@@ -0,0 +1,85 @@
+package soteria_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestInfoContextRoundTrip(t *testing.T) {
+	if _, ok := soteria.InfoFromContext(context.Background()); ok {
+		t.Error("found info in an empty context")
+	}
+
+	ctx := soteria.NewInfoContext(context.Background(), soteria.BreakerInfo{Name: "db", State: soteria.StateOpen})
+	info, ok := soteria.InfoFromContext(ctx)
+	if !ok || info.Name != "db" || info.State != soteria.StateOpen {
+		t.Errorf("InfoFromContext = %+v, %v", info, ok)
+	}
+}
+
+func TestExecuteContextPassesInfo(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{Name: "ctx"})
+
+	cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		info, ok := soteria.InfoFromContext(ctx)
+		if !ok || info.Name != "ctx" || info.State != soteria.StateClosed {
+			t.Errorf("InfoFromContext = %+v, %v", info, ok)
+		}
+		return nil, nil
+	})
+}
+
+func TestExecuteContextPassesAdmittingState(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{})
+	trip(cb)
+	clock.Advance(cb.Timeout())
+
+	cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		if info, _ := soteria.InfoFromContext(ctx); info.State != soteria.StateHalfOpen {
+			t.Errorf("State = %v, want half-open", info.State)
+		}
+		return nil, nil
+	})
+}
+
+func TestExecuteContextPassesCallInfo(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Name: "ctx"})
+	s := cb.Subscribe(16)
+	defer s.Close()
+
+	var calls []soteria.CallInfo
+	call := func() {
+		cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+			info, ok := soteria.CallFromContext(ctx)
+			if !ok {
+				t.Error("no CallInfo in the context of the request")
+			}
+			calls = append(calls, info)
+			return nil, nil
+		})
+	}
+
+	call()
+	trip(cb)
+	clock.Advance(cb.Timeout())
+	call()
+
+	if c := calls[0]; c.Name != "ctx" || c.State != soteria.StateClosed || c.Probe || !c.Start.Equal(clock.Now().Add(-cb.Timeout())) {
+		t.Errorf("closed CallInfo = %+v", c)
+	}
+	if c := calls[1]; c.State != soteria.StateHalfOpen || !c.Probe || c.Generation <= calls[0].Generation {
+		t.Errorf("half-open CallInfo = %+v, want a probe of a later generation", c)
+	}
+
+	var last soteria.TraceEvent
+	for len(s.C) > 0 {
+		if e := <-s.C; e.Kind == soteria.TraceSuccess {
+			last = e
+		}
+	}
+	if last.Kind != soteria.TraceSuccess || last.Generation != calls[1].Generation || !last.Probe {
+		t.Errorf("last success = %+v, want the probe with its generation", last)
+	}
+}
@@ -0,0 +1,174 @@
+package soteria_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestAlignInterval(t *testing.T) {
+	for _, align := range []bool{false, true} {
+		cb, clock := newBreaker(t, soteria.Settings{Interval: time.Minute, AlignInterval: align})
+
+		clock.Advance(40 * time.Second)
+		cb.Reset()
+		fail(cb)
+
+		clock.Advance(20 * time.Second)
+		cleared := cb.Stats().Requests == 0
+		if cleared != align {
+			t.Errorf("AlignInterval %v: cleared on the minute = %v", align, cleared)
+		}
+
+		clock.Advance(40 * time.Second)
+		if got := cb.Stats().Requests; got != 0 {
+			t.Errorf("AlignInterval %v: Requests = %d a minute after the reset", align, got)
+		}
+	}
+}
+
+func TestOutcomeCountsBeforeIntervalRollOver(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Interval: time.Minute})
+	for i := 0; i < 5; i++ {
+		fail(cb)
+	}
+
+	// the sixth failure completes after the interval it was admitted in
+	cb.Execute(func() (interface{}, error) {
+		clock.Advance(time.Minute)
+		return nil, errFail
+	})
+
+	if cb.State() != soteria.StateOpen {
+		t.Errorf("State = %v, want the late failure to trip the breaker", cb.State())
+	}
+}
+
+func TestIntervalRollsOverAfterOutcome(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Interval: time.Minute})
+
+	cb.Execute(func() (interface{}, error) {
+		clock.Advance(time.Minute)
+		return nil, errFail
+	})
+
+	if got := cb.Stats().Requests; got != 0 {
+		t.Errorf("Requests = %d, want the expired generation rolled over", got)
+	}
+}
+
+// steppedClock runs on the monotonic clock from when it was created, moved
+// by Advance, while Step moves its wall clock only, as NTP corrections do.
+type steppedClock struct {
+	mutex   sync.Mutex
+	base    time.Time
+	elapsed time.Duration
+	step    time.Duration
+}
+
+func newSteppedClock(t *testing.T) *steppedClock {
+	now := time.Now()
+	if stepped := stepWall(now, time.Hour); stepped.Sub(now) != 0 || stepped.Round(0).Sub(now.Round(0)) != time.Hour {
+		t.Skip("cannot step the wall clock of a time.Time")
+	}
+	return &steppedClock{base: now}
+}
+
+// stepWall returns t with its wall clock moved by d, in whole seconds, and
+// its monotonic reading kept, which the time package offers no way to do.
+// The wall field of a time.Time with a monotonic reading holds its seconds
+// from bit 30.
+func stepWall(t time.Time, d time.Duration) time.Time {
+	wall := (*uint64)(unsafe.Pointer(&t))
+	*wall += uint64(d/time.Second) << 30
+	return t
+}
+
+func (c *steppedClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return stepWall(c.base.Add(c.elapsed), c.step)
+}
+
+func (c *steppedClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.elapsed += d
+}
+
+func (c *steppedClock) Step(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.step += d
+}
+
+func TestExpiryFollowsElapsedTime(t *testing.T) {
+	for _, align := range []bool{false, true} {
+		clock := newSteppedClock(t)
+		cb := soteria.New(soteria.Settings{
+			Interval:             time.Minute,
+			AlignInterval:        align,
+			Timeout:              time.Minute,
+			Windows:              []time.Duration{time.Minute},
+			Clock:                clock,
+			OnInvariantViolation: func(err error) { t.Error(err) },
+		})
+
+		fail(cb)
+		clock.Step(time.Hour)
+		s := cb.Stats()
+		if s.Requests != 1 {
+			t.Errorf("AlignInterval %v: Requests = %d after the wall clock stepped forward, want the interval running", align, s.Requests)
+		}
+		if w, _ := s.Window(time.Minute); w.Failures != 1 {
+			t.Errorf("AlignInterval %v: window = %+v after the wall clock stepped forward", align, w)
+		}
+
+		clock.Step(-2 * time.Hour)
+		clock.Advance(time.Minute)
+		if got := cb.Stats().Requests; got != 0 {
+			t.Errorf("AlignInterval %v: Requests = %d a minute later with the wall clock stepped back", align, got)
+		}
+
+		trip(cb)
+		clock.Step(time.Hour)
+		soteriatest.AssertOpen(t, cb)
+		if got := cb.RemainingOpenTime(); got != time.Minute {
+			t.Errorf("AlignInterval %v: RemainingOpenTime = %v after the wall clock stepped forward", align, got)
+		}
+		clock.Step(-2 * time.Hour)
+		clock.Advance(time.Minute)
+		soteriatest.AssertHalfOpen(t, cb)
+	}
+}
+
+func TestGenerationRolled(t *testing.T) {
+	var rolled []soteria.TraceEvent
+	cb, clock := newBreaker(t, soteria.Settings{
+		Interval: time.Minute,
+		OnTrace: func(e soteria.TraceEvent) {
+			if e.Kind == soteria.TraceGenerationRolled {
+				rolled = append(rolled, e)
+			}
+		},
+	})
+	first := cb.Stats().Generation
+
+	clock.Advance(time.Minute)
+	if got := cb.Stats().Generation; got != first+1 || len(rolled) != 1 {
+		t.Fatalf("Generation = %d after %d events, want %d", got, len(rolled), first+1)
+	}
+	if e := rolled[0]; e.Generation != first+1 || !e.Time.Equal(clock.Now()) {
+		t.Errorf("event = %+v, want the new generation at expiry", e)
+	}
+
+	cb.ResetStats("test")
+	trip(cb)
+	if got := cb.Stats().Generation; got != first+3 || len(rolled) != 3 {
+		t.Errorf("Generation = %d after %d events, want %d", got, len(rolled), first+3)
+	}
+}
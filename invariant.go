@@ -0,0 +1,97 @@
+package soteria
+
+import (
+	"fmt"
+	"time"
+)
+
+// InvariantError describes a violated internal invariant of a CircuitBreaker.
+type InvariantError struct {
+	Name      string
+	Invariant string
+	State     State
+	Stats     Stats
+}
+
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("soteria: breaker %q violates %q in state %v with %+v", e.Name, e.Invariant, e.State, e.Stats)
+}
+
+// verify checks the internal invariants and reports the first violation to
+// onInvariantViolation. It is a no-op unless Settings.OnInvariantViolation
+// was set. cb.mutex must be held.
+func (cb *CircuitBreaker) verify(now time.Time) {
+	if cb.onInvariantViolation == nil {
+		return
+	}
+
+	if invariant := cb.violated(now); invariant != "" {
+		err := &InvariantError{
+			Name:      cb.name,
+			Invariant: invariant,
+			State:     cb.state(),
+			Stats:     cb.snapshot(now),
+		}
+		cb.anomalies.InvariantViolations++
+		cb.anomaly(err)
+		cb.hook("OnInvariantViolation", func() { cb.onInvariantViolation(err) })
+	}
+}
+
+func (cb *CircuitBreaker) violated(now time.Time) string {
+	s := cb.stats
+
+	switch {
+	case s.TotalSuccesses+s.TotalFailures > s.Requests:
+		return "outcomes <= requests"
+	case s.ConsecutiveSuccesses > s.TotalSuccesses:
+		return "consecutive successes <= total successes"
+	case s.ConsecutiveFailures > s.TotalFailures:
+		return "consecutive failures <= total failures"
+	case s.ConsecutiveSuccesses > 0 && s.ConsecutiveFailures > 0:
+		return "consecutive successes and failures are exclusive"
+	}
+
+	switch cb.state() {
+	case StateClosed:
+		if cb.interval == 0 && !cb.expiry.IsZero() {
+			return "closed without interval has no expiry"
+		}
+		if cb.interval != 0 && cb.expiry.IsZero() {
+			return "closed with interval has an expiry"
+		}
+		if !cb.expiry.IsZero() && !now.Before(cb.expiry) && s.TotalFailures > 0 {
+			return "closed never accumulates failures past its expiry"
+		}
+	case StateHalfOpen:
+		if s.Requests > cb.maxRequests {
+			return "half-open requests <= MaxRequests"
+		}
+		if s.TotalFailures > cb.maxRequests-cb.probeSuccesses {
+			return "half-open failures <= MaxRequests - ProbeSuccesses"
+		}
+	case StateIsolated:
+		if !cb.expiry.IsZero() {
+			return "isolated has no expiry"
+		}
+		if s.Requests > 0 {
+			return "isolated admits no requests"
+		}
+	case StateOpen:
+		if cb.expiry.IsZero() {
+			return "open has an expiry"
+		}
+		if s.Requests > 0 && cb.openPassthrough <= 0 {
+			return "open admits no requests without passthrough"
+		}
+	default:
+		if _, ok := cb.custom[cb.state()]; !ok {
+			return "state is closed, half-open, open or custom"
+		}
+		if !cb.expiry.IsZero() {
+			return "custom has no expiry"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,59 @@
+package soteria_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+var walkDegraded = soteria.DefineState("walk-degraded")
+
+func TestRandomWalkKeepsInvariants(t *testing.T) {
+	ratio := func(s soteria.Stats) float64 {
+		if n := s.TotalSuccesses + s.TotalFailures; n > 0 {
+			return float64(s.TotalFailures) / float64(n)
+		}
+		return 0
+	}
+
+	settings := map[string]soteria.Settings{
+		"default":  {},
+		"probes":   {MaxRequests: 3, Timeout: time.Second},
+		"quorum":   {MaxRequests: 10, ProbeSuccesses: 8, Timeout: time.Second},
+		"interval": {Interval: 10 * time.Second, Timeout: time.Minute},
+		"aligned":  {Interval: 10 * time.Second, AlignInterval: true},
+		"minimum": {
+			MinimumRequests: 5,
+			ReadyToTrip:     func(s soteria.Stats) bool { return ratio(s) > 0.5 },
+		},
+		"windows": {
+			Windows: []time.Duration{time.Second, time.Minute},
+			ReadyToTrip: func(s soteria.Stats) bool {
+				w, _ := s.Window(time.Second)
+				return w.Failures > 3
+			},
+		},
+		"custom": {
+			States: []soteria.CustomState{{State: walkDegraded, Admit: soteria.RejectFraction(0.5)}},
+			Transitions: []soteria.Transition{
+				{From: soteria.StateClosed, To: walkDegraded, When: func(s soteria.Stats) bool { return s.ConsecutiveFailures > 2 }},
+				{From: walkDegraded, To: soteria.StateOpen, When: func(s soteria.Stats) bool { return s.ConsecutiveFailures > 2 }},
+				{From: walkDegraded, To: soteria.StateClosed, When: func(s soteria.Stats) bool { return s.ConsecutiveSuccesses > 3 }},
+			},
+		},
+	}
+
+	for name, st := range settings {
+		st := st
+		t.Run(name, func(t *testing.T) {
+			for seed := int64(0); seed < 100; seed++ {
+				st := st
+				st.Name = fmt.Sprintf("%s-%d", name, seed)
+				soteriatest.RandomWalk(t, st, seed, 500)
+			}
+		})
+	}
+}
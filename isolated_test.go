@@ -0,0 +1,78 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestForceOpenIsolatesUntilClosed(t *testing.T) {
+	clock := soteriatest.NewClock(time.Unix(0, 0))
+	cb := soteria.New(soteria.Settings{
+		Timeout:              time.Second,
+		Clock:                clock,
+		OnInvariantViolation: func(err error) { t.Error(err) },
+	})
+
+	if err := cb.ForceOpen(); err != nil {
+		t.Fatal(err)
+	}
+	soteriatest.AssertIsolated(t, cb)
+
+	// unlike a trip, isolation does not expire
+	clock.Advance(time.Hour)
+	soteriatest.AssertIsolated(t, cb)
+
+	_, err := cb.Execute(func() (interface{}, error) { return nil, nil })
+	if !errors.Is(err, soteria.ErrIsolated) || !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Execute while isolated = %v, want ErrIsolated wrapping ErrOpenState", err)
+	}
+
+	if err := cb.ForceClose(); err != nil {
+		t.Fatal(err)
+	}
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestIsolationIsNotATrip(t *testing.T) {
+	cb := soteria.New(soteria.Settings{})
+	cb.ForceOpen()
+	if trips := cb.TripRate().Total; trips != 0 {
+		t.Errorf("isolation counted as %d trips", trips)
+	}
+}
+
+func TestResetLeavesIsolation(t *testing.T) {
+	cb := soteria.New(soteria.Settings{})
+	cb.ForceOpen()
+	if err := cb.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestRegistryForceOpenIsolates(t *testing.T) {
+	r := soteria.NewRegistry()
+	r.GetOrCreate("b", soteria.Settings{})
+
+	if err := r.ForceState("b", soteria.StateOpen, soteria.Override{}); err != nil {
+		t.Fatal(err)
+	}
+	cb, _ := r.Get("b")
+	soteriatest.AssertIsolated(t, cb)
+}
+
+func TestIsolatedMarshalsByName(t *testing.T) {
+	text, err := soteria.StateIsolated.MarshalText()
+	if err != nil || string(text) != "isolated" {
+		t.Fatalf("MarshalText = %q, %v", text, err)
+	}
+
+	var s soteria.State
+	if err := s.UnmarshalText(text); err != nil || s != soteria.StateIsolated {
+		t.Errorf("UnmarshalText = %v, %v", s, err)
+	}
+}
@@ -0,0 +1,147 @@
+package soteria
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded is returned by a KeyedBreaker for requests of a key
+// over its Quota.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Quota limits the requests of a key to Rate per second, allowing bursts of
+// up to Burst requests. If Burst is less than 1, it is Rate rounded up, and
+// at least 1.
+type Quota struct {
+	Rate  float64
+	Burst int
+}
+
+// KeyedBreaker runs a CircuitBreaker per key, such as a tenant, so that
+// the failures of one key do not reject the requests of the others.
+// Requests over the Quota of their key are rejected with ErrQuotaExceeded
+// before reaching its CircuitBreaker and are not counted by it.
+type KeyedBreaker struct {
+	settings Settings
+	quota    Quota
+	clock    Clock
+
+	mutex sync.Mutex
+	keys  map[string]*keyed
+}
+
+type keyed struct {
+	cb     *CircuitBreaker
+	tokens float64
+	last   time.Time
+}
+
+// NewKeyedBreaker returns a KeyedBreaker creating the CircuitBreaker of each
+// key from settings, named settings.Name/KEY. If quota.Rate is 0, keys are
+// not rate limited.
+func NewKeyedBreaker(settings Settings, quota Quota) *KeyedBreaker {
+	if quota.Burst < 1 {
+		quota.Burst = int(math.Max(1, math.Ceil(quota.Rate)))
+	}
+
+	clock := settings.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	return &KeyedBreaker{
+		settings: settings,
+		quota:    quota,
+		clock:    clock,
+		keys:     make(map[string]*keyed),
+	}
+}
+
+// Breaker returns the CircuitBreaker of key, creating it if needed.
+func (k *KeyedBreaker) Breaker(key string) *CircuitBreaker {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return k.entry(key).cb
+}
+
+func (k *KeyedBreaker) entry(key string) *keyed {
+	e, ok := k.keys[key]
+	if !ok {
+		e = k.create(key, k.settings)
+		k.keys[key] = e
+	}
+	return e
+}
+
+func (k *KeyedBreaker) create(key string, settings Settings) *keyed {
+	settings.Name = k.settings.Name + "/" + key
+	return &keyed{cb: New(settings), tokens: float64(k.quota.Burst), last: k.clock.Now()}
+}
+
+// allow takes a token of key, returning its CircuitBreaker and whether the
+// request is within the Quota.
+func (k *KeyedBreaker) allow(key string) (*CircuitBreaker, bool) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	e := k.entry(key)
+	if k.quota.Rate <= 0 {
+		return e.cb, true
+	}
+
+	now := k.clock.Now()
+	e.tokens += now.Sub(e.last).Seconds() * k.quota.Rate
+	if burst := float64(k.quota.Burst); e.tokens > burst {
+		e.tokens = burst
+	}
+	e.last = now
+
+	if e.tokens < 1 {
+		return e.cb, false
+	}
+	e.tokens--
+	return e.cb, true
+}
+
+// Execute runs req through the CircuitBreaker of key, or returns
+// ErrQuotaExceeded if key is over its Quota.
+func (k *KeyedBreaker) Execute(key string, req func() (interface{}, error)) (interface{}, error) {
+	cb, ok := k.allow(key)
+	if !ok {
+		return nil, ErrQuotaExceeded
+	}
+	return cb.Execute(req)
+}
+
+// ExecuteContext is Execute with CircuitBreaker.ExecuteContext.
+func (k *KeyedBreaker) ExecuteContext(ctx context.Context, key string, req func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	cb, ok := k.allow(key)
+	if !ok {
+		return nil, ErrQuotaExceeded
+	}
+	return cb.ExecuteContext(ctx, req)
+}
+
+// Keys returns the keys with a CircuitBreaker, sorted.
+func (k *KeyedBreaker) Keys() []string {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	keys := make([]string, 0, len(k.keys))
+	for key := range k.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Remove drops the CircuitBreaker and the quota of key.
+func (k *KeyedBreaker) Remove(key string) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	delete(k.keys, key)
+}
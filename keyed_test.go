@@ -0,0 +1,84 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func keyedExecute(k *soteria.KeyedBreaker, key string, err error) error {
+	_, err = k.Execute(key, func() (interface{}, error) { return nil, err })
+	return err
+}
+
+func TestKeyedBreakerIsolatesKeys(t *testing.T) {
+	clock := soteriatest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	k := soteria.NewKeyedBreaker(soteria.Settings{Name: "api", Clock: clock}, soteria.Quota{})
+
+	for i := 0; i < 10; i++ {
+		keyedExecute(k, "acme", errFail)
+	}
+	soteriatest.AssertOpen(t, k.Breaker("acme"))
+
+	if err := keyedExecute(k, "globex", nil); err != nil {
+		t.Errorf("Execute for another key = %v, want nil", err)
+	}
+	if name := k.Breaker("acme").Name(); name != "api/acme" {
+		t.Errorf("Name = %q, want api/acme", name)
+	}
+	if keys := k.Keys(); len(keys) != 2 || keys[0] != "acme" || keys[1] != "globex" {
+		t.Errorf("Keys = %v", keys)
+	}
+
+	k.Remove("acme")
+	soteriatest.AssertClosed(t, k.Breaker("acme"))
+}
+
+func TestKeyedBreakerQuota(t *testing.T) {
+	clock := soteriatest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	k := soteria.NewKeyedBreaker(soteria.Settings{Clock: clock}, soteria.Quota{Rate: 1, Burst: 2})
+
+	for i := 0; i < 2; i++ {
+		if err := keyedExecute(k, "acme", nil); err != nil {
+			t.Fatalf("Execute %d = %v, want nil", i, err)
+		}
+	}
+	err := keyedExecute(k, "acme", nil)
+	if !errors.Is(err, soteria.ErrQuotaExceeded) || !soteria.IsRejected(err) {
+		t.Errorf("Execute over quota = %v, want ErrQuotaExceeded", err)
+	}
+	if err := keyedExecute(k, "globex", nil); err != nil {
+		t.Errorf("Execute for another key = %v, want nil", err)
+	}
+
+	clock.Advance(time.Second)
+	if err := keyedExecute(k, "acme", nil); err != nil {
+		t.Errorf("Execute after refill = %v, want nil", err)
+	}
+	if err := keyedExecute(k, "acme", nil); !errors.Is(err, soteria.ErrQuotaExceeded) {
+		t.Errorf("Execute over quota = %v, want ErrQuotaExceeded", err)
+	}
+
+	if c := k.Breaker("acme").Stats(); c.Requests != 3 {
+		t.Errorf("Requests = %d, want 3: rejected requests are not counted", c.Requests)
+	}
+}
+
+func TestKeyedBreakerQuotaDefaultBurst(t *testing.T) {
+	for rate, burst := range map[float64]int{0.5: 1, 2.5: 3, 100: 100} {
+		clock := soteriatest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		k := soteria.NewKeyedBreaker(soteria.Settings{Name: "k", Clock: clock}, soteria.Quota{Rate: rate})
+
+		for i := 0; i < burst; i++ {
+			if err := keyedExecute(k, "acme", nil); err != nil {
+				t.Fatalf("Rate %v: Execute %d = %v, want a burst of %d", rate, i, err, burst)
+			}
+		}
+		if err := keyedExecute(k, "acme", nil); !errors.Is(err, soteria.ErrQuotaExceeded) {
+			t.Errorf("Rate %v: Execute past the burst = %v, want ErrQuotaExceeded", rate, err)
+		}
+	}
+}
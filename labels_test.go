@@ -0,0 +1,54 @@
+package soteria_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestLabelsAreCopied(t *testing.T) {
+	labels := map[string]string{"region": "eu"}
+	cb, _ := newBreaker(t, soteria.Settings{Labels: labels})
+
+	labels["region"] = "us"
+	if got := cb.Labels()["region"]; got != "eu" {
+		t.Errorf("Labels changed with Settings.Labels: region = %q", got)
+	}
+
+	cb.Labels()["region"] = "us"
+	if got := cb.Labels()["region"]; got != "eu" {
+		t.Errorf("Labels changed through its result: region = %q", got)
+	}
+}
+
+func TestTraceEventsCarryLabels(t *testing.T) {
+	var events []soteria.TraceEvent
+	cb, _ := newBreaker(t, soteria.Settings{
+		Labels:  map[string]string{"region": "eu"},
+		OnTrace: func(e soteria.TraceEvent) { events = append(events, e) },
+	})
+	succeed(cb)
+	fail(cb)
+
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	for _, e := range events {
+		if !reflect.DeepEqual(e.Labels, map[string]string{"region": "eu"}) {
+			t.Errorf("%s event Labels = %v", e.Kind, e.Labels)
+		}
+	}
+}
+
+func TestTraceEventsWithoutLabels(t *testing.T) {
+	var events []soteria.TraceEvent
+	cb, _ := newBreaker(t, soteria.Settings{
+		OnTrace: func(e soteria.TraceEvent) { events = append(events, e) },
+	})
+	succeed(cb)
+
+	if len(events) != 1 || events[0].Labels != nil {
+		t.Errorf("events = %+v, want one without labels", events)
+	}
+}
@@ -0,0 +1,271 @@
+package soteria
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// ErrDeadlineBudget is matched by the errors of requests rejected because
+// their deadline is too short. See Settings.DeadlineBudget.
+var ErrDeadlineBudget = errors.New("deadline shorter than expected latency")
+
+// DeadlineBudgetError is returned for requests rejected because their
+// context deadline is nearer than the latency they are expected to take.
+// errors.Is(err, ErrDeadlineBudget) holds for it.
+type DeadlineBudgetError struct {
+	Remaining time.Duration
+	Expected  time.Duration
+}
+
+func (e *DeadlineBudgetError) Error() string {
+	return fmt.Sprintf("%s (%v left, %v expected)", ErrDeadlineBudget, e.Remaining, e.Expected)
+}
+
+func (e *DeadlineBudgetError) Is(target error) bool {
+	return target == ErrDeadlineBudget
+}
+
+// latencyWindow is the period latencies are tracked over: percentiles
+// cover the latencies of the current and the previous latencyWindow.
+const latencyWindow = time.Minute
+
+// deadlineBudgetSamples is the number of latencies needed before
+// Settings.DeadlineBudget rejects anything.
+const deadlineBudgetSamples = 20
+
+// latencies tracks request latencies over the last two latencyWindows.
+type latencies struct {
+	start    time.Time
+	current  histogram
+	previous histogram
+}
+
+func (l *latencies) roll(now time.Time) {
+	switch elapsed := now.Sub(l.start); {
+	case l.start.IsZero():
+		l.start = now
+	case elapsed >= 2*latencyWindow:
+		l.previous, l.current = histogram{}, histogram{}
+		l.start = now
+	case elapsed >= latencyWindow:
+		l.previous, l.current = l.current, histogram{}
+		l.start = l.start.Add(latencyWindow)
+	}
+}
+
+func (l *latencies) record(now time.Time, d time.Duration) {
+	l.roll(now)
+	l.current.record(d)
+}
+
+// quantile returns the latency below which a share q of the tracked
+// latencies fall, and their number.
+func (l *latencies) quantile(now time.Time, q float64) (time.Duration, uint64) {
+	h := l.merged(now)
+	return h.quantile(q), h.total
+}
+
+// merged returns the histogram of the tracked latencies.
+func (l *latencies) merged(now time.Time) histogram {
+	l.roll(now)
+	var h histogram
+	h.merge(&l.previous)
+	h.merge(&l.current)
+	return h
+}
+
+// histogram counts latencies in buckets growing exponentially from one
+// microsecond, with four buckets per doubling, for a relative error of at
+// most 25%.
+type histogram struct {
+	counts [histogramBuckets]uint64
+	total  uint64
+}
+
+const histogramBuckets = 1 + 64*4
+
+// latencyBucket returns the bucket of d.
+func latencyBucket(d time.Duration) int {
+	us := uint64(d / time.Microsecond)
+	if d <= 0 || us == 0 {
+		return 0
+	}
+
+	e := bits.Len64(us) - 1
+	if e < 2 {
+		return 1 + e*4
+	}
+	return 1 + e*4 + int(us>>(e-2)&3)
+}
+
+// bucketBound returns the exclusive upper bound of the latencies in bucket i.
+func bucketBound(i int) time.Duration {
+	if i == 0 {
+		return time.Microsecond
+	}
+
+	e, sub := (i-1)/4, (i-1)%4
+	var us float64
+	if e < 2 {
+		us = math.Ldexp(1, e+1)
+	} else {
+		us = math.Ldexp(float64(4+sub+1), e-2)
+	}
+	if us >= float64(math.MaxInt64/time.Microsecond) {
+		return math.MaxInt64
+	}
+	return time.Duration(us) * time.Microsecond
+}
+
+func (h *histogram) record(d time.Duration) {
+	h.counts[latencyBucket(d)]++
+	h.total++
+}
+
+func (h *histogram) merge(o *histogram) {
+	for i, n := range o.counts {
+		h.counts[i] += n
+	}
+	h.total += o.total
+}
+
+// quantile returns the upper bound of the bucket holding the latency below
+// which a share q of the latencies fall, or 0 if there are none.
+func (h *histogram) quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(q * float64(h.total)))
+	if rank == 0 {
+		rank = 1
+	}
+
+	var seen uint64
+	for i, n := range h.counts {
+		if seen += n; seen >= rank {
+			return bucketBound(i)
+		}
+	}
+	return bucketBound(histogramBuckets - 1)
+}
+
+// checkDeadlineBudget returns a *DeadlineBudgetError if the deadline of ctx,
+// which is on the system clock, leaves less time than the Settings.DeadlineBudget percentile of the
+// latencies. cb.mutex must be held.
+func (cb *CircuitBreaker) checkDeadlineBudget(ctx context.Context, now time.Time) error {
+	if cb.deadlineBudget <= 0 {
+		return nil
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+
+	expected, n := cb.latencies.quantile(now, cb.deadlineBudget)
+	if remaining := time.Until(deadline); n >= deadlineBudgetSamples && remaining < expected {
+		return &DeadlineBudgetError{Remaining: remaining, Expected: expected}
+	}
+	return nil
+}
+
+// latency returns the latency below which a share q of the latencies of
+// the last minute or two fall, and their number.
+func (cb *CircuitBreaker) latency(q float64) (time.Duration, uint64) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return cb.latencies.quantile(cb.clock.Now(), q)
+}
+
+// LatencyHistogram holds the latencies of the requests of a CircuitBreaker
+// over the last minute or two, in buckets with a relative width of at most
+// 25%. Only the buckets holding latencies are listed, in increasing order.
+type LatencyHistogram struct {
+	Buckets []LatencyBucket `json:"buckets"`
+	Count   uint64          `json:"count"`
+}
+
+// LatencyBucket counts the latencies below UpperBound and at or above the
+// UpperBound of the previous bucket. UpperBound is encoded in JSON as text,
+// such as "1.25ms".
+type LatencyBucket struct {
+	UpperBound time.Duration `json:"-"`
+	Count      uint64        `json:"count"`
+}
+
+func (b LatencyBucket) MarshalJSON() ([]byte, error) {
+	type plain LatencyBucket
+	return json.Marshal(struct {
+		UpperBound jsonDuration `json:"upper_bound"`
+		plain
+	}{jsonDuration(b.UpperBound), plain(b)})
+}
+
+func (b *LatencyBucket) UnmarshalJSON(data []byte) error {
+	type plain LatencyBucket
+	v := struct {
+		UpperBound jsonDuration `json:"upper_bound"`
+		*plain
+	}{plain: (*plain)(b)}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	b.UpperBound = time.Duration(v.UpperBound)
+	return nil
+}
+
+// Quantile returns the upper bound of the bucket holding the latency below
+// which a share q, between 0 and 1, of the latencies fall, such as q = 0.99
+// for the 99th percentile, or 0 if there are none.
+func (h LatencyHistogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+
+	rank := uint64(math.Ceil(q * float64(h.Count)))
+	var seen uint64
+	for _, b := range h.Buckets {
+		if seen += b.Count; seen >= rank {
+			return b.UpperBound
+		}
+	}
+	return h.Buckets[len(h.Buckets)-1].UpperBound
+}
+
+// SuggestTimeout suggests a timeout for the requests of the histogram from
+// evidence rather than guesses: the q quantile of their latencies times
+// factor, such as the 99th percentile times 1.5. It returns 0 if fewer than
+// minCount latencies were seen, as the suggestion would not be reliable.
+func (h LatencyHistogram) SuggestTimeout(q, factor float64, minCount uint64) time.Duration {
+	if h.Count == 0 || h.Count < minCount {
+		return 0
+	}
+	return time.Duration(float64(h.Quantile(q)) * factor)
+}
+
+// Latencies returns the histogram of the latencies of the requests of the
+// last minute or two, successes and failures alike.
+func (cb *CircuitBreaker) Latencies() LatencyHistogram {
+	if cb.unprotected() {
+		return LatencyHistogram{}
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	h := cb.latencies.merged(cb.clock.Now())
+
+	var lh LatencyHistogram
+	for i, n := range h.counts {
+		if n > 0 {
+			lh.Buckets = append(lh.Buckets, LatencyBucket{UpperBound: bucketBound(i), Count: n})
+		}
+	}
+	lh.Count = h.total
+	return lh
+}
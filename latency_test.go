@@ -0,0 +1,103 @@
+package soteria_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+// slow runs a request through cb taking d on clock.
+func slow(cb *soteria.CircuitBreaker, clock *soteriatest.Clock, d time.Duration) {
+	cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		clock.Advance(d)
+		return nil, nil
+	})
+}
+
+func TestDeadlineBudget(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{DeadlineBudget: 0.9})
+
+	short, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	run := func(ctx context.Context) error {
+		_, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) { return nil, nil })
+		return err
+	}
+
+	for i := 0; i < 19; i++ {
+		slow(cb, clock, 2*time.Second)
+	}
+	if err := run(short); err != nil {
+		t.Errorf("Execute before enough latencies = %v, want nil", err)
+	}
+
+	slow(cb, clock, 2*time.Second)
+	err := run(short)
+	var budget *soteria.DeadlineBudgetError
+	if !errors.As(err, &budget) || !errors.Is(err, soteria.ErrDeadlineBudget) || !soteria.IsRejected(err) {
+		t.Fatalf("Execute with a short deadline = %v, want a DeadlineBudgetError", err)
+	}
+	if budget.Expected < 2*time.Second || budget.Expected > 5*time.Second/2 {
+		t.Errorf("Expected = %v, want about 2s", budget.Expected)
+	}
+
+	if err := run(context.Background()); err != nil {
+		t.Errorf("Execute without a deadline = %v, want nil", err)
+	}
+	long, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := run(long); err != nil {
+		t.Errorf("Execute with a long deadline = %v, want nil", err)
+	}
+
+	clock.Advance(3 * time.Minute)
+	if err := run(short); err != nil {
+		t.Errorf("Execute once the latencies are old = %v, want nil", err)
+	}
+}
+
+func TestLatencies(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{})
+	for i := 0; i < 99; i++ {
+		slow(cb, clock, 10*time.Millisecond)
+	}
+	slow(cb, clock, time.Second)
+
+	h := cb.Latencies()
+	if h.Count != 100 || len(h.Buckets) != 2 || h.Buckets[0].Count != 99 {
+		t.Fatalf("Latencies = %+v, want 99 and 1 in two buckets", h)
+	}
+	if p := h.Quantile(0.5); p <= 10*time.Millisecond || p > 25*time.Millisecond/2 {
+		t.Errorf("p50 = %v, want just above 10ms", p)
+	}
+	if p := h.Quantile(1); p <= time.Second || p > 5*time.Second/4 {
+		t.Errorf("p100 = %v, want just above 1s", p)
+	}
+
+	if d := h.SuggestTimeout(0.99, 2, 100); d != 2*h.Quantile(0.99) {
+		t.Errorf("SuggestTimeout = %v, want twice the p99", d)
+	}
+	if d := h.SuggestTimeout(0.99, 2, 1000); d != 0 {
+		t.Errorf("SuggestTimeout with too few latencies = %v, want 0", d)
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded soteria.LatencyHistogram
+	if err := json.Unmarshal(data, &decoded); err != nil || !reflect.DeepEqual(decoded, h) {
+		t.Errorf("JSON %s decoded to %+v, %v", data, decoded, err)
+	}
+
+	clock.Advance(3 * time.Minute)
+	if h := cb.Latencies(); h.Count != 0 || h.Quantile(0.5) != 0 {
+		t.Errorf("Latencies once old = %+v, want none", h)
+	}
+}
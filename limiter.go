@@ -0,0 +1,173 @@
+package soteria
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrConcurrencyLimit is returned by an AdaptiveLimiter for requests over
+// its limit.
+var ErrConcurrencyLimit = errors.New("concurrency limit reached")
+
+// AdaptiveLimit configures an AdaptiveLimiter. Zero fields take defaults:
+// an InitialLimit of 20, a MinLimit of 1, a MaxLimit of 1000 and a
+// Smoothing of 0.2.
+type AdaptiveLimit struct {
+	InitialLimit int
+	MinLimit     int
+	MaxLimit     int
+
+	// Smoothing is the weight, between 0 and 1, of each new estimate of the
+	// limit against the current one.
+	Smoothing float64
+
+	// Clock measures latencies. If nil, the system clock is used.
+	Clock Clock
+}
+
+// AdaptiveLimiter bounds the requests in flight to a dependency, adjusting
+// the limit from their latencies with the gradient algorithm of Netflix's
+// concurrency-limits: the limit grows while latencies stay near the lowest
+// seen, and shrinks as they rise, which is queueing building up
+// downstream. Requests that time out shrink it further. It fits
+// dependencies whose safe throughput varies, where a fixed limit is either
+// too low at night or too high at peak.
+//
+// Requests over the limit are rejected with ErrConcurrencyLimit. Requests
+// within it go through the CircuitBreaker, if any, whose rejections leave
+// the limit as it is.
+type AdaptiveLimiter struct {
+	cb     *CircuitBreaker
+	config AdaptiveLimit
+
+	mutex    sync.Mutex
+	limit    float64
+	inFlight int
+	minRTT   time.Duration
+	samples  int
+}
+
+// adaptiveProbeSamples is the number of latencies after which the lowest
+// one is forgotten, so that the limiter adapts to a dependency that got
+// slower for good.
+const adaptiveProbeSamples = 1000
+
+// NewAdaptiveLimiter returns an AdaptiveLimiter in front of cb, which may
+// be nil.
+func NewAdaptiveLimiter(cb *CircuitBreaker, config AdaptiveLimit) *AdaptiveLimiter {
+	if config.MinLimit <= 0 {
+		config.MinLimit = 1
+	}
+	if config.MaxLimit <= 0 {
+		config.MaxLimit = 1000
+	}
+	if config.InitialLimit <= 0 {
+		config.InitialLimit = 20
+	}
+	if config.Smoothing <= 0 || config.Smoothing > 1 {
+		config.Smoothing = 0.2
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+
+	l := &AdaptiveLimiter{cb: cb, config: config}
+	l.limit = l.clamp(float64(config.InitialLimit))
+	return l
+}
+
+// Limit returns the current limit.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return int(l.limit)
+}
+
+// InFlight returns the number of requests in flight.
+func (l *AdaptiveLimiter) InFlight() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.inFlight
+}
+
+// Execute runs req if the limit allows, through the CircuitBreaker if any.
+func (l *AdaptiveLimiter) Execute(ctx context.Context, req func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if !l.acquire() {
+		return nil, ErrConcurrencyLimit
+	}
+
+	start := l.config.Clock.Now()
+	var (
+		result   interface{}
+		err      error
+		admitted bool
+	)
+	if l.cb == nil {
+		result, err = req(ctx)
+		admitted = true
+	} else {
+		result, err = l.cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+			admitted = true
+			return req(ctx)
+		})
+	}
+
+	l.release(admitted, l.config.Clock.Now().Sub(start), errors.Is(err, context.DeadlineExceeded))
+	return result, err
+}
+
+func (l *AdaptiveLimiter) acquire() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.inFlight >= int(l.limit) {
+		return false
+	}
+	l.inFlight++
+	return true
+}
+
+// release ends a request that took rtt. Requests that were not admitted
+// leave the limit unchanged.
+func (l *AdaptiveLimiter) release(admitted bool, rtt time.Duration, timedOut bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	inFlight := l.inFlight
+	l.inFlight--
+	if !admitted {
+		return
+	}
+
+	if timedOut {
+		l.limit = l.clamp(l.limit * 0.9)
+		return
+	}
+
+	if l.samples++; l.samples >= adaptiveProbeSamples {
+		l.samples, l.minRTT = 0, 0
+	}
+	if rtt <= 0 {
+		rtt = time.Nanosecond
+	}
+	if l.minRTT == 0 || rtt < l.minRTT {
+		l.minRTT = rtt
+	}
+
+	// Only grow a limit the traffic actually uses.
+	gradient := math.Max(0.5, math.Min(1, 2*float64(l.minRTT)/float64(rtt)))
+	estimate := l.limit*gradient + math.Sqrt(l.limit)
+	if estimate > l.limit && float64(inFlight) < l.limit/2 {
+		return
+	}
+
+	s := l.config.Smoothing
+	l.limit = l.clamp(l.limit*(1-s) + estimate*s)
+}
+
+func (l *AdaptiveLimiter) clamp(limit float64) float64 {
+	return math.Max(float64(l.config.MinLimit), math.Min(float64(l.config.MaxLimit), limit))
+}
@@ -0,0 +1,106 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func limited(l *soteria.AdaptiveLimiter, clock *soteriatest.Clock, d time.Duration, err error) error {
+	_, err = l.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		clock.Advance(d)
+		return nil, err
+	})
+	return err
+}
+
+// saturate runs as many nested requests as l allows, all taking d on clock.
+func saturate(l *soteria.AdaptiveLimiter, clock *soteriatest.Clock, d time.Duration) {
+	var nest func(n int)
+	nest = func(n int) {
+		l.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+			if n > 1 {
+				nest(n - 1)
+			} else {
+				clock.Advance(d)
+			}
+			return nil, nil
+		})
+	}
+	nest(l.Limit())
+}
+
+func TestAdaptiveLimiterRejectsOverLimit(t *testing.T) {
+	l := soteria.NewAdaptiveLimiter(nil, soteria.AdaptiveLimit{InitialLimit: 1, MaxLimit: 1})
+
+	l.Execute(context.Background(), func(ctx context.Context) (interface{}, error) {
+		if l.InFlight() != 1 {
+			t.Errorf("InFlight = %d, want 1", l.InFlight())
+		}
+		_, err := l.Execute(ctx, func(ctx context.Context) (interface{}, error) { return nil, nil })
+		if !errors.Is(err, soteria.ErrConcurrencyLimit) || !soteria.IsRejected(err) {
+			t.Errorf("Execute over the limit = %v, want ErrConcurrencyLimit", err)
+		}
+		return nil, nil
+	})
+	if l.InFlight() != 0 {
+		t.Errorf("InFlight = %d, want 0", l.InFlight())
+	}
+}
+
+func TestAdaptiveLimiterAdapts(t *testing.T) {
+	clock := soteriatest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	l := soteria.NewAdaptiveLimiter(nil, soteria.AdaptiveLimit{InitialLimit: 2, MaxLimit: 10, Clock: clock})
+
+	for i := 0; i < 5; i++ {
+		limited(l, clock, 10*time.Millisecond, nil)
+	}
+	if l.Limit() != 2 {
+		t.Errorf("Limit with idle traffic = %d, want 2", l.Limit())
+	}
+
+	for i := 0; i < 20; i++ {
+		saturate(l, clock, 10*time.Millisecond)
+	}
+	if l.Limit() != 10 {
+		t.Errorf("Limit with steady latencies = %d, want 10", l.Limit())
+	}
+
+	for i := 0; i < 20; i++ {
+		saturate(l, clock, 100*time.Millisecond)
+	}
+	if l.Limit() > 4 {
+		t.Errorf("Limit with rising latencies = %d, want at most 4", l.Limit())
+	}
+}
+
+func TestAdaptiveLimiterTimeouts(t *testing.T) {
+	clock := soteriatest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	l := soteria.NewAdaptiveLimiter(nil, soteria.AdaptiveLimit{InitialLimit: 10, Clock: clock})
+
+	for i := 0; i < 5; i++ {
+		limited(l, clock, time.Second, context.DeadlineExceeded)
+	}
+	if l.Limit() != 5 {
+		t.Errorf("Limit after timeouts = %d, want 5", l.Limit())
+	}
+}
+
+func TestAdaptiveLimiterBreaker(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Timeout: time.Minute})
+	l := soteria.NewAdaptiveLimiter(cb, soteria.AdaptiveLimit{InitialLimit: 10, Clock: clock})
+	soteriatest.Trip(t, cb, 10)
+
+	for i := 0; i < 5; i++ {
+		if err := limited(l, clock, 0, nil); !errors.Is(err, soteria.ErrOpenState) {
+			t.Fatalf("Execute while open = %v, want ErrOpenState", err)
+		}
+	}
+	if l.Limit() != 10 || l.InFlight() != 0 {
+		t.Errorf("Limit %d with %d in flight, want 10 and 0", l.Limit(), l.InFlight())
+	}
+}
@@ -0,0 +1,101 @@
+package soteria
+
+import "fmt"
+
+// Input is an input fed to the Machine of a CircuitBreaker.
+type Input int
+
+// Machine is the finite state machine a CircuitBreaker runs on.
+// A CircuitBreaker only calls it with its mutex held.
+type Machine interface {
+	// AddRule makes in move the machine from src to dst, calling action,
+	// if not nil, once the machine is in dst.
+	AddRule(src State, in Input, dst State, action func() error)
+	// State returns the current state.
+	State() State
+	// Process feeds in to the machine, returning the error of the action
+	// of the rule it followed, or an error if there is no rule for in.
+	Process(in Input) error
+}
+
+// NewMachineFunc creates a Machine knowing states and inputs, in the first
+// of states. See Settings.NewMachine.
+type NewMachineFunc func(states []State, inputs []Input) Machine
+
+type ruleKey struct {
+	src State
+	in  Input
+}
+
+type rule struct {
+	dst    State
+	action func() error
+}
+
+// ruleSpec is a rule as passed to Machine.AddRule.
+type ruleSpec struct {
+	src    State
+	in     Input
+	dst    State
+	action func() error
+}
+
+// builtinMachine is the default Machine. It is sealed by New once its
+// rules are added.
+type builtinMachine struct {
+	current State
+	rules   map[ruleKey]rule
+	sealed  bool
+}
+
+func newBuiltinMachine(states []State, inputs []Input) Machine {
+	return &builtinMachine{current: states[0], rules: make(map[ruleKey]rule)}
+}
+
+func (m *builtinMachine) AddRule(src State, in Input, dst State, action func() error) {
+	if m.sealed {
+		panic("soteria: rules added to the machine of a CircuitBreaker in use")
+	}
+	m.rules[ruleKey{src, in}] = rule{dst, action}
+}
+
+func (m *builtinMachine) State() State {
+	return m.current
+}
+
+func (m *builtinMachine) Process(in Input) error {
+	r, ok := m.rules[ruleKey{m.current, in}]
+	if !ok {
+		return fmt.Errorf("soteria: no rule for input %d in state %v", in, m.current)
+	}
+
+	m.current = r.dst
+	if r.action != nil {
+		return r.action()
+	}
+	return nil
+}
+
+// MachineErrors returns the number of errors the state machine of cb
+// returned while accounting for requests, since New.
+func (cb *CircuitBreaker) MachineErrors() uint64 {
+	if cb.unprotected() {
+		return 0
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return cb.anomalies.MachineErrors
+}
+
+// machineError reports err, if not nil. cb.mutex must be held.
+func (cb *CircuitBreaker) machineError(err error) {
+	if err == nil {
+		return
+	}
+
+	cb.anomalies.MachineErrors++
+	cb.anomaly(err)
+	if cb.onMachineError != nil {
+		cb.hook("OnMachineError", func() { cb.onMachineError(err) })
+	}
+}
@@ -0,0 +1,166 @@
+package soteria_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+type testRule struct {
+	dst    soteria.State
+	action func() error
+}
+
+// testMachine is a minimal soteria.Machine recording what it was created with.
+type testMachine struct {
+	states  []soteria.State
+	inputs  []soteria.Input
+	current soteria.State
+	rules   map[[2]int]testRule
+	fail    map[soteria.Input]error
+}
+
+func (m *testMachine) AddRule(src soteria.State, in soteria.Input, dst soteria.State, action func() error) {
+	m.rules[[2]int{int(src), int(in)}] = testRule{dst, action}
+}
+
+func (m *testMachine) State() soteria.State {
+	return m.current
+}
+
+func (m *testMachine) Process(in soteria.Input) error {
+	if err := m.fail[in]; err != nil {
+		return err
+	}
+
+	r, ok := m.rules[[2]int{int(m.current), int(in)}]
+	if !ok {
+		return fmt.Errorf("no rule for %d in %v", in, m.current)
+	}
+	m.current = r.dst
+	if r.action != nil {
+		return r.action()
+	}
+	return nil
+}
+
+func newTestMachine(m **testMachine) soteria.NewMachineFunc {
+	return func(states []soteria.State, inputs []soteria.Input) soteria.Machine {
+		*m = &testMachine{states: states, inputs: inputs, current: states[0], rules: make(map[[2]int]testRule)}
+		return *m
+	}
+}
+
+func TestNewMachine(t *testing.T) {
+	var m *testMachine
+	cb, clock := newBreaker(t, soteria.Settings{NewMachine: newTestMachine(&m)})
+
+	if m == nil || m.states[0] != soteria.StateClosed {
+		t.Fatalf("machine created with %v", m)
+	}
+
+	trip(cb)
+	if m.State() != soteria.StateOpen {
+		t.Errorf("machine in %v, want open", m.State())
+	}
+	clock.Advance(cb.Timeout())
+	succeed(cb)
+	if m.State() != soteria.StateClosed || cb.State() != soteria.StateClosed {
+		t.Errorf("machine in %v, breaker %v, want both closed", m.State(), cb.State())
+	}
+}
+
+func TestNewMachineKnowsCustomStates(t *testing.T) {
+	var m *testMachine
+	settings := degradedSettings(0)
+	settings.NewMachine = newTestMachine(&m)
+	newBreaker(t, settings)
+
+	found := false
+	for _, s := range m.states {
+		found = found || s == degraded
+	}
+	if !found {
+		t.Errorf("states = %v, want degraded among them", m.states)
+	}
+	if len(m.inputs) == 0 {
+		t.Error("no inputs")
+	}
+}
+
+func TestOnMachineError(t *testing.T) {
+	var (
+		m      *testMachine
+		errs   []error
+		errBug = errors.New("machine bug")
+	)
+	cb, _ := newBreaker(t, soteria.Settings{
+		NewMachine:     newTestMachine(&m),
+		OnMachineError: func(err error) { errs = append(errs, err) },
+	})
+	m.fail = map[soteria.Input]error{soteria.Ok: errBug}
+
+	got, err := cb.Execute(func() (interface{}, error) { return "result", nil })
+	if got != "result" || err != nil {
+		t.Errorf("Execute = %v, %v, want the result of the request", got, err)
+	}
+	if len(errs) != 1 || errs[0] != errBug {
+		t.Errorf("OnMachineError got %v", errs)
+	}
+	if n := cb.MachineErrors(); n != 1 {
+		t.Errorf("MachineErrors = %d, want 1", n)
+	}
+
+	fail(cb)
+	if n := cb.MachineErrors(); n != 1 {
+		t.Errorf("MachineErrors = %d after a failure the machine accepted", n)
+	}
+}
+
+func TestMachineErrorsWithoutCallback(t *testing.T) {
+	var m *testMachine
+	cb, _ := newBreaker(t, soteria.Settings{NewMachine: newTestMachine(&m)})
+	m.fail = map[soteria.Input]error{soteria.NotOk: errors.New("machine bug")}
+
+	if err := cb.Run(func() error { return errFail }); err != errFail {
+		t.Errorf("Run = %v, want the error of the request", err)
+	}
+	if n := cb.MachineErrors(); n != 1 {
+		t.Errorf("MachineErrors = %d, want 1", n)
+	}
+}
+
+// orderedMachine fails the test if a rule is added once it was used.
+type orderedMachine struct {
+	*testMachine
+	t    *testing.T
+	used bool
+}
+
+func (m *orderedMachine) AddRule(src soteria.State, in soteria.Input, dst soteria.State, action func() error) {
+	if m.used {
+		m.t.Errorf("rule for %d in %v added after the machine was used", in, src)
+	}
+	m.testMachine.AddRule(src, in, dst, action)
+}
+
+func (m *orderedMachine) State() soteria.State {
+	m.used = true
+	return m.testMachine.State()
+}
+
+func TestNewAddsRulesBeforeUse(t *testing.T) {
+	var m *testMachine
+	settings := degradedSettings(0)
+	settings.NewMachine = func(states []soteria.State, inputs []soteria.Input) soteria.Machine {
+		return &orderedMachine{testMachine: newTestMachine(&m)(states, inputs).(*testMachine), t: t}
+	}
+	cb, _ := newBreaker(t, settings)
+
+	if len(m.rules) == 0 {
+		t.Fatal("no rules added")
+	}
+	succeed(cb)
+}
@@ -0,0 +1,101 @@
+package soteria
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrMaintenance is returned for requests rejected during a MaintenanceOpen
+// window. It wraps ErrOpenState.
+var ErrMaintenance = fmt.Errorf("%w: scheduled maintenance", ErrOpenState)
+
+// MaintenanceMode is what a CircuitBreaker does during a MaintenanceWindow.
+type MaintenanceMode int
+
+const (
+	// MaintenanceOpen rejects every request with ErrMaintenance and reports
+	// the CircuitBreaker as open.
+	MaintenanceOpen MaintenanceMode = iota + 1
+	// MaintenanceObserve lets every request through regardless of state,
+	// without counting its outcome.
+	MaintenanceObserve
+)
+
+// Schedule tells whether a point in time falls within it.
+type Schedule interface {
+	Contains(t time.Time) bool
+}
+
+// MaintenanceWindow applies Mode while Schedule contains the current time.
+type MaintenanceWindow struct {
+	Schedule Schedule
+	Mode     MaintenanceMode
+}
+
+// maintenanceAt returns the mode of the first window containing now, or 0.
+func (cb *CircuitBreaker) maintenanceAt(now time.Time) MaintenanceMode {
+	for _, w := range cb.maintenance {
+		if w.Schedule.Contains(now) {
+			return w.Mode
+		}
+	}
+	return 0
+}
+
+// Between is a one-off Schedule from Start inclusive to End exclusive.
+type Between struct {
+	Start, End time.Time
+}
+
+func (b Between) Contains(t time.Time) bool {
+	return !t.Before(b.Start) && t.Before(b.End)
+}
+
+// Daily is a Schedule recurring every day at Start, an offset from
+// midnight in Location, for Duration. A nil Location means UTC.
+type Daily struct {
+	Start    time.Duration
+	Duration time.Duration
+	Location *time.Location
+}
+
+func (d Daily) Contains(t time.Time) bool {
+	return recurs(t, d.Location, 24*time.Hour, d.Start, d.Duration, func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	})
+}
+
+// Weekly is a Schedule recurring every week on Day at Start, an offset
+// from midnight in Location, for Duration. A nil Location means UTC.
+type Weekly struct {
+	Day      time.Weekday
+	Start    time.Duration
+	Duration time.Duration
+	Location *time.Location
+}
+
+func (w Weekly) Contains(t time.Time) bool {
+	return recurs(t, w.Location, 7*24*time.Hour, w.Start, w.Duration, func(t time.Time) time.Time {
+		day := t.AddDate(0, 0, -int((t.Weekday()-w.Day+7)%7))
+		return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, t.Location())
+	})
+}
+
+// recurs reports whether t falls within a window of duration opening at
+// start past the most recent period boundary returned by floor, or past
+// the boundary before it for windows spanning into the next period.
+func recurs(t time.Time, loc *time.Location, period, start, duration time.Duration, floor func(time.Time) time.Time) bool {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+
+	boundary := floor(t)
+	for _, b := range []time.Time{boundary, floor(boundary.Add(-period / 2))} {
+		opens := b.Add(start)
+		if !t.Before(opens) && t.Before(opens.Add(duration)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+// the clock of newBreaker starts on Monday 2024-01-01 at midnight UTC
+var monday = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestMaintenanceOpen(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{
+		Maintenance: []soteria.MaintenanceWindow{{
+			Schedule: soteria.Between{Start: monday.Add(time.Hour), End: monday.Add(2 * time.Hour)},
+			Mode:     soteria.MaintenanceOpen,
+		}},
+	})
+
+	succeed(cb)
+	clock.Advance(time.Hour)
+
+	if cb.State() != soteria.StateOpen {
+		t.Errorf("State = %v during maintenance, want open", cb.State())
+	}
+	_, err := cb.Execute(func() (interface{}, error) { return nil, nil })
+	if err != soteria.ErrMaintenance || !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Execute = %v, want ErrMaintenance", err)
+	}
+
+	clock.Advance(time.Hour)
+	if cb.State() != soteria.StateClosed {
+		t.Errorf("State = %v after maintenance, want closed", cb.State())
+	}
+	if got := cb.Stats().TotalSuccesses; got != 1 {
+		t.Errorf("TotalSuccesses = %d, want the stats kept through maintenance", got)
+	}
+}
+
+func TestMaintenanceObserve(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{
+		Maintenance: []soteria.MaintenanceWindow{{
+			Schedule: soteria.Between{Start: monday, End: monday.Add(time.Hour)},
+			Mode:     soteria.MaintenanceObserve,
+		}},
+	})
+
+	for i := 0; i < 10; i++ {
+		fail(cb)
+	}
+	if cb.State() != soteria.StateClosed {
+		t.Errorf("State = %v, want failures during maintenance not to trip", cb.State())
+	}
+	if st := cb.Stats(); st.TotalFailures != 0 {
+		t.Errorf("Stats = %+v, want outcomes not counted", st)
+	}
+}
+
+func TestDailySchedule(t *testing.T) {
+	// 23:00 to 01:00, spanning midnight
+	d := soteria.Daily{Start: 23 * time.Hour, Duration: 2 * time.Hour}
+
+	for offset, want := range map[time.Duration]bool{
+		22 * time.Hour:                  false,
+		23 * time.Hour:                  true,
+		24*time.Hour + 30*time.Minute:   true,
+		25 * time.Hour:                  false,
+		3*24*time.Hour + 23*time.Hour:   true,
+		3*24*time.Hour + 12*time.Hour:   false,
+		3*24*time.Hour + 59*time.Minute: true,
+		3*24*time.Hour + 61*time.Minute: false,
+	} {
+		if got := d.Contains(monday.Add(offset)); got != want {
+			t.Errorf("Contains(%v) = %v, want %v", monday.Add(offset), got, want)
+		}
+	}
+}
+
+func TestWeeklySchedule(t *testing.T) {
+	w := soteria.Weekly{Day: time.Wednesday, Start: 2 * time.Hour, Duration: time.Hour}
+	wednesday := monday.AddDate(0, 0, 2)
+
+	for at, want := range map[time.Time]bool{
+		wednesday.Add(2 * time.Hour):                  true,
+		wednesday.Add(3 * time.Hour):                  false,
+		monday.Add(2 * time.Hour):                     false,
+		wednesday.AddDate(0, 0, 7).Add(2 * time.Hour): true,
+	} {
+		if got := w.Contains(at); got != want {
+			t.Errorf("Contains(%v) = %v, want %v", at, got, want)
+		}
+	}
+}
+
+func TestScheduleLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	d := soteria.Daily{Start: 2 * time.Hour, Duration: time.Hour, Location: loc}
+
+	if !d.Contains(monday) {
+		t.Error("midnight UTC is not 02:00 in UTC+2")
+	}
+}
@@ -0,0 +1,303 @@
+package soteria
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// HTTPStack composes the layers of an HTTP client in the order
+//
+//	Tracing → Metrics → Breaker → Retry → next
+//
+// Tracing and Metrics see every request, including those the Breaker
+// rejects, so that rejections show up in both. The Breaker counts a
+// request once however many attempts Retry makes, and Retry never retries
+// a rejection, so that retries do not hammer a dependency the Breaker
+// already considers down.
+type HTTPStack struct {
+	// Tracing and Metrics wrap the rest of the stack, if not nil, such as
+	// otelhttp.NewTransport.
+	Tracing func(next http.RoundTripper) http.RoundTripper
+	Metrics func(next http.RoundTripper) http.RoundTripper
+
+	// Breaker, if not nil, guards the requests, as with a RoundTripper.
+	Breaker *CircuitBreaker
+	// Classifier decides on the status code, for the Breaker and for
+	// Retry. If nil, ServerErrors is used.
+	Classifier StatusClassifier
+
+	// Retry retries failed attempts. Requests with a body are only retried
+	// if they have a GetBody.
+	Retry Retry
+
+	// ProbeHeader marks the half-open probes of the Breaker, as with
+	// RoundTripper.ProbeHeader.
+	ProbeHeader string
+}
+
+// RoundTripper returns the stack in front of next. If next is nil,
+// http.DefaultTransport is used.
+func (s HTTPStack) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	rt := next
+	if s.Retry.Attempts > 1 {
+		rt = &retryTransport{next: rt, retry: s.Retry, classifier: s.Classifier}
+	}
+	if s.Breaker != nil {
+		rt = &RoundTripper{Breaker: s.Breaker, Next: rt, Classifier: s.Classifier, ProbeHeader: s.ProbeHeader}
+	}
+	if s.Metrics != nil {
+		rt = s.Metrics(rt)
+	}
+	if s.Tracing != nil {
+		rt = s.Tracing(rt)
+	}
+	return rt
+}
+
+type retryTransport struct {
+	next       http.RoundTripper
+	retry      Retry
+	classifier StatusClassifier
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	classifier := t.classifier
+	if classifier == nil {
+		classifier = ServerErrors
+	}
+
+	var (
+		resp *http.Response
+		last error
+		n    int
+	)
+	err := t.retry.Do(req.Context(), func(ctx context.Context) error {
+		n++
+		attempt := req
+		if n > 1 {
+			if req.Body != nil && req.Body != http.NoBody {
+				if req.GetBody == nil {
+					return errNoRetry
+				}
+				body, err := req.GetBody()
+				if err != nil {
+					return err
+				}
+				attempt = req.Clone(ctx)
+				attempt.Body = body
+			}
+			if resp != nil {
+				resp.Body.Close()
+				resp = nil
+			}
+		}
+
+		resp, last = t.next.RoundTrip(attempt)
+		if last == nil && classifier(resp.StatusCode) {
+			last = &StatusError{Code: resp.StatusCode, Status: resp.Status}
+		}
+		return last
+	})
+	if errors.Is(err, errNoRetry) {
+		err = last
+	}
+
+	var se *StatusError
+	if err == nil || errors.As(err, &se) {
+		return resp, nil
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return nil, err
+}
+
+// errNoRetry stops retrying a request whose body cannot be sent again.
+var errNoRetry = errors.New("request body cannot be retried")
+
+// HandlerOption configures Handler.
+type HandlerOption func(o *handlerOptions)
+
+type handlerOptions struct {
+	fallback http.Handler
+}
+
+// WithFallback answers the requests cb rejects with h, such as a cached
+// page, rather than with a plain text error. Unless h writes another status
+// code, the response is 503 Service Unavailable. h can get the rejection
+// with Rejection.
+func WithFallback(h http.Handler) HandlerOption {
+	return func(o *handlerOptions) {
+		o.fallback = h
+	}
+}
+
+// StaticFallback answers the requests cb rejects with body, such as a JSON
+// error or an HTML maintenance page, of the given content type.
+func StaticFallback(contentType string, body []byte) HandlerOption {
+	return WithFallback(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write(body)
+	}))
+}
+
+type rejectionKey struct{}
+
+// Rejection returns the error a fallback given to WithFallback is serving
+// r for, or nil.
+func Rejection(r *http.Request) error {
+	err, _ := r.Context().Value(rejectionKey{}).(error)
+	return err
+}
+
+// Handler serves requests through cb. Responses whose status classifier
+// reports as a failure, or ServerErrors if nil, count as failures. While
+// cb rejects requests, they are answered with 503 Service Unavailable, or
+// by the fallback of WithFallback, and, when cb is open, a Retry-After
+// header.
+func Handler(cb *CircuitBreaker, next http.Handler, classifier StatusClassifier, opts ...HandlerOption) http.Handler {
+	if classifier == nil {
+		classifier = ServerErrors
+	}
+
+	var o handlerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w}
+		_, err := cb.ExecuteContext(r.Context(), func(ctx context.Context) (interface{}, error) {
+			next.ServeHTTP(rec, r.WithContext(ctx))
+			if code := rec.status(); classifier(code) {
+				return nil, &StatusError{Code: code, Status: http.StatusText(code)}
+			}
+			return nil, nil
+		})
+
+		if IsRejected(err) && !rec.written {
+			var open *OpenStateError
+			if errors.As(err, &open) {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(open.Remaining.Seconds()))))
+			}
+			if o.fallback == nil {
+				http.Error(w, err.Error(), http.StatusServiceUnavailable)
+				return
+			}
+			r = r.WithContext(context.WithValue(r.Context(), rejectionKey{}, err))
+			o.fallback.ServeHTTP(&fallbackWriter{ResponseWriter: w}, r)
+		}
+	})
+}
+
+// fallbackWriter defaults the status code of a fallback to 503 Service
+// Unavailable.
+type fallbackWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *fallbackWriter) WriteHeader(code int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *fallbackWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *fallbackWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// HTTPHandlerStack composes the layers of an HTTP server in the order
+//
+//	Tracing → Metrics → Breaker → next
+//
+// so that rejected requests are traced and measured like the others.
+type HTTPHandlerStack struct {
+	Tracing func(next http.Handler) http.Handler
+	Metrics func(next http.Handler) http.Handler
+
+	// Breaker, if not nil, guards the requests, as with Handler.
+	Breaker    *CircuitBreaker
+	Classifier StatusClassifier
+	// Fallback, if not nil, answers the requests Breaker rejects, as with
+	// WithFallback.
+	Fallback http.Handler
+}
+
+// Handler returns the stack in front of next.
+func (s HTTPHandlerStack) Handler(next http.Handler) http.Handler {
+	h := next
+	if s.Breaker != nil {
+		var opts []HandlerOption
+		if s.Fallback != nil {
+			opts = append(opts, WithFallback(s.Fallback))
+		}
+		h = Handler(s.Breaker, h, s.Classifier, opts...)
+	}
+	if s.Metrics != nil {
+		h = s.Metrics(h)
+	}
+	if s.Tracing != nil {
+		h = s.Tracing(h)
+	}
+	return h
+}
+
+// statusRecorder records the status code written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	code    int
+	written bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.written {
+		r.code = code
+		r.written = true
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if !r.written {
+		r.WriteHeader(http.StatusOK)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		if !r.written {
+			r.WriteHeader(http.StatusOK)
+		}
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *statusRecorder) status() int {
+	if !r.written {
+		return http.StatusOK
+	}
+	return r.code
+}
@@ -0,0 +1,194 @@
+package soteria_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestHTTPStackOrder(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	var order []string
+	layer := func(name string) func(http.RoundTripper) http.RoundTripper {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+
+	cb, _ := newBreaker(t, soteria.Settings{})
+	client := &http.Client{Transport: soteria.HTTPStack{
+		Tracing: layer("tracing"),
+		Metrics: layer("metrics"),
+		Breaker: cb,
+		Retry:   soteria.Retry{Attempts: 3, Backoff: time.Millisecond},
+	}.RoundTripper(nil)}
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Errorf("status %d after %d calls, want 200 after 3", resp.StatusCode, calls)
+	}
+	if strings.Join(order, " ") != "tracing metrics" {
+		t.Errorf("layers ran as %v", order)
+	}
+	if s := cb.Stats(); s.Requests != 1 || s.TotalSuccesses != 1 {
+		t.Errorf("Stats = %+v, want one successful request for all attempts", s)
+	}
+}
+
+func TestHTTPStackDoesNotRetryRejections(t *testing.T) {
+	var calls int32
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&calls, 1)
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	cb, _ := newBreaker(t, soteria.Settings{Timeout: time.Minute})
+	soteriatest.Trip(t, cb, 10)
+
+	rt := soteria.HTTPStack{Breaker: cb, Retry: soteria.Retry{Attempts: 3}}.RoundTripper(next)
+	req, _ := http.NewRequest(http.MethodGet, "http://db", nil)
+	if _, err := rt.RoundTrip(req); !soteria.IsRejected(err) {
+		t.Errorf("RoundTrip = %v, want a rejection", err)
+	}
+	if calls != 0 {
+		t.Errorf("next called %d times, want 0", calls)
+	}
+}
+
+func TestHTTPStackRetriesWithGetBody(t *testing.T) {
+	var bodies []string
+	next := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		return &http.Response{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway", Body: http.NoBody}, nil
+	})
+
+	rt := soteria.HTTPStack{Retry: soteria.Retry{Attempts: 2, Backoff: time.Millisecond}}.RoundTripper(next)
+
+	req, _ := http.NewRequest(http.MethodPost, "http://db", strings.NewReader("row"))
+	resp, err := rt.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("RoundTrip = %v, %v, want the last 502", resp, err)
+	}
+	if len(bodies) != 2 || bodies[1] != "row" {
+		t.Errorf("bodies sent = %q, want the body twice", bodies)
+	}
+
+	bodies = nil
+	req, _ = http.NewRequest(http.MethodPost, "http://db", strings.NewReader("row"))
+	req.GetBody = nil
+	if resp, err := rt.RoundTrip(req); err != nil || resp.StatusCode != http.StatusBadGateway || len(bodies) != 1 {
+		t.Errorf("RoundTrip without GetBody = %v, %v after %d attempts, want one 502", resp, err, len(bodies))
+	}
+}
+
+func TestHandler(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Timeout: 30 * time.Second})
+	failing := true
+	h := soteria.HTTPHandlerStack{Breaker: cb}.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing {
+			http.Error(w, "down", http.StatusInternalServerError)
+		}
+	}))
+
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w
+	}
+
+	for i := 0; i < 6; i++ {
+		if w := serve(); w.Code != http.StatusInternalServerError {
+			t.Fatalf("status %d, want 500", w.Code)
+		}
+	}
+	soteriatest.AssertOpen(t, cb)
+
+	clock.Advance(10 * time.Second)
+	w := serve()
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "20" {
+		t.Errorf("status %d, Retry-After %q, want 503 and 20", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	clock.Advance(20 * time.Second)
+	failing = false
+	if w := serve(); w.Code != http.StatusOK {
+		t.Errorf("status %d, want 200", w.Code)
+	}
+}
+
+func TestHandlerFallback(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	trip(cb)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("next served a rejected request")
+	})
+
+	page := []byte("<h1>Down for maintenance</h1>")
+	w := httptest.NewRecorder()
+	soteria.Handler(cb, next, nil, soteria.StaticFallback("text/html", page)).
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Content-Type") != "text/html" || w.Body.String() != string(page) {
+		t.Errorf("static fallback = %d %q %q", w.Code, w.Header().Get("Content-Type"), w.Body)
+	}
+
+	fallback := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !errors.Is(soteria.Rejection(r), soteria.ErrOpenState) {
+			t.Errorf("Rejection = %v", soteria.Rejection(r))
+		}
+		w.Write([]byte("cached"))
+	})
+	w = httptest.NewRecorder()
+	soteria.HTTPHandlerStack{Breaker: cb, Fallback: fallback}.Handler(next).
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusServiceUnavailable || w.Body.String() != "cached" || w.Header().Get("Retry-After") == "" {
+		t.Errorf("fallback = %d %q, Retry-After %q", w.Code, w.Body, w.Header().Get("Retry-After"))
+	}
+}
+
+func TestHandlerMarksProbes(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{})
+
+	var probes []bool
+	h := soteria.Handler(cb, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probes = append(probes, soteria.IsProbe(r.Context()))
+	}), nil)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	trip(cb)
+	clock.Advance(cb.Timeout())
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if len(probes) != 2 || probes[0] || !probes[1] {
+		t.Errorf("probes = %v, want [false true]", probes)
+	}
+}
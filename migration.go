@@ -0,0 +1,56 @@
+package soteria
+
+import "time"
+
+// Divergence is a request on which a shadow policy decided otherwise than
+// the live CircuitBreaker.
+type Divergence struct {
+	Shadow string
+	Time   time.Time
+	// Live is the state of the live CircuitBreaker, State that of the
+	// shadow policy after the request.
+	Live  State
+	State State
+	// WouldReject is true if the shadow policy would have rejected a
+	// request the live CircuitBreaker admitted, false if it would have
+	// admitted a rejected one.
+	WouldReject bool
+}
+
+// Migration is a CircuitBreaker enforcing its current Settings while a
+// candidate policy shadows it on the same outcomes, as with AddShadow, so
+// that new thresholds can be tried on a critical path before they are
+// enforced.
+type Migration struct {
+	*CircuitBreaker
+	candidate Settings
+}
+
+const candidateShadow = "candidate"
+
+// NewMigration returns a Migration enforcing current and shadowing
+// candidate. onDivergence, if not nil, is called with every request the
+// candidate decided otherwise on, while the lock of the CircuitBreaker is
+// held, so it must not call the CircuitBreaker.
+func NewMigration(current, candidate Settings, onDivergence func(d Divergence)) *Migration {
+	m := &Migration{CircuitBreaker: New(current), candidate: candidate}
+	m.addShadow(candidateShadow, candidate, onDivergence)
+	return m
+}
+
+// Candidate returns what the candidate policy would have done so far.
+func (m *Migration) Candidate() ShadowStats {
+	for _, s := range m.Shadows() {
+		if s.Name == candidateShadow {
+			return s
+		}
+	}
+	return ShadowStats{Name: candidateShadow}
+}
+
+// Promote enforces the candidate Settings, as with UpdateSettings, and
+// stops shadowing them.
+func (m *Migration) Promote() {
+	m.RemoveShadow(candidateShadow)
+	m.UpdateSettings(m.candidate)
+}
@@ -0,0 +1,79 @@
+package soteria_test
+
+import (
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestMinimumRequestsGatesReadyToTrip(t *testing.T) {
+	var consulted int
+	cb, _ := newBreaker(t, soteria.Settings{
+		MinimumRequests: 3,
+		ReadyToTrip: func(stats soteria.Stats) bool {
+			consulted++
+			return true
+		},
+	})
+
+	fail(cb)
+	fail(cb)
+	if consulted != 0 || cb.State() != soteria.StateClosed {
+		t.Fatalf("ReadyToTrip consulted %d times below MinimumRequests", consulted)
+	}
+
+	fail(cb)
+	if consulted != 1 || cb.State() != soteria.StateOpen {
+		t.Errorf("consulted %d times, State = %v; want a trip at MinimumRequests", consulted, cb.State())
+	}
+}
+
+func TestMinimumRequestsIgnoresInFlight(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{
+		MinimumRequests: 3,
+		ReadyToTrip: func(stats soteria.Stats) bool {
+			if n := stats.TotalSuccesses + stats.TotalFailures; n < 3 {
+				t.Errorf("ReadyToTrip consulted with %d outcomes", n)
+			}
+			return false
+		},
+	})
+
+	// two requests in flight count as requests but not as outcomes
+	cb.Execute(func() (interface{}, error) {
+		cb.Execute(func() (interface{}, error) {
+			fail(cb)
+			return nil, errFail
+		})
+		return nil, errFail
+	})
+	fail(cb)
+
+	if got := cb.Stats().TotalFailures; got != 4 {
+		t.Errorf("TotalFailures = %d, want 4", got)
+	}
+}
+
+func TestMinimumRequestsGatesTransitions(t *testing.T) {
+	degraded := soteria.DefineState("minimum-test-degraded")
+
+	cb, _ := newBreaker(t, soteria.Settings{
+		MinimumRequests: 2,
+		ReadyToTrip:     func(soteria.Stats) bool { return false },
+		States:          []soteria.CustomState{{State: degraded}},
+		Transitions: []soteria.Transition{{
+			From: soteria.StateClosed,
+			To:   degraded,
+			When: func(stats soteria.Stats) bool { return stats.TotalFailures > 0 },
+		}},
+	})
+
+	fail(cb)
+	if cb.State() != soteria.StateClosed {
+		t.Fatalf("State = %v below MinimumRequests", cb.State())
+	}
+	succeed(cb)
+	if cb.State() != degraded {
+		t.Errorf("State = %v, want %v at MinimumRequests", cb.State(), degraded)
+	}
+}
@@ -0,0 +1,91 @@
+package soteria
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+)
+
+// NetClassifier categorizes network failures as CategoryDNS, CategoryTLS,
+// CategoryConnection or CategoryTimeout, and nothing else.
+// Classifier(NetClassifier).IsSuccessful counts only network failures.
+var NetClassifier Classifier = NetCategory
+
+// NetCategory returns the Category of a network failure, or "" if err is
+// not one. DNS and TLS failures take precedence over connection and
+// timeout failures; a DNS lookup that timed out is CategoryDNS.
+func NetCategory(err error) Category {
+	switch {
+	case err == nil:
+		return ""
+	case IsDNSError(err):
+		return CategoryDNS
+	case IsTLSHandshakeError(err):
+		return CategoryTLS
+	case IsConnectionError(err):
+		return CategoryConnection
+	case IsTimeout(err):
+		return CategoryTimeout
+	}
+	return ""
+}
+
+// IsTimeout reports whether err is a timeout, either a net.Error timeout
+// or an exceeded deadline.
+func IsTimeout(err error) bool {
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// IsConnectionRefused reports whether err is a refused connection.
+func IsConnectionRefused(err error) bool {
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// IsConnectionError reports whether err is a refused, reset or aborted
+// connection, or a failed dial.
+func IsConnectionError(err error) bool {
+	if IsConnectionRefused(err) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, syscall.EHOSTUNREACH) ||
+		errors.Is(err, syscall.ENETUNREACH) {
+		return true
+	}
+
+	var oe *net.OpError
+	return errors.As(err, &oe) && oe.Op == "dial" && !IsTimeout(err)
+}
+
+// IsDNSError reports whether err is a failed name resolution.
+func IsDNSError(err error) bool {
+	var de *net.DNSError
+	return errors.As(err, &de)
+}
+
+// IsTLSHandshakeError reports whether err is a failed TLS handshake or
+// certificate verification.
+func IsTLSHandshakeError(err error) bool {
+	var (
+		rhe tls.RecordHeaderError
+		ae  tls.AlertError
+		cve *tls.CertificateVerificationError
+		uae x509.UnknownAuthorityError
+		he  x509.HostnameError
+		cie x509.CertificateInvalidError
+	)
+
+	return errors.As(err, &rhe) ||
+		errors.As(err, &ae) ||
+		errors.As(err, &cve) ||
+		errors.As(err, &uae) ||
+		errors.As(err, &he) ||
+		errors.As(err, &cie)
+}
@@ -0,0 +1,65 @@
+package soteria_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestNetCategory(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	dnsTimeout := &net.DNSError{Err: "i/o timeout", Name: "backend", IsTimeout: true}
+
+	for _, c := range []struct {
+		name string
+		err  error
+		want soteria.Category
+	}{
+		{"nil", nil, ""},
+		{"plain", errors.New("boom"), ""},
+		{"refused", refused, soteria.CategoryConnection},
+		{"reset", fmt.Errorf("read: %w", syscall.ECONNRESET), soteria.CategoryConnection},
+		{"dns", &net.DNSError{Err: "no such host", Name: "backend", IsNotFound: true}, soteria.CategoryDNS},
+		{"dns timeout", dnsTimeout, soteria.CategoryDNS},
+		{"tls", fmt.Errorf("handshake: %w", x509.UnknownAuthorityError{}), soteria.CategoryTLS},
+		{"deadline", os.ErrDeadlineExceeded, soteria.CategoryTimeout},
+		{"context", context.DeadlineExceeded, soteria.CategoryTimeout},
+	} {
+		if got := soteria.NetCategory(c.err); got != c.want {
+			t.Errorf("%s: NetCategory = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNetErrorPredicates(t *testing.T) {
+	refused := fmt.Errorf("dial: %w", syscall.ECONNREFUSED)
+	if !soteria.IsConnectionRefused(refused) || !soteria.IsConnectionError(refused) {
+		t.Error("refused connection not recognized")
+	}
+	if soteria.IsConnectionRefused(syscall.ECONNRESET) {
+		t.Error("reset connection recognized as refused")
+	}
+
+	dialTimeout := &net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}
+	if soteria.IsConnectionError(dialTimeout) || !soteria.IsTimeout(dialTimeout) {
+		t.Error("dial timeout not recognized as a timeout only")
+	}
+}
+
+func TestNetClassifierIsSuccessful(t *testing.T) {
+	is := soteria.NetClassifier.IsSuccessful
+
+	if !is(nil) || !is(errors.New("application error")) {
+		t.Error("non-network errors counted as failures")
+	}
+	if is(syscall.ECONNREFUSED) {
+		t.Error("refused connection counted as a success")
+	}
+}
@@ -0,0 +1,21 @@
+package soteria
+
+// unprotected reports whether cb is nil or a zero CircuitBreaker, which
+// run requests unprotected: they pass through, are never rejected and are
+// not counted, so that libraries can accept an optional *CircuitBreaker
+// without checking it for nil at every call site. The accessors of such a
+// CircuitBreaker report a closed breaker without stats, its controls are
+// no-ops, and its Subscriptions are closed right away. The zero value
+// panics in strict mode, see SetStrict.
+func (cb *CircuitBreaker) unprotected() bool {
+	if cb == nil {
+		return true
+	}
+	if cb.machine == nil {
+		if Strict() {
+			misuse("CircuitBreaker not created by New")
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestNilBreakerPassesThrough(t *testing.T) {
+	for name, cb := range map[string]*soteria.CircuitBreaker{"nil": nil, "zero": {}} {
+		for i := 0; i < 10; i++ {
+			if err := fail(cb); err != errFail {
+				t.Fatalf("%s: Execute = %v, want the error of the request", name, err)
+			}
+		}
+
+		got, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) { return "ok", nil })
+		if got != "ok" || err != nil {
+			t.Errorf("%s: ExecuteContext = %v, %v", name, got, err)
+		}
+		if got, err := cb.ExecuteAsync(func() (interface{}, error) { return "ok", nil }).Wait(context.Background()); got != "ok" || err != nil {
+			t.Errorf("%s: ExecuteAsync = %v, %v", name, got, err)
+		}
+		if err := cb.ReportFailure(errFail, time.Second); err != nil {
+			t.Errorf("%s: ReportFailure = %v", name, err)
+		}
+		if _, err := cb.Execute(nil); !errors.Is(err, soteria.ErrMisuse) {
+			t.Errorf("%s: Execute(nil) = %v, want ErrMisuse", name, err)
+		}
+
+		if cb.State() != soteria.StateClosed || cb.Stats().Requests != 0 || cb.Name() != "" || cb.RemainingOpenTime() != 0 {
+			t.Errorf("%s: State = %v, Stats = %+v, want a closed breaker without stats", name, cb.State(), cb.Stats())
+		}
+	}
+}
+
+func TestStrictZeroBreaker(t *testing.T) {
+	soteria.SetStrict(true)
+	defer soteria.SetStrict(false)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Execute on a zero CircuitBreaker did not panic in strict mode")
+			}
+		}()
+		succeed(&soteria.CircuitBreaker{})
+	}()
+
+	var cb *soteria.CircuitBreaker
+	if err := succeed(cb); err != nil {
+		t.Errorf("Execute on a nil CircuitBreaker = %v in strict mode", err)
+	}
+}
+
+func TestNilBreakerControlsAreNoOps(t *testing.T) {
+	for name, cb := range map[string]*soteria.CircuitBreaker{"nil": nil, "zero": {}} {
+		for control, do := range map[string]func() error{
+			"ForceOpen":           cb.ForceOpen,
+			"ForceClose":          cb.ForceClose,
+			"Reset":               cb.Reset,
+			"ClosedOkAction":      cb.ClosedOkAction,
+			"ClosedNotOkAction":   cb.ClosedNotOkAction,
+			"HalfOpenOkAction":    cb.HalfOpenOkAction,
+			"HalfOpenNotOkAction": cb.HalfOpenNotOkAction,
+		} {
+			if err := do(); err != nil {
+				t.Errorf("%s: %s = %v, want nil", name, control, err)
+			}
+		}
+
+		cb.ResetStats("incident resolved")
+		cb.UpdateSettings(soteria.Settings{MaxRequests: 3})
+		cb.ModifySettings(func(settings *soteria.Settings) { settings.MaxRequests = 3 })
+		cb.AddShadow("strict", soteria.Settings{})
+		cb.RemoveShadow("strict")
+		cb.HookPool(soteria.PoolHooks{})()
+
+		for _, s := range []*soteria.Subscription{cb.Subscribe(1), cb.SubscribeTransitions(1)} {
+			if _, ok := <-s.C; ok {
+				t.Errorf("%s: Subscription delivered an event, want C closed", name)
+			}
+			s.Close()
+		}
+
+		if cb.State() != soteria.StateClosed || len(cb.Shadows()) != 0 {
+			t.Errorf("%s: State = %v with shadows %v, want a closed breaker without shadows", name, cb.State(), cb.Shadows())
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package soteria
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Overflow decides how the half-open CircuitBreaker accounts for the
+// requests it rejects with ErrTooManyRequests because all of its probes
+// are taken. See Settings.HalfOpenOverflow.
+type Overflow int
+
+const (
+	// OverflowTraced traces and passes overflow rejections to OnRejected
+	// like any other rejection, without counting them in Stats.
+	OverflowTraced Overflow = iota
+	// OverflowCounted also counts them in Stats.Overflows.
+	OverflowCounted
+	// OverflowSilent neither traces nor passes them to OnRejected, so that
+	// a busy half-open CircuitBreaker does not drown out its probes.
+	OverflowSilent
+)
+
+// overflow rejects a request in excess of the half-open probes.
+// cb.mutex must be held.
+func (cb *CircuitBreaker) overflow(now time.Time) error {
+	if cb.overflowResetsProbes {
+		atomic.StoreUint32(&cb.stats.ConsecutiveSuccesses, 0)
+	}
+
+	switch cb.overflowMode {
+	case OverflowSilent:
+		return cb.named(ErrTooManyRequests)
+	case OverflowCounted:
+		atomic.AddUint32(&cb.stats.Overflows, 1)
+	}
+	return cb.reject(now, ErrTooManyRequests)
+}
@@ -0,0 +1,48 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestHalfOpenOverflow(t *testing.T) {
+	for _, tc := range []struct {
+		overflow  soteria.Overflow
+		resets    bool
+		rejected  int
+		overflows uint32
+		streak    uint32
+	}{
+		{overflow: soteria.OverflowTraced, rejected: 1, streak: 1},
+		{overflow: soteria.OverflowCounted, rejected: 1, overflows: 1, streak: 1},
+		{overflow: soteria.OverflowSilent, resets: true},
+	} {
+		rejected := 0
+		cb, clock := newBreaker(t, soteria.Settings{
+			MaxRequests:          2,
+			HalfOpenOverflow:     tc.overflow,
+			OverflowResetsProbes: tc.resets,
+			OnRejected:           func(err error, suppressed uint64) { rejected++ },
+		})
+		trip(cb)
+		soteriatest.AdvanceToHalfOpen(t, clock, cb)
+		succeed(cb)
+
+		var stats soteria.Stats
+		cb.Execute(func() (interface{}, error) {
+			if err := succeed(cb); !errors.Is(err, soteria.ErrTooManyRequests) {
+				t.Errorf("overflow = %v, want ErrTooManyRequests", err)
+			}
+			stats = cb.Stats()
+			return nil, nil
+		})
+
+		if rejected != tc.rejected || stats.Overflows != tc.overflows || stats.ConsecutiveSuccesses != tc.streak {
+			t.Errorf("%v: %d rejections, Stats = %+v", tc.overflow, rejected, stats)
+		}
+		soteriatest.AssertClosed(t, cb)
+	}
+}
@@ -0,0 +1,39 @@
+package soteria_test
+
+import (
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestOpenPassthrough(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{OpenPassthrough: 1})
+	trip(cb)
+
+	for i := 0; i < 3; i++ {
+		if err := succeed(cb); err != nil {
+			t.Fatalf("request %d while open = %v, want it let through", i, err)
+		}
+	}
+	fail(cb)
+
+	s := cb.Stats()
+	if cb.State() != soteria.StateOpen || s.Requests != 4 || s.TotalSuccesses != 3 || s.TotalFailures != 1 {
+		t.Errorf("State = %v with %+v, want open with the passthrough counted", cb.State(), s)
+	}
+}
+
+func TestOpenPassthroughShare(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{OpenPassthrough: 0.2})
+	trip(cb)
+
+	passed := 0
+	for i := 0; i < 1000; i++ {
+		if succeed(cb) == nil {
+			passed++
+		}
+	}
+	if passed < 100 || passed > 300 {
+		t.Errorf("%d of 1000 requests let through, want about 200", passed)
+	}
+}
@@ -0,0 +1,11 @@
+module github.com/jtejido/soteria/persephonefsm
+
+go 1.25.0
+
+// persephonefsm also requires github.com/jtejido/persephone; it is kept in
+// a module of its own so that soteria itself does not depend on it.
+// Add it with: go get github.com/jtejido/persephone
+
+require github.com/jtejido/soteria v0.0.0
+
+replace github.com/jtejido/soteria => ../
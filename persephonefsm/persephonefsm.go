@@ -0,0 +1,49 @@
+// Package persephonefsm runs soteria circuit breakers on the persephone
+// state machine:
+//
+//	cb := soteria.New(soteria.Settings{NewMachine: persephonefsm.New})
+package persephonefsm
+
+import (
+	"github.com/jtejido/persephone"
+
+	"github.com/jtejido/soteria"
+)
+
+type machine struct {
+	*persephone.AbstractFSM
+}
+
+// New is a soteria.NewMachineFunc creating persephone machines.
+func New(states []soteria.State, inputs []soteria.Input) soteria.Machine {
+	var (
+		ps persephone.States
+		pi persephone.Inputs
+	)
+
+	for i, s := range states {
+		if i == 0 {
+			ps.Add(persephone.State(s), persephone.INITIAL_STATE)
+		} else {
+			ps.Add(persephone.State(s), persephone.NORMAL_STATE)
+		}
+	}
+
+	for _, in := range inputs {
+		pi.Add(persephone.Input(in))
+	}
+
+	return machine{persephone.New(ps, pi)}
+}
+
+func (m machine) AddRule(src soteria.State, in soteria.Input, dst soteria.State, action func() error) {
+	m.AbstractFSM.AddRule(persephone.State(src), persephone.Input(in), persephone.State(dst), action)
+}
+
+func (m machine) State() soteria.State {
+	return soteria.State(m.GetState())
+}
+
+func (m machine) Process(in soteria.Input) error {
+	return m.AbstractFSM.Process(persephone.Input(in))
+}
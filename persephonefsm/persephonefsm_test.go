@@ -0,0 +1,44 @@
+package persephonefsm_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/persephonefsm"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+var errFail = errors.New("fail")
+
+func TestBreakerOnPersephone(t *testing.T) {
+	clock := soteriatest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	cb := soteria.New(soteria.Settings{
+		Name:                 "persephone",
+		Timeout:              time.Minute,
+		Clock:                clock,
+		NewMachine:           persephonefsm.New,
+		OnInvariantViolation: func(err error) { t.Error(err) },
+	})
+
+	soteriatest.Trip(t, cb, 10)
+	if _, err := cb.Execute(func() (interface{}, error) { return nil, nil }); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Execute while open = %v, want ErrOpenState", err)
+	}
+
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+	cb.Execute(func() (interface{}, error) { return nil, errFail })
+	soteriatest.AssertOpen(t, cb)
+
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+	if _, err := cb.Execute(func() (interface{}, error) { return nil, nil }); err != nil {
+		t.Fatal(err)
+	}
+	soteriatest.AssertClosed(t, cb)
+
+	cb.ForceOpen()
+	soteriatest.AssertIsolated(t, cb)
+	cb.ForceClose()
+	soteriatest.AssertClosed(t, cb)
+}
@@ -0,0 +1,39 @@
+package soteria
+
+// PoolHooks connect a connection pool to the CircuitBreaker guarding its
+// host.
+//
+// Drain, if set, is called when the CircuitBreaker opens or is isolated,
+// such as to close the idle connections to the failing host.
+//
+// WarmUp, if set, is called when the CircuitBreaker closes again, such as
+// to open connections ahead of the returning traffic.
+type PoolHooks struct {
+	Drain  func()
+	WarmUp func()
+}
+
+// HookPool calls the hooks of p as the state of cb changes. They are called
+// in order, on a goroutine of their own, so they may block without holding
+// up requests; state changes made while a hook runs are buffered. Calling
+// the returned function stops the hooks.
+func (cb *CircuitBreaker) HookPool(p PoolHooks) (stop func()) {
+	s := cb.SubscribeTransitions(16)
+
+	go func() {
+		for e := range s.C {
+			switch e.To {
+			case StateOpen, StateIsolated:
+				if p.Drain != nil {
+					p.Drain()
+				}
+			case StateClosed:
+				if p.WarmUp != nil {
+					p.WarmUp()
+				}
+			}
+		}
+	}()
+
+	return s.Close
+}
@@ -0,0 +1,71 @@
+package soteria_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestHookPool(t *testing.T) {
+	calls := make(chan string, 10)
+	cb, clock := newBreaker(t, soteria.Settings{})
+	stop := cb.HookPool(soteria.PoolHooks{
+		Drain:  func() { calls <- "drain" },
+		WarmUp: func() { calls <- "warm-up" },
+	})
+	defer stop()
+
+	next := func() string {
+		select {
+		case c := <-calls:
+			return c
+		case <-time.After(5 * time.Second):
+			return "timeout"
+		}
+	}
+
+	soteriatest.Trip(t, cb, 10)
+	if c := next(); c != "drain" {
+		t.Errorf("after tripping: %s, want drain", c)
+	}
+
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+	succeed(cb)
+	if c := next(); c != "warm-up" {
+		t.Errorf("after closing: %s, want warm-up", c)
+	}
+
+	cb.ForceOpen()
+	if c := next(); c != "drain" {
+		t.Errorf("after ForceOpen: %s, want drain", c)
+	}
+}
+
+func TestHookPoolIgnoresRequests(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	drained := make(chan struct{}, 1)
+	stop := cb.HookPool(soteria.PoolHooks{Drain: func() { drained <- struct{}{} }})
+
+	for i := 0; i < 100; i++ {
+		succeed(cb)
+	}
+	soteriatest.Trip(t, cb, 10)
+
+	select {
+	case <-drained:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Drain not called once the breaker opened")
+	}
+
+	stop()
+	cb.Reset()
+	cb.ForceOpen()
+	select {
+	case <-drained:
+		t.Error("Drain called after stop")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
@@ -0,0 +1,31 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestPressureFunc(t *testing.T) {
+	pressure := 0.5
+	cb, _ := newBreaker(t, soteria.Settings{
+		PressureFunc: func() float64 { return pressure },
+		MaxPressure:  0.8,
+	})
+
+	if err := succeed(cb); err != nil {
+		t.Fatalf("Execute = %v under low pressure", err)
+	}
+	soteriatest.AssertClosed(t, cb)
+
+	pressure = 0.9
+	if err := succeed(cb); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Execute = %v, want the breaker tripped before the request", err)
+	}
+	soteriatest.AssertOpen(t, cb)
+	if s := cb.Stats(); s.TotalFailures != 0 {
+		t.Errorf("Stats = %+v, want no failures", s)
+	}
+}
@@ -0,0 +1,153 @@
+package soteria_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestAllowProbe(t *testing.T) {
+	type probeKey struct{}
+	cb, clock := newBreaker(t, soteria.Settings{
+		MaxRequests: 3,
+		AllowProbe: func(ctx context.Context) bool {
+			return ctx.Value(probeKey{}) != nil
+		},
+	})
+
+	succeed(cb)
+	trip(cb)
+	clock.Advance(cb.Timeout())
+
+	_, err := cb.ExecuteContext(context.Background(), func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	})
+	if err != soteria.ErrTooManyRequests {
+		t.Errorf("ExecuteContext = %v, want ErrTooManyRequests", err)
+	}
+
+	probe := context.WithValue(context.Background(), probeKey{}, true)
+	if _, err := cb.ExecuteContext(probe, func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	}); err != nil {
+		t.Errorf("ExecuteContext = %v for a probe", err)
+	}
+}
+
+func TestAllowProbeOnlyWhenHalfOpen(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{
+		AllowProbe: func(ctx context.Context) bool { return false },
+	})
+
+	if _, err := cb.Execute(func() (interface{}, error) { return nil, nil }); err != nil {
+		t.Errorf("Execute = %v while closed", err)
+	}
+}
+
+func TestSafeProbes(t *testing.T) {
+	if soteria.SafeProbes(context.Background()) {
+		t.Error("allowed a probe without a request")
+	}
+
+	for method, want := range map[string]bool{
+		http.MethodGet:     true,
+		http.MethodHead:    true,
+		http.MethodOptions: true,
+		http.MethodTrace:   true,
+		http.MethodPut:     false,
+		http.MethodDelete:  false,
+		http.MethodPost:    false,
+		http.MethodPatch:   false,
+	} {
+		cb, clock := newBreaker(t, soteria.Settings{AllowProbe: soteria.SafeProbes})
+		trip(cb)
+		clock.Advance(cb.Timeout())
+
+		var n int
+		rt := &soteria.RoundTripper{Breaker: cb, Next: respond(200, &n)}
+		req, _ := http.NewRequest(method, "http://backend/", nil)
+		if resp, err := rt.RoundTrip(req); err == nil {
+			resp.Body.Close()
+		}
+
+		if got := n == 1; got != want {
+			t.Errorf("%s probe sent = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestProbeWindow(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{MaxRequests: 4, ProbeWindow: 4 * time.Second})
+	soteriatest.Trip(t, cb, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	for i := 0; i < 4; i++ {
+		if err := succeed(cb); err != nil {
+			t.Fatalf("probe %d = %v, want nil", i, err)
+		}
+		if err := succeed(cb); i < 3 && err != soteria.ErrTooManyRequests {
+			t.Fatalf("probe %d before its token = %v, want ErrTooManyRequests", i, err)
+		}
+		clock.Advance(time.Second)
+	}
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestProbeWindowCapsAtMaxRequests(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{MaxRequests: 2, ProbeWindow: time.Second})
+	soteriatest.Trip(t, cb, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+	clock.Advance(time.Hour)
+
+	// probes stay in flight until all of them were tried
+	admitted := 0
+	var probe func(n int)
+	probe = func(n int) {
+		if n > 0 {
+			cb.Run(func() error {
+				admitted++
+				probe(n - 1)
+				return nil
+			})
+		}
+	}
+	probe(4)
+
+	if admitted != 2 {
+		t.Errorf("%d probes admitted, want MaxRequests", admitted)
+	}
+}
+
+func TestProbesExhausted(t *testing.T) {
+	var exhausted []soteria.TraceEvent
+	cb, clock := newBreaker(t, soteria.Settings{
+		MaxRequests:    3,
+		ProbeSuccesses: 2,
+		OnTrace: func(e soteria.TraceEvent) {
+			if e.Kind == soteria.TraceProbesExhausted {
+				exhausted = append(exhausted, e)
+			}
+		},
+	})
+
+	trip(cb)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+	succeed(cb)
+	fail(cb)
+	if len(exhausted) != 0 {
+		t.Fatalf("exhausted after 2 of 3 probes: %+v", exhausted)
+	}
+	succeed(cb)
+
+	if len(exhausted) != 1 {
+		t.Fatalf("got %d probes exhausted events, want 1", len(exhausted))
+	}
+	if e := exhausted[0]; e.From != soteria.StateHalfOpen || e.To != soteria.StateClosed ||
+		e.Stats == nil || e.Stats.TotalSuccesses != 2 || e.Stats.TotalFailures != 1 {
+		t.Errorf("event = %+v, want the probes closing the breaker", e)
+	}
+}
@@ -0,0 +1,95 @@
+package soteria
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrUnknownProfile is returned by Profile for a name it does not know.
+var ErrUnknownProfile = errors.New("unknown settings profile")
+
+// profiles are the Settings presets, by name. Every preset trips on a
+// failure ratio over at least MinimumRequests outcomes.
+var profiles = map[string]func() Settings{
+	// aggressive: trips quickly, recovers quickly; for a fast cache that
+	// is cheaper to skip than to wait for.
+	"fast-cache": func() Settings {
+		return Settings{
+			MaxRequests:              5,
+			ProbeSuccesses:           4,
+			Interval:                 10 * time.Second,
+			Timeout:                  5 * time.Second,
+			ReadyToTrip:              failureRatio(0.2),
+			MinimumRequests:          20,
+			IgnoreCallerCancellation: true,
+		}
+	},
+	// balanced: for the RPCs of services within the same organization.
+	"internal-rpc": func() Settings {
+		return Settings{
+			MaxRequests:              5,
+			ProbeSuccesses:           3,
+			Interval:                 time.Minute,
+			Timeout:                  30 * time.Second,
+			ReadyToTrip:              failureRatio(0.5),
+			MinimumRequests:          20,
+			IgnoreCallerCancellation: true,
+		}
+	},
+	// lenient: tolerates the noise of an API over the internet and probes
+	// it gently, spreading the probes over half a minute.
+	"third-party": func() Settings {
+		return Settings{
+			MaxRequests:     3,
+			ProbeSuccesses:  2,
+			ProbeWindow:     30 * time.Second,
+			Interval:        2 * time.Minute,
+			Timeout:         time.Minute,
+			ReadyToTrip:     failureRatio(0.6),
+			MinimumRequests: 10,
+		}
+	},
+}
+
+var profileAliases = map[string]string{
+	"aggressive": "fast-cache",
+	"balanced":   "internal-rpc",
+	"lenient":    "third-party",
+}
+
+// Profile returns the Settings preset tuned for a common class of
+// dependency, so that thresholds are not copied from service to service:
+//
+//	fast-cache, aggressive     trips at 20% failures, opens for 5s
+//	internal-rpc, balanced     trips at 50% failures, opens for 30s
+//	third-party, lenient       trips at 60% failures, opens for 1m
+//
+// Each trips only once enough outcomes of the current Interval were seen.
+// Set Name, and adjust the rest as needed, before passing the Settings to
+// New.
+func Profile(name string) (Settings, error) {
+	if alias, ok := profileAliases[name]; ok {
+		name = alias
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		return Settings{}, fmt.Errorf("%w: %q", ErrUnknownProfile, name)
+	}
+	return profile(), nil
+}
+
+// Profiles returns the names of the presets of Profile, aliases included,
+// sorted.
+func Profiles() []string {
+	names := make([]string, 0, len(profiles)+len(profileAliases))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	for alias := range profileAliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	return names
+}
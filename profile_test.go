@@ -0,0 +1,38 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestProfile(t *testing.T) {
+	for _, name := range soteria.Profiles() {
+		settings, err := soteria.Profile(name)
+		if err != nil {
+			t.Fatalf("Profile(%q) = %v", name, err)
+		}
+		cb, _ := newBreaker(t, settings)
+
+		for i := uint32(0); i < settings.MinimumRequests-1; i++ {
+			fail(cb)
+		}
+		soteriatest.AssertClosed(t, cb)
+		fail(cb)
+		soteriatest.AssertOpen(t, cb)
+	}
+}
+
+func TestProfileAliases(t *testing.T) {
+	lenient, _ := soteria.Profile("lenient")
+	thirdParty, _ := soteria.Profile("third-party")
+	if lenient.Timeout != thirdParty.Timeout || lenient.MinimumRequests != thirdParty.MinimumRequests {
+		t.Errorf("lenient = %+v, want the third-party preset", lenient)
+	}
+
+	if _, err := soteria.Profile("reckless"); !errors.Is(err, soteria.ErrUnknownProfile) {
+		t.Errorf("Profile of an unknown name = %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package soteria
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// random picks random numbers from a rand.Source, or from the global
+// source of math/rand if it is nil. It is safe for concurrent use.
+type random struct {
+	mutex sync.Mutex
+	rand  *rand.Rand
+}
+
+func newRandom(src rand.Source) *random {
+	if src == nil {
+		return nil
+	}
+	return &random{rand: rand.New(src)}
+}
+
+func (r *random) Float64() float64 {
+	if r == nil {
+		return rand.Float64()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.rand.Float64()
+}
+
+// passes reports whether an event picked at random falls within the share
+// rate of all events.
+func (r *random) passes(rate float64) bool {
+	return rate >= 1 || rate > 0 && r.Float64() < rate
+}
@@ -0,0 +1,89 @@
+package soteria_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+// admissions returns which of n requests an open breaker with a share of
+// passthrough drawn from a source seeded with seed lets through.
+func admissions(t *testing.T, seed int64, n int) []bool {
+	cb, _ := newBreaker(t, soteria.Settings{OpenPassthrough: 0.5, Rand: rand.NewSource(seed)})
+	trip(cb)
+
+	admitted := make([]bool, n)
+	for i := range admitted {
+		admitted[i] = succeed(cb) == nil
+	}
+	return admitted
+}
+
+func TestRandReproducesPassthrough(t *testing.T) {
+	a, b := admissions(t, 42, 50), admissions(t, 42, 50)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("request %d admitted %v, then %v from the same seed", i, a[i], b[i])
+		}
+	}
+}
+
+func TestRandReproducesSampling(t *testing.T) {
+	sampled := func() int {
+		n := 0
+		cb, _ := newBreaker(t, soteria.Settings{
+			Sampling: map[string]float64{soteria.TraceSuccess: 0.3},
+			Rand:     rand.NewSource(7),
+			OnTrace:  func(e soteria.TraceEvent) { n++ },
+		})
+		for i := 0; i < 100; i++ {
+			succeed(cb)
+		}
+		return n
+	}
+
+	if a, b := sampled(), sampled(); a != b {
+		t.Errorf("%d then %d successes sampled from the same seed", a, b)
+	}
+}
+
+func TestRejectFractionFrom(t *testing.T) {
+	rejected := func() (n int) {
+		admit := soteria.RejectFractionFrom(0.5, rand.NewSource(3))
+		for i := 0; i < 100; i++ {
+			if admit(context.Background()) != nil {
+				n++
+			}
+		}
+		return n
+	}
+
+	if a, b := rejected(), rejected(); a != b || a == 0 || a == 100 {
+		t.Errorf("%d then %d requests rejected from the same seed", a, b)
+	}
+}
+
+func TestBalancerSetRand(t *testing.T) {
+	x, _ := newBreaker(t, soteria.Settings{})
+	y, _ := newBreaker(t, soteria.Settings{})
+
+	sequence := func() []string {
+		b := soteria.NewBalancer(soteria.BalancerTarget[string]{Target: "x", Breaker: x}, soteria.BalancerTarget[string]{Target: "y", Breaker: y})
+		b.SetRand(rand.NewSource(11))
+		var targets []string
+		for i := 0; i < 20; i++ {
+			target, _ := b.Pick()
+			targets = append(targets, target.Target)
+		}
+		return targets
+	}
+
+	a, b := sequence(), sequence()
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("pick %d was %s, then %s from the same seed", i, a[i], b[i])
+		}
+	}
+}
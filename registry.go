@@ -0,0 +1,216 @@
+package soteria
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrUnknownBreaker is returned by Registry operations on a name that is
+// not registered.
+var ErrUnknownBreaker = errors.New("unknown circuit breaker")
+
+// Registry is a set of CircuitBreakers by name, for managing the breakers
+// of a process as a whole.
+type Registry struct {
+	mutex     sync.RWMutex
+	breakers  map[string]*CircuitBreaker
+	audit     AuditLog
+	scheduler Scheduler
+	hooks     []*shutdownHook
+}
+
+func NewRegistry() *Registry {
+	return &Registry{breakers: make(map[string]*CircuitBreaker)}
+}
+
+// Scheduler returns the Scheduler shared by the background work on the
+// breakers of r, such as exporting them.
+func (r *Registry) Scheduler() *Scheduler {
+	return &r.scheduler
+}
+
+// Add registers cb under its name, replacing any CircuitBreaker of the
+// same name. In strict mode replacing another CircuitBreaker panics, see
+// SetStrict.
+func (r *Registry) Add(cb *CircuitBreaker) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if prev, ok := r.breakers[cb.Name()]; ok && prev != cb && Strict() {
+		misuse("breaker %q is already registered", cb.Name())
+	}
+	r.breakers[cb.Name()] = cb
+}
+
+// GetOrCreate returns the CircuitBreaker registered under name, creating
+// and registering it from settings if there is none. settings.Name is
+// set to name.
+func (r *Registry) GetOrCreate(name string, settings Settings) *CircuitBreaker {
+	if cb, ok := r.Get(name); ok {
+		return cb
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	cb, ok := r.breakers[name]
+	if !ok {
+		settings.Name = name
+		cb = New(settings)
+		r.breakers[name] = cb
+	}
+	return cb
+}
+
+func (r *Registry) Get(name string) (*CircuitBreaker, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	cb, ok := r.breakers[name]
+	return cb, ok
+}
+
+func (r *Registry) Remove(name string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.breakers, name)
+}
+
+// Names returns the registered names, sorted.
+func (r *Registry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	names := make([]string, 0, len(r.breakers))
+	for name := range r.breakers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Breakers returns the registered CircuitBreakers, sorted by name.
+func (r *Registry) Breakers() []*CircuitBreaker {
+	names := r.Names()
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	breakers := make([]*CircuitBreaker, 0, len(names))
+	for _, name := range names {
+		if cb, ok := r.breakers[name]; ok {
+			breakers = append(breakers, cb)
+		}
+	}
+	return breakers
+}
+
+func (r *Registry) lookup(name string) (*CircuitBreaker, error) {
+	cb, ok := r.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBreaker, name)
+	}
+	return cb, nil
+}
+
+// Override identifies who applies a manual override and why, for the
+// audit log.
+type Override struct {
+	Operator string
+	Reason   string
+}
+
+// SetAuditLog makes the Registry record its manual overrides to log.
+// If log is nil, overrides are not recorded.
+func (r *Registry) SetAuditLog(log AuditLog) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.audit = log
+}
+
+// AuditLog returns the AuditLog set with SetAuditLog, or nil.
+func (r *Registry) AuditLog() AuditLog {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.audit
+}
+
+// override applies do to the named CircuitBreaker and records it.
+func (r *Registry) override(name, action string, o Override, do func(cb *CircuitBreaker) error) error {
+	cb, err := r.lookup(name)
+	if err != nil {
+		return err
+	}
+
+	err = do(cb)
+
+	if log := r.AuditLog(); log != nil {
+		e := AuditEntry{
+			Time:     time.Now(),
+			Breaker:  name,
+			Action:   action,
+			Operator: o.Operator,
+			Reason:   o.Reason,
+		}
+		if err != nil {
+			e.Error = err.Error()
+		}
+		if err_a := log.Record(e); err_a != nil && err == nil {
+			err = err_a
+		}
+	}
+	return err
+}
+
+// ForceState forces the named CircuitBreaker closed, or open, which
+// isolates it (see CircuitBreaker.ForceOpen). Forcing StateHalfOpen is not
+// supported.
+func (r *Registry) ForceState(name string, state State, o Override) error {
+	switch state {
+	case StateOpen, StateIsolated:
+		return r.override(name, ActionForceOpen, o, (*CircuitBreaker).ForceOpen)
+	case StateClosed:
+		return r.override(name, ActionForceClose, o, (*CircuitBreaker).ForceClose)
+	}
+	return fmt.Errorf("soteria: cannot force %v", state)
+}
+
+// Reset resets the named CircuitBreaker.
+func (r *Registry) Reset(name string, o Override) error {
+	return r.override(name, ActionReset, o, (*CircuitBreaker).Reset)
+}
+
+// ResetStats clears the Stats of the named CircuitBreaker, with the reason
+// of o. See CircuitBreaker.ResetStats.
+func (r *Registry) ResetStats(name string, o Override) error {
+	return r.override(name, ActionResetStats, o, func(cb *CircuitBreaker) error {
+		cb.ResetStats(o.Reason)
+		return nil
+	})
+}
+
+// Shed sets the percentage of requests the named CircuitBreaker sheds.
+// See CircuitBreaker.SetShedPercent.
+func (r *Registry) Shed(name string, percent float64, o Override) error {
+	return r.override(name, ActionShed, o, func(cb *CircuitBreaker) error {
+		return cb.SetShedPercent(percent)
+	})
+}
+
+// UpdateSettings replaces the settings of the named CircuitBreaker.
+func (r *Registry) UpdateSettings(name string, settings Settings, o Override) error {
+	return r.override(name, ActionUpdateSettings, o, func(cb *CircuitBreaker) error {
+		cb.UpdateSettings(settings)
+		return nil
+	})
+}
+
+// ModifySettings modifies the settings of the named CircuitBreaker.
+// See CircuitBreaker.ModifySettings.
+func (r *Registry) ModifySettings(name string, modify func(settings *Settings), o Override) error {
+	return r.override(name, ActionUpdateSettings, o, func(cb *CircuitBreaker) error {
+		cb.ModifySettings(modify)
+		return nil
+	})
+}
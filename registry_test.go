@@ -0,0 +1,84 @@
+package soteria_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestRegistryGetOrCreate(t *testing.T) {
+	r := soteria.NewRegistry()
+
+	cb := r.GetOrCreate("db", soteria.Settings{Name: "ignored", Timeout: time.Second})
+	if cb.Name() != "db" || cb.Timeout() != time.Second {
+		t.Errorf("created %q with timeout %v", cb.Name(), cb.Timeout())
+	}
+	if again := r.GetOrCreate("db", soteria.Settings{}); again != cb {
+		t.Error("GetOrCreate created a second breaker")
+	}
+}
+
+func TestRegistryNamesAndRemove(t *testing.T) {
+	r := soteria.NewRegistry()
+	for _, name := range []string{"cache", "db", "api"} {
+		r.Add(soteria.New(soteria.Settings{Name: name}))
+	}
+
+	if got := r.Names(); !reflect.DeepEqual(got, []string{"api", "cache", "db"}) {
+		t.Errorf("Names = %v", got)
+	}
+
+	r.Remove("cache")
+	if _, ok := r.Get("cache"); ok {
+		t.Error("removed breaker still registered")
+	}
+	if got := len(r.Breakers()); got != 2 {
+		t.Errorf("got %d breakers, want 2", got)
+	}
+}
+
+func TestRegistryUnknownBreaker(t *testing.T) {
+	r := soteria.NewRegistry()
+
+	if err := r.Reset("nope", soteria.Override{}); !errors.Is(err, soteria.ErrUnknownBreaker) {
+		t.Errorf("Reset = %v, want ErrUnknownBreaker", err)
+	}
+	if err := r.ForceState("nope", soteria.StateClosed, soteria.Override{}); !errors.Is(err, soteria.ErrUnknownBreaker) {
+		t.Errorf("ForceState = %v, want ErrUnknownBreaker", err)
+	}
+}
+
+func TestRegistryForceState(t *testing.T) {
+	r := soteria.NewRegistry()
+	cb := r.GetOrCreate("db", soteria.Settings{})
+
+	if err := r.ForceState("db", soteria.StateHalfOpen, soteria.Override{}); err == nil {
+		t.Error("forced half-open")
+	}
+
+	trip(cb)
+	if err := r.ForceState("db", soteria.StateClosed, soteria.Override{}); err != nil {
+		t.Fatal(err)
+	}
+	if cb.State() != soteria.StateClosed {
+		t.Errorf("State = %v, want closed", cb.State())
+	}
+}
+
+func TestRegistryModifySettings(t *testing.T) {
+	r := soteria.NewRegistry()
+	cb := r.GetOrCreate("db", soteria.Settings{Timeout: time.Second})
+
+	err := r.ModifySettings("db", func(st *soteria.Settings) {
+		st.Timeout = time.Minute
+	}, soteria.Override{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cb.Timeout() != time.Minute {
+		t.Errorf("Timeout = %v, want 1m", cb.Timeout())
+	}
+}
@@ -0,0 +1,83 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+type rejection struct {
+	err        error
+	suppressed uint64
+}
+
+func TestOnRejected(t *testing.T) {
+	var got []rejection
+	cb, _ := newBreaker(t, soteria.Settings{
+		Timeout:    time.Minute,
+		OnRejected: func(err error, suppressed uint64) { got = append(got, rejection{err, suppressed}) },
+	})
+	soteriatest.Trip(t, cb, 10)
+
+	succeed(cb)
+	succeed(cb)
+
+	if len(got) != 2 {
+		t.Fatalf("OnRejected called %d times, want 2", len(got))
+	}
+	for _, r := range got {
+		if !errors.Is(r.err, soteria.ErrOpenState) || r.suppressed != 0 {
+			t.Errorf("OnRejected(%v, %d)", r.err, r.suppressed)
+		}
+	}
+}
+
+func TestRejectedInterval(t *testing.T) {
+	var got []rejection
+	cb, clock := newBreaker(t, soteria.Settings{
+		Timeout:          time.Minute,
+		RejectedInterval: time.Second,
+		OnRejected:       func(err error, suppressed uint64) { got = append(got, rejection{err, suppressed}) },
+	})
+	soteriatest.Trip(t, cb, 10)
+
+	for i := 0; i < 100; i++ {
+		succeed(cb)
+		clock.Advance(10 * time.Millisecond)
+	}
+	succeed(cb)
+
+	if len(got) != 2 {
+		t.Fatalf("OnRejected called %d times in a second, want 2", len(got))
+	}
+	if got[0].suppressed != 0 || got[1].suppressed != 99 {
+		t.Errorf("suppressed = %d, %d, want 0, 99", got[0].suppressed, got[1].suppressed)
+	}
+}
+
+func TestRejectedIntervalPerBreaker(t *testing.T) {
+	calls := map[string]int{}
+	settings := soteria.Settings{
+		RejectedInterval: time.Second,
+		OnRejected: func(err error, suppressed uint64) {
+			calls[err.Error()]++
+		},
+	}
+
+	isolated, _ := newBreaker(t, settings)
+	isolated.ForceOpen()
+	open, _ := newBreaker(t, settings)
+	soteriatest.Trip(t, open, 10)
+
+	for i := 0; i < 10; i++ {
+		succeed(isolated)
+		succeed(open)
+	}
+
+	if calls[soteria.ErrIsolated.Error()] != 1 || len(calls) != 2 {
+		t.Errorf("OnRejected calls = %v, want one per breaker", calls)
+	}
+}
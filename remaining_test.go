@@ -0,0 +1,83 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestRemainingOpenTime(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Timeout: time.Minute})
+	if got := cb.RemainingOpenTime(); got != 0 {
+		t.Errorf("RemainingOpenTime = %v while closed", got)
+	}
+
+	trip(cb)
+	clock.Advance(20 * time.Second)
+	if got := cb.RemainingOpenTime(); got != 40*time.Second {
+		t.Errorf("RemainingOpenTime = %v, want 40s", got)
+	}
+
+	clock.Advance(40 * time.Second)
+	if got := cb.RemainingOpenTime(); got != 0 {
+		t.Errorf("RemainingOpenTime = %v once half-open", got)
+	}
+}
+
+func TestOpenStateError(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Timeout: time.Minute})
+	trip(cb)
+	clock.Advance(15 * time.Second)
+
+	_, err := cb.Execute(func() (interface{}, error) { return nil, nil })
+
+	var oe *soteria.OpenStateError
+	if !errors.As(err, &oe) || oe.Remaining != 45*time.Second {
+		t.Fatalf("Execute = %v, want an *OpenStateError with 45s remaining", err)
+	}
+	if !errors.Is(err, soteria.ErrOpenState) {
+		t.Error("errors.Is(err, ErrOpenState) does not hold")
+	}
+	if err == soteria.ErrOpenState {
+		t.Error("the bare sentinel was returned")
+	}
+}
+
+func TestCompositeOpenStateError(t *testing.T) {
+	a, clock := newBreaker(t, soteria.Settings{Timeout: time.Minute})
+	b := soteria.New(soteria.Settings{Timeout: time.Minute, Clock: clock})
+	d := soteria.New(soteria.Settings{Timeout: time.Minute, Clock: clock})
+	c := soteria.NewComposite("abd", 2, a, b, d)
+
+	trip(a)
+	clock.Advance(10 * time.Second)
+	trip(b)
+	clock.Advance(10 * time.Second)
+	trip(d)
+
+	// the Composite closes once the second member, b, half-opens
+	if got := c.RemainingOpenTime(); got != 50*time.Second {
+		t.Errorf("RemainingOpenTime = %v, want 50s", got)
+	}
+
+	_, err := c.Execute(func() (interface{}, error) { return nil, nil })
+	var oe *soteria.OpenStateError
+	if !errors.As(err, &oe) || oe.Remaining != 50*time.Second {
+		t.Errorf("Execute = %v, want an *OpenStateError with 50s remaining", err)
+	}
+}
+
+func TestCompositeHeldOpenByIsolation(t *testing.T) {
+	a, _ := newBreaker(t, soteria.Settings{})
+	c := soteria.NewComposite("a", 0, a)
+	a.ForceOpen()
+
+	if got := c.RemainingOpenTime(); got != 0 {
+		t.Errorf("RemainingOpenTime = %v, want 0 for an isolated member", got)
+	}
+	if _, err := c.Execute(func() (interface{}, error) { return nil, nil }); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Execute = %v, want ErrOpenState", err)
+	}
+}
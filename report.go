@@ -0,0 +1,39 @@
+package soteria
+
+import (
+	"context"
+	"time"
+)
+
+// ReportSuccess accounts for a request that succeeded after latency, for
+// callers that execute requests through their own layer, such as an RPC
+// framework or a connection pool, rather than through Execute.
+//
+// The request is admitted as if it had just started. If the
+// CircuitBreaker would have rejected it, its outcome is not counted and the
+// rejection error is returned, so the caller can stop sending requests.
+func (cb *CircuitBreaker) ReportSuccess(latency time.Duration) error {
+	return cb.report(nil, latency)
+}
+
+// ReportFailure is like ReportSuccess for a request that returned err.
+// err is judged by Settings.IsSuccessful, as with Execute, so it may still
+// count as a success.
+func (cb *CircuitBreaker) ReportFailure(err error, latency time.Duration) error {
+	return cb.report(err, latency)
+}
+
+func (cb *CircuitBreaker) report(err error, latency time.Duration) error {
+	if cb.unprotected() {
+		return nil
+	}
+
+	t, err_r := cb.beforeRequest(context.Background())
+	if err_r != nil {
+		return t.mapError(err_r)
+	}
+
+	t.start, t.latency = time.Time{}, latency
+	cb.afterRequest(t, t.outcomeOf(err), err)
+	return nil
+}
@@ -0,0 +1,78 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestReport(t *testing.T) {
+	var events []soteria.TraceEvent
+	cb, _ := newBreaker(t, soteria.Settings{
+		OnTrace: func(e soteria.TraceEvent) { events = append(events, e) },
+	})
+
+	if err := cb.ReportSuccess(20 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 6; i++ {
+		if err := cb.ReportFailure(errFail, time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+	soteriatest.AssertOpen(t, cb)
+
+	if events[0].Kind != soteria.TraceSuccess || events[0].Latency != 20*time.Millisecond {
+		t.Errorf("first event = %+v, want a success after 20ms", events[0])
+	}
+	if events[1].Kind != soteria.TraceFailure || events[1].Latency != time.Second {
+		t.Errorf("second event = %+v, want a failure after 1s", events[1])
+	}
+
+	if err := cb.ReportSuccess(time.Millisecond); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("ReportSuccess while open = %v, want ErrOpenState", err)
+	}
+	if s := cb.Stats(); s.TotalSuccesses != 0 || s.Requests != 0 {
+		t.Errorf("Stats = %+v after a rejected report", s)
+	}
+}
+
+func TestReportFailureIsSuccessful(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{
+		IsSuccessful: func(err error) bool { return err == nil || errors.Is(err, errFail) },
+	})
+
+	cb.ReportFailure(errFail, 0)
+	if s := cb.Stats(); s.TotalSuccesses != 1 || s.TotalFailures != 0 {
+		t.Errorf("Stats = %+v, want the failure counted as a success", s)
+	}
+}
+
+func TestReportProbes(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{MaxRequests: 2})
+	soteriatest.Trip(t, cb, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	cb.ReportSuccess(0)
+	soteriatest.AssertHalfOpen(t, cb)
+	cb.ReportSuccess(0)
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestExecuteLatency(t *testing.T) {
+	var latency time.Duration
+	cb, clock := newBreaker(t, soteria.Settings{
+		OnTrace: func(e soteria.TraceEvent) { latency = e.Latency },
+	})
+
+	cb.Execute(func() (interface{}, error) {
+		clock.Advance(300 * time.Millisecond)
+		return nil, nil
+	})
+	if latency != 300*time.Millisecond {
+		t.Errorf("Latency = %v, want 300ms", latency)
+	}
+}
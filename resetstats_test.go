@@ -0,0 +1,63 @@
+package soteria_test
+
+import (
+	"testing"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestResetStatsKeepsState(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{})
+	s := cb.Subscribe(16)
+	defer s.Close()
+
+	trip(cb)
+	remaining := cb.RemainingOpenTime()
+	cb.ResetStats("incident closed")
+
+	soteriatest.AssertOpen(t, cb)
+	if st := cb.Stats(); st.TotalFailures != 0 || cb.RemainingOpenTime() != remaining {
+		t.Errorf("Stats = %+v, RemainingOpenTime = %v, want cleared stats and the same expiry", st, cb.RemainingOpenTime())
+	}
+
+	var reset *soteria.TraceEvent
+	for len(s.C) > 0 {
+		if e := <-s.C; e.Kind == soteria.TraceStatsReset {
+			reset = &e
+		}
+	}
+	if reset == nil || reset.Reason != "incident closed" {
+		t.Errorf("stats reset event = %+v", reset)
+	}
+
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+}
+
+func TestResetStatsDropsRequestsInFlight(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	cb.Execute(func() (interface{}, error) {
+		cb.ResetStats("")
+		return nil, errFail
+	})
+	if st := cb.Stats(); st.Requests != 0 || st.TotalFailures != 0 {
+		t.Errorf("Stats = %+v, want the request in flight not counted", st)
+	}
+}
+
+func TestRegistryResetStatsIsAudited(t *testing.T) {
+	r := soteria.NewRegistry()
+	l := soteria.NewMemoryAuditLog(0)
+	r.SetAuditLog(l)
+	cb := r.GetOrCreate("db", soteria.Settings{})
+	trip(cb)
+
+	if err := r.ResetStats("db", soteria.Override{Operator: "alice", Reason: "config change"}); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := l.Entries("db", 0)
+	if cb.Stats().TotalFailures != 0 || len(entries) != 1 || entries[0].Action != soteria.ActionResetStats || entries[0].Reason != "config change" {
+		t.Errorf("Stats = %+v, audit = %+v", cb.Stats(), entries)
+	}
+}
@@ -0,0 +1,113 @@
+package soteria
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Resolver performs DNS lookups through a CircuitBreaker per zone, and
+// serves the last answer that succeeded for a host while the breaker of its
+// zone rejects lookups, so that a resolver outage does not take down every
+// request needing a lookup.
+//
+// Lookups that fail with a *net.DNSError for which IsNotFound holds are
+// answers, and count as successes. Other failures count as failures,
+// unless the Settings given to NewResolver say otherwise.
+type Resolver struct {
+	// Resolver performs the lookups. If nil, net.DefaultResolver is used.
+	Resolver *net.Resolver
+
+	// Zone returns the zone of host. If nil, the last two labels of host
+	// are its zone, such as example.com for api.example.com.
+	Zone func(host string) string
+
+	registry *Registry
+	settings Settings
+
+	mutex sync.Mutex
+	cache map[string]interface{}
+}
+
+// NewResolver returns a Resolver keeping the CircuitBreakers of zones in
+// registry, creating them from settings. The breaker of a zone is named
+// after it, prefixed with settings.Name and a slash if settings.Name is set.
+func NewResolver(registry *Registry, settings Settings) *Resolver {
+	if settings.IsSuccessful == nil {
+		settings.IsSuccessful = isDNSAnswer
+	}
+	return &Resolver{registry: registry, settings: settings, cache: make(map[string]interface{})}
+}
+
+func isDNSAnswer(err error) bool {
+	var de *net.DNSError
+	return err == nil || errors.As(err, &de) && de.IsNotFound
+}
+
+// Breaker returns the CircuitBreaker of zone, creating it if needed.
+func (r *Resolver) Breaker(zone string) *CircuitBreaker {
+	name := zone
+	if r.settings.Name != "" {
+		name = r.settings.Name + "/" + zone
+	}
+	return r.registry.GetOrCreate(name, r.settings)
+}
+
+func (r *Resolver) zone(host string) string {
+	if r.Zone != nil {
+		return r.Zone(host)
+	}
+
+	labels := strings.Split(strings.TrimSuffix(host, "."), ".")
+	if len(labels) > 2 {
+		labels = labels[len(labels)-2:]
+	}
+	return strings.Join(labels, ".")
+}
+
+func (r *Resolver) resolver() *net.Resolver {
+	if r.Resolver == nil {
+		return net.DefaultResolver
+	}
+	return r.Resolver
+}
+
+// LookupHost is like net.Resolver.LookupHost.
+func (r *Resolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return lookup(ctx, r, "host", host, r.resolver().LookupHost)
+}
+
+// LookupIPAddr is like net.Resolver.LookupIPAddr.
+func (r *Resolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return lookup(ctx, r, "ip", host, r.resolver().LookupIPAddr)
+}
+
+// lookup runs fn for host through the breaker of its zone, caching its
+// answers by kind and host.
+func lookup[T any](ctx context.Context, r *Resolver, kind, host string, fn func(ctx context.Context, host string) (T, error)) (T, error) {
+	key := kind + ":" + host
+
+	var answer T
+	admitted := false
+	err := r.Breaker(r.zone(host)).RunContext(ctx, func(ctx context.Context) error {
+		admitted = true
+		var err error
+		answer, err = fn(ctx, host)
+		return err
+	})
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	switch {
+	case err == nil:
+		r.cache[key] = answer
+	case !admitted:
+		if cached, ok := r.cache[key]; ok {
+			return cached.(T), nil
+		}
+	}
+	return answer, err
+}
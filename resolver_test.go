@@ -0,0 +1,131 @@
+package soteria_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+// stubResolver answers lookups itself, through the Dial of a net.Resolver,
+// failing every query while *down is true. See answer.
+func stubResolver(down *bool) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			if *down {
+				return nil, errors.New("resolver unreachable")
+			}
+			client, server := net.Pipe()
+			go answer(server)
+			return client, nil
+		},
+	}
+}
+
+func TestResolverServesLastKnownGood(t *testing.T) {
+	down := false
+	r := soteria.NewResolver(soteria.NewRegistry(), soteria.Settings{Name: "dns"})
+	r.Resolver = stubResolver(&down)
+
+	addrs, err := r.LookupHost(context.Background(), "api.example.com")
+	if err != nil || !reflect.DeepEqual(addrs, []string{"192.0.2.1"}) {
+		t.Fatalf("LookupHost = %v, %v", addrs, err)
+	}
+
+	down = true
+	for i := 0; i < 6; i++ {
+		if _, err := r.LookupHost(context.Background(), "api.example.com"); err == nil {
+			t.Fatal("LookupHost succeeded with the resolver down")
+		}
+	}
+	soteriatest.AssertOpen(t, r.Breaker("example.com"))
+
+	addrs, err = r.LookupHost(context.Background(), "api.example.com")
+	if err != nil || !reflect.DeepEqual(addrs, []string{"192.0.2.1"}) {
+		t.Errorf("LookupHost while open = %v, %v, want the cached answer", addrs, err)
+	}
+	if _, err := r.LookupHost(context.Background(), "www.example.com"); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("LookupHost of an uncached host while open = %v, want ErrOpenState", err)
+	}
+	if _, err := r.LookupIPAddr(context.Background(), "api.example.com"); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("LookupIPAddr while open = %v, want ErrOpenState, answers are cached by kind", err)
+	}
+}
+
+func TestResolverNotFoundIsAnAnswer(t *testing.T) {
+	down := false
+	r := soteria.NewResolver(soteria.NewRegistry(), soteria.Settings{})
+	r.Resolver = stubResolver(&down)
+
+	for i := 0; i < 10; i++ {
+		var de *net.DNSError
+		if _, err := r.LookupHost(context.Background(), "missing.example.org"); !errors.As(err, &de) || !de.IsNotFound {
+			t.Fatalf("LookupHost = %v, want not found", err)
+		}
+	}
+	soteriatest.AssertClosed(t, r.Breaker("example.org"))
+}
+
+func TestResolverZone(t *testing.T) {
+	r := soteria.NewResolver(soteria.NewRegistry(), soteria.Settings{})
+	r.Resolver = stubResolver(new(bool))
+	r.Zone = func(host string) string { return "all" }
+
+	r.Breaker("all").ForceOpen()
+	if _, err := r.LookupHost(context.Background(), "a.example.net"); !errors.Is(err, soteria.ErrIsolated) {
+		t.Errorf("LookupHost = %v, want the breaker of the custom zone", err)
+	}
+}
+
+// answer serves DNS over stream framing on conn: A queries for names
+// beginning with "missing" fail with NXDOMAIN, the others are answered with
+// 192.0.2.1, and every other query type has no answer.
+func answer(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		var size [2]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		query := make([]byte, binary.BigEndian.Uint16(size[:]))
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+
+		// the question ends with its type and class, after the name
+		end := 12
+		for query[end] != 0 {
+			end += int(query[end]) + 1
+		}
+		name := string(query[13 : 13+query[12]])
+		qtype := binary.BigEndian.Uint16(query[end+1:])
+		end += 5
+
+		resp := append([]byte(nil), query[:end]...)
+		binary.BigEndian.PutUint16(resp[2:], 0x8180) // response, recursion desired and available
+		binary.BigEndian.PutUint16(resp[6:], 0)      // answers
+		binary.BigEndian.PutUint32(resp[8:], 0)      // authority and additional records
+
+		switch {
+		case strings.HasPrefix(name, "missing"):
+			resp[3] |= 3 // NXDOMAIN
+		case qtype == 1:
+			resp[7] = 1
+			resp = append(resp, 0xc0, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 192, 0, 2, 1)
+		}
+
+		binary.BigEndian.PutUint16(size[:], uint16(len(resp)))
+		if _, err := conn.Write(append(size[:], resp...)); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package soteria
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Retry retries the failed attempts of a request. It never retries
+// rejections of a CircuitBreaker nor requests whose context is done.
+type Retry struct {
+	// Attempts is the maximum number of attempts, including the first.
+	// If less than 2, requests are not retried.
+	Attempts int
+
+	// Backoff is the delay after the first failed attempt, doubled after
+	// every further one up to MaxBackoff. If 0, 100 milliseconds and 2
+	// seconds are used.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// Retryable reports whether an attempt failing with err is retried.
+	// If nil, every failure is.
+	Retryable func(err error) bool
+}
+
+// Do calls fn until it succeeds, fails with an error that is not
+// retryable, or Attempts is reached, and returns the last error.
+func (r Retry) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	for n := 1; ; n++ {
+		err := fn(ctx)
+		if err == nil || n >= r.Attempts || !r.retryable(ctx, err) {
+			return err
+		}
+
+		if err := sleep(ctx, r.delay(n)); err != nil {
+			return err
+		}
+	}
+}
+
+func (r Retry) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || IsRejected(err) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return r.Retryable == nil || r.Retryable(err)
+}
+
+func (r Retry) delay(n int) time.Duration {
+	min, max := r.Backoff, r.MaxBackoff
+	if min <= 0 {
+		min = 100 * time.Millisecond
+	}
+	if max <= 0 {
+		max = 2 * time.Second
+	}
+	return backoff(nil, uint32(n), min, max)
+}
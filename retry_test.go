@@ -0,0 +1,42 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestRetry(t *testing.T) {
+	errPermanent := errors.New("permanent")
+	r := soteria.Retry{
+		Attempts:  3,
+		Backoff:   time.Millisecond,
+		Retryable: func(err error) bool { return !errors.Is(err, errPermanent) },
+	}
+
+	tests := []struct {
+		name  string
+		errs  []error
+		calls int
+	}{
+		{"success", []error{nil}, 1},
+		{"retried", []error{errFail, nil}, 2},
+		{"exhausted", []error{errFail, errFail, errFail}, 3},
+		{"not retryable", []error{errPermanent}, 1},
+		{"rejected", []error{soteria.ErrTooManyRequests}, 1},
+	}
+
+	for _, tt := range tests {
+		calls := 0
+		err := r.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return tt.errs[calls-1]
+		})
+		if calls != tt.calls || err != tt.errs[calls-1] {
+			t.Errorf("%s: %d calls returning %v, want %d", tt.name, calls, err, tt.calls)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestRun(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	if err := cb.Run(func() error { return nil }); err != nil {
+		t.Errorf("Run = %v", err)
+	}
+	if err := cb.Run(func() error { return errFail }); err != errFail {
+		t.Errorf("Run = %v, want errFail", err)
+	}
+
+	st := cb.Stats()
+	if st.TotalSuccesses != 1 || st.TotalFailures != 1 {
+		t.Errorf("Stats = %+v", st)
+	}
+}
+
+func TestRunRejected(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	trip(cb)
+
+	called := false
+	err := cb.Run(func() error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, soteria.ErrOpenState) || called {
+		t.Errorf("Run = %v, called %v", err, called)
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{Name: "run"})
+
+	err := cb.RunContext(context.Background(), func(ctx context.Context) error {
+		if info, ok := soteria.InfoFromContext(ctx); !ok || info.Name != "run" {
+			t.Errorf("InfoFromContext = %+v, %v", info, ok)
+		}
+		return errFail
+	})
+	if err != errFail {
+		t.Errorf("RunContext = %v, want errFail", err)
+	}
+	if got := cb.Stats().TotalFailures; got != 1 {
+		t.Errorf("TotalFailures = %d, want 1", got)
+	}
+}
@@ -0,0 +1,41 @@
+package soteria_test
+
+import (
+	"testing"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestSampling(t *testing.T) {
+	kinds := make(map[string]int)
+	cb, _ := newBreaker(t, soteria.Settings{
+		OnTrace: func(e soteria.TraceEvent) { kinds[e.Kind]++ },
+		Sampling: map[string]float64{
+			soteria.TraceSuccess:    0.1,
+			soteria.TraceFailure:    0,
+			soteria.TraceTransition: 0,
+		},
+	})
+	s := cb.Subscribe(16)
+	defer s.Close()
+
+	for i := 0; i < 10000; i++ {
+		succeed(cb)
+	}
+	soteriatest.Trip(t, cb, 10)
+	succeed(cb)
+
+	if n := kinds[soteria.TraceSuccess]; n < 800 || n > 1200 {
+		t.Errorf("%d successes traced, want about 1000", n)
+	}
+	if kinds[soteria.TraceFailure] != 0 {
+		t.Errorf("%d failures traced, want none", kinds[soteria.TraceFailure])
+	}
+	if kinds[soteria.TraceTransition] != 1 || kinds[soteria.TraceRejected] != 1 {
+		t.Errorf("traced %v, want every transition and rejection", kinds)
+	}
+	if len(s.C) == 0 {
+		t.Error("no sampled event reached the subscription")
+	}
+}
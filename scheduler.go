@@ -0,0 +1,160 @@
+package soteria
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// Scheduler runs periodic background work, such as exporting or watching,
+// on a single goroutine, so that a process with thousands of breakers does
+// not run a ticker for each of them. The goroutine only runs while tasks
+// are scheduled. The zero Scheduler is ready to use; every Registry has one,
+// see Registry.Scheduler.
+type Scheduler struct {
+	mutex   sync.Mutex
+	idle    *sync.Cond // signaled whenever a task completes a run
+	tasks   tasks
+	wake    chan struct{}
+	running bool
+	current *task
+}
+
+type task struct {
+	next     time.Time
+	interval time.Duration
+	fn       func()
+	index    int // in Scheduler.tasks, -1 once stopped
+}
+
+// Every runs fn every interval, starting interval from now, until stop is
+// called. Tasks run one at a time, so fn must be quick or hand its work
+// off; a run that is late is not made up for. Every panics if interval is
+// not positive, as time.NewTicker does. stop waits for a run of fn
+// in progress, and must not be called from fn.
+func (s *Scheduler) Every(interval time.Duration, fn func()) (stop func()) {
+	if interval <= 0 {
+		panic("soteria: non-positive interval for Scheduler.Every")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.idle == nil {
+		s.idle = sync.NewCond(&s.mutex)
+		s.wake = make(chan struct{}, 1)
+	}
+
+	t := &task{next: time.Now().Add(interval), interval: interval, fn: fn}
+	heap.Push(&s.tasks, t)
+	if !s.running {
+		s.running = true
+		go s.run()
+	} else {
+		s.notify()
+	}
+
+	return func() { s.stop(t) }
+}
+
+func (s *Scheduler) stop(t *task) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if t.index >= 0 {
+		heap.Remove(&s.tasks, t.index)
+		t.index = -1
+		s.notify()
+	}
+	for s.current == t {
+		s.idle.Wait()
+	}
+}
+
+// Stop cancels every task of s, waiting for a run in progress, as the stop
+// functions of Every do. Tasks can be scheduled again afterwards. Stop must
+// not be called from a task.
+func (s *Scheduler) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for len(s.tasks) > 0 {
+		heap.Pop(&s.tasks).(*task).index = -1
+	}
+	if s.idle == nil {
+		return
+	}
+	s.notify()
+	for s.current != nil {
+		s.idle.Wait()
+	}
+}
+
+// notify wakes the goroutine of s up to look at the next task again.
+// s.mutex must be held.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) run() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for len(s.tasks) > 0 {
+		t := s.tasks[0]
+		now := time.Now()
+		if wait := t.next.Sub(now); wait > 0 {
+			s.mutex.Unlock()
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-s.wake:
+				timer.Stop()
+			}
+			s.mutex.Lock()
+			continue
+		}
+
+		t.next = t.next.Add(t.interval)
+		if !t.next.After(now) {
+			t.next = now.Add(t.interval)
+		}
+		heap.Fix(&s.tasks, t.index)
+
+		s.current = t
+		s.mutex.Unlock()
+		t.fn()
+		s.mutex.Lock()
+		s.current = nil
+		s.idle.Broadcast()
+	}
+	s.running = false
+}
+
+// tasks is a heap of the tasks of a Scheduler by their next run.
+type tasks []*task
+
+func (h tasks) Len() int           { return len(h) }
+func (h tasks) Less(i, j int) bool { return h[i].next.Before(h[j].next) }
+
+func (h tasks) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *tasks) Push(x interface{}) {
+	t := x.(*task)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *tasks) Pop() interface{} {
+	old := *h
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return t
+}
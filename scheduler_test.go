@@ -0,0 +1,30 @@
+package soteria_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestScheduler(t *testing.T) {
+	var s soteria.Scheduler
+	var fast, slow atomic.Int32
+
+	stopFast := s.Every(time.Millisecond, func() { fast.Add(1) })
+	stopSlow := s.Every(time.Hour, func() { slow.Add(1) })
+	defer stopSlow()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for fast.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	stopFast()
+
+	n := fast.Load()
+	time.Sleep(10 * time.Millisecond)
+	if n < 3 || fast.Load() != n || slow.Load() != 0 {
+		t.Errorf("fast ran %d then %d times, slow %d, want no run after stop", n, fast.Load(), slow.Load())
+	}
+}
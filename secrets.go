@@ -0,0 +1,70 @@
+package soteria
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SecretCache fetches secrets, such as from Vault or a cloud secret
+// manager, through a CircuitBreaker. While the CircuitBreaker rejects
+// fetches, the last value fetched for a path is served instead, as long as
+// it is no older than MaxStaleness, so that a blip of the secret store
+// does not stop the refresh of tokens built from it.
+//
+// If MaxStaleness is 0, cached values are served however old they are.
+// Their age is measured with the Clock of the CircuitBreaker.
+type SecretCache[V any] struct {
+	Breaker      *CircuitBreaker
+	Fetch        func(ctx context.Context, path string) (V, error)
+	MaxStaleness time.Duration
+
+	mutex   sync.Mutex
+	secrets map[string]cachedSecret[V]
+}
+
+type cachedSecret[V any] struct {
+	value   V
+	fetched time.Time
+}
+
+// Get fetches the secret at path, or returns its cached value if the
+// Breaker rejects the fetch. Failed fetches return their error; they are
+// not hidden by cached values.
+func (c *SecretCache[V]) Get(ctx context.Context, path string) (V, error) {
+	var value V
+	admitted := false
+	err := c.Breaker.RunContext(ctx, func(ctx context.Context) error {
+		admitted = true
+		var err error
+		value, err = c.Fetch(ctx, path)
+		return err
+	})
+
+	now := c.Breaker.now()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	switch {
+	case err == nil:
+		if c.secrets == nil {
+			c.secrets = make(map[string]cachedSecret[V])
+		}
+		c.secrets[path] = cachedSecret[V]{value: value, fetched: now}
+	case !admitted:
+		s, ok := c.secrets[path]
+		if ok && (c.MaxStaleness == 0 || now.Sub(s.fetched) <= c.MaxStaleness) {
+			return s.value, nil
+		}
+	}
+	return value, err
+}
+
+// Forget drops the cached value of path, such as once the secret was
+// revoked.
+func (c *SecretCache[V]) Forget(path string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.secrets, path)
+}
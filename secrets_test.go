@@ -0,0 +1,68 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestSecretCacheServesStale(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Timeout: time.Hour})
+
+	fetches := 0
+	down := false
+	c := &soteria.SecretCache[string]{
+		Breaker:      cb,
+		MaxStaleness: 10 * time.Minute,
+		Fetch: func(ctx context.Context, path string) (string, error) {
+			fetches++
+			if down {
+				return "", errFail
+			}
+			return path + "-v1", nil
+		},
+	}
+
+	if v, err := c.Get(context.Background(), "db/password"); v != "db/password-v1" || err != nil {
+		t.Fatalf("Get = %q, %v", v, err)
+	}
+
+	down = true
+	if _, err := c.Get(context.Background(), "db/password"); err != errFail {
+		t.Errorf("Get with the store failing = %v, want its error", err)
+	}
+	soteriatest.Trip(t, cb, 10)
+
+	clock.Advance(5 * time.Minute)
+	n := fetches
+	if v, err := c.Get(context.Background(), "db/password"); v != "db/password-v1" || err != nil || fetches != n {
+		t.Errorf("Get while open = %q, %v, want the cached secret without a fetch", v, err)
+	}
+	if _, err := c.Get(context.Background(), "api/key"); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Get of an uncached secret while open = %v, want ErrOpenState", err)
+	}
+
+	clock.Advance(6 * time.Minute)
+	if _, err := c.Get(context.Background(), "db/password"); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Get past MaxStaleness = %v, want ErrOpenState", err)
+	}
+}
+
+func TestSecretCacheForget(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	c := &soteria.SecretCache[[]byte]{
+		Breaker: cb,
+		Fetch:   func(ctx context.Context, path string) ([]byte, error) { return []byte("s"), nil },
+	}
+
+	c.Get(context.Background(), "k")
+	c.Forget("k")
+	cb.ForceOpen()
+	if _, err := c.Get(context.Background(), "k"); !errors.Is(err, soteria.ErrIsolated) {
+		t.Errorf("Get of a forgotten secret = %v, want ErrIsolated", err)
+	}
+}
@@ -0,0 +1,151 @@
+package soteria
+
+import (
+	"sort"
+	"sync"
+)
+
+// shadow evaluates alternative Settings on the outcomes of a live
+// CircuitBreaker without enforcing them. See CircuitBreaker.AddShadow.
+type shadow struct {
+	mutex    sync.Mutex
+	clock    replayClock
+	category Category
+	cb       *CircuitBreaker
+	stats    ShadowStats
+
+	onDivergence func(d Divergence)
+}
+
+// ShadowStats tells what a shadow policy would have done.
+type ShadowStats struct {
+	Name  string `json:"name"`
+	State State  `json:"state"`
+	// Requests is the number of requests the policy has seen.
+	Requests uint64 `json:"requests"`
+	// WouldReject is the number of requests the live CircuitBreaker
+	// admitted that the policy would have rejected.
+	WouldReject uint64 `json:"would_reject"`
+	// WouldAdmit is the number of requests the live CircuitBreaker rejected
+	// that the policy would have admitted. Their outcome is unknown; the
+	// policy counts them as successes.
+	WouldAdmit uint64 `json:"would_admit"`
+	// Trips is the number of times the policy opened.
+	Trips uint64 `json:"trips"`
+}
+
+// AddShadow attaches a shadow policy built from settings to cb, replacing
+// any of the same name. Shadow policies are CircuitBreakers that see every
+// success, failure and rejection of cb from then on, on its clock, without
+// enforcing anything, so that alternative thresholds can be tried on live
+// traffic and compared through Shadows. Settings.Clock,
+// Settings.IsSuccessful, Settings.Classifier and Settings.OnTrace are
+// overridden, as for Replay: whether a request failed, and its Category,
+// are those of cb.
+func (cb *CircuitBreaker) AddShadow(name string, settings Settings) {
+	cb.addShadow(name, settings, nil)
+}
+
+func (cb *CircuitBreaker) addShadow(name string, settings Settings, onDivergence func(d Divergence)) {
+	if cb.unprotected() {
+		return
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	s := &shadow{stats: ShadowStats{Name: name}, onDivergence: onDivergence}
+	s.clock.now = cb.clock.Now()
+
+	settings.Name = cb.name + "/" + name
+	settings.Clock = &s.clock
+	settings.IsSuccessful = defaultIsSuccessful
+	settings.Classifier = func(error) Category {
+		return s.category
+	}
+	settings.OnTrace = func(e TraceEvent) {
+		if e.Kind == TraceTransition && e.To == StateOpen {
+			s.stats.Trips++
+		}
+	}
+	s.cb = New(settings)
+
+	if cb.shadows == nil {
+		cb.shadows = make(map[string]*shadow)
+	}
+	cb.shadows[name] = s
+}
+
+// RemoveShadow detaches the named shadow policy from cb.
+func (cb *CircuitBreaker) RemoveShadow(name string) {
+	if cb.unprotected() {
+		return
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	delete(cb.shadows, name)
+}
+
+// Shadows returns the stats of the shadow policies of cb, sorted by name.
+func (cb *CircuitBreaker) Shadows() []ShadowStats {
+	if cb.unprotected() {
+		return nil
+	}
+	cb.mutex.Lock()
+	shadows := make([]*shadow, 0, len(cb.shadows))
+	for _, s := range cb.shadows {
+		shadows = append(shadows, s)
+	}
+	cb.mutex.Unlock()
+
+	stats := make([]ShadowStats, 0, len(shadows))
+	for _, s := range shadows {
+		stats = append(stats, s.snapshot())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+func (s *shadow) snapshot() ShadowStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stats := s.stats
+	stats.State = s.cb.State()
+	return stats
+}
+
+// observe feeds a success, failure or rejection of the live CircuitBreaker
+// to s, and reports whether s decided otherwise on it.
+func (s *shadow) observe(e TraceEvent) (d Divergence, diverged bool) {
+	var outcome error
+	switch e.Kind {
+	case TraceSuccess, TraceRejected:
+	case TraceFailure:
+		outcome = errReplayFailure
+	default:
+		return d, false
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.clock.now = e.Time
+	s.category = e.Category
+	s.stats.Requests++
+
+	admitted := false
+	s.cb.Execute(func() (interface{}, error) {
+		admitted = true
+		return nil, outcome
+	})
+
+	switch {
+	case !admitted && e.Kind != TraceRejected:
+		s.stats.WouldReject++
+	case admitted && e.Kind == TraceRejected:
+		s.stats.WouldAdmit++
+	default:
+		return d, false
+	}
+	return Divergence{Shadow: s.stats.Name, Time: e.Time, Live: e.From, State: s.cb.State(), WouldReject: !admitted}, true
+}
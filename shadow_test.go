@@ -0,0 +1,79 @@
+package soteria_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestShadows(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{Timeout: time.Minute})
+	cb.AddShadow("strict", soteria.Settings{
+		Timeout:     time.Minute,
+		ReadyToTrip: func(stats soteria.Stats) bool { return stats.ConsecutiveFailures >= 2 },
+	})
+	cb.AddShadow("lenient", soteria.Settings{
+		ReadyToTrip: func(stats soteria.Stats) bool { return false },
+	})
+
+	for i := 0; i < 4; i++ {
+		fail(cb)
+	}
+
+	shadows := cb.Shadows()
+	if len(shadows) != 2 || shadows[0].Name != "lenient" || shadows[1].Name != "strict" {
+		t.Fatalf("Shadows = %+v", shadows)
+	}
+	if s := shadows[1]; s.State != soteria.StateOpen || s.Trips != 1 || s.Requests != 4 || s.WouldReject != 2 {
+		t.Errorf("strict = %+v, want open after one trip, rejecting 2 of 4", s)
+	}
+
+	for i := 0; i < 3; i++ {
+		fail(cb)
+	}
+	succeed(cb)
+
+	shadows = cb.Shadows()
+	if s := shadows[0]; s.State != soteria.StateClosed || s.Requests != 8 || s.WouldAdmit != 2 || s.WouldReject != 0 {
+		t.Errorf("lenient = %+v, want closed, admitting the 2 rejected requests", s)
+	}
+	if st := soteria.Status(cb, true); len(st.Shadows) != 2 {
+		t.Errorf("Status.Shadows = %+v", st.Shadows)
+	}
+
+	cb.RemoveShadow("strict")
+	if shadows := cb.Shadows(); len(shadows) != 1 {
+		t.Errorf("Shadows after RemoveShadow = %+v", shadows)
+	}
+}
+
+func TestMigration(t *testing.T) {
+	var divergences []soteria.Divergence
+	m := soteria.NewMigration(
+		soteria.Settings{Name: "payments"},
+		soteria.Settings{ReadyToTrip: func(stats soteria.Stats) bool { return stats.ConsecutiveFailures >= 2 }},
+		func(d soteria.Divergence) { divergences = append(divergences, d) },
+	)
+
+	for i := 0; i < 3; i++ {
+		fail(m.CircuitBreaker)
+	}
+
+	if len(divergences) != 1 {
+		t.Fatalf("divergences = %+v, want the third failure", divergences)
+	}
+	if d := divergences[0]; !d.WouldReject || d.Live != soteria.StateClosed || d.State != soteria.StateOpen || d.Shadow != "candidate" {
+		t.Errorf("divergence = %+v", d)
+	}
+	if c := m.Candidate(); c.Requests != 3 || c.WouldReject != 1 || c.Trips != 1 {
+		t.Errorf("Candidate = %+v", c)
+	}
+
+	m.Promote()
+	fail(m.CircuitBreaker)
+	fail(m.CircuitBreaker)
+	if m.State() != soteria.StateOpen || len(m.Shadows()) != 0 || m.Name() != "payments" {
+		t.Errorf("State = %v with %d shadows after Promote, want the candidate enforced", m.State(), len(m.Shadows()))
+	}
+}
@@ -0,0 +1,38 @@
+package soteria
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrShed is returned for requests rejected by the load shedding of
+// SetShedPercent.
+var ErrShed = errors.New("request shed")
+
+// SetShedPercent makes the CircuitBreaker reject percent, between 0 and
+// 100, of the requests it would admit, picked at random, with ErrShed, for
+// reducing traffic gradually during a capacity incident whatever its
+// state. Shed requests are not counted in Stats. 0 stops shedding.
+func (cb *CircuitBreaker) SetShedPercent(percent float64) error {
+	if math.IsNaN(percent) || percent < 0 || percent > 100 {
+		return fmt.Errorf("soteria: shed percentage %v out of [0, 100]", percent)
+	}
+	if cb.unprotected() {
+		return nil
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	cb.shed = percent / 100
+	return nil
+}
+
+// ShedPercent returns the percentage of requests shed, see SetShedPercent.
+func (cb *CircuitBreaker) ShedPercent() float64 {
+	if cb.unprotected() {
+		return 0
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return cb.shed * 100
+}
@@ -0,0 +1,57 @@
+package soteria_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestShedPercent(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{Rand: rand.NewSource(1)})
+	if err := cb.SetShedPercent(101); err == nil {
+		t.Error("SetShedPercent(101) succeeded")
+	}
+	if err := cb.SetShedPercent(30); err != nil {
+		t.Fatal(err)
+	}
+
+	shed := 0
+	for i := 0; i < 1000; i++ {
+		if err := succeed(cb); errors.Is(err, soteria.ErrShed) && soteria.IsRejected(err) {
+			shed++
+		}
+	}
+	if shed < 250 || shed > 350 {
+		t.Errorf("shed %d of 1000 requests, want about 300", shed)
+	}
+	if s := cb.Stats(); s.Requests != uint32(1000-shed) {
+		t.Errorf("Requests = %d, want the shed requests not counted", s.Requests)
+	}
+
+	cb.SetShedPercent(0)
+	if err := succeed(cb); err != nil {
+		t.Errorf("Execute = %v after shedding stopped", err)
+	}
+}
+
+func TestAdminHandlerShed(t *testing.T) {
+	r, h := newAdmin(t)
+
+	w := serve(h, http.MethodPost, "/breakers/db/shed", url.Values{"percent": {"40"}, "operator": {"alice"}})
+	var status soteria.BreakerStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil || w.Code != http.StatusOK || status.ShedPercent != 40 {
+		t.Fatalf("POST shed = %d %s", w.Code, w.Body)
+	}
+	if cb, _ := r.Get("db"); cb.ShedPercent() != 40 {
+		t.Errorf("ShedPercent = %v", cb.ShedPercent())
+	}
+
+	if w := serve(h, http.MethodPost, "/breakers/db/shed", url.Values{"percent": {"lots"}}); w.Code == http.StatusOK {
+		t.Errorf("POST shed with an invalid percent = %d", w.Code)
+	}
+}
@@ -0,0 +1,81 @@
+package soteria
+
+import (
+	"context"
+	"errors"
+)
+
+// Close ends the Subscriptions of cb, closing their C after the events
+// they buffered, so that the goroutines watching cb, such as those of
+// DeferredQueue.Watch, deliver the pending events and return. Later
+// Subscriptions are closed right away. cb keeps guarding requests. Close
+// is safe to call more than once.
+func (cb *CircuitBreaker) Close() {
+	if cb.unprotected() {
+		return
+	}
+
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.closed = true
+	for s := range cb.subscribers {
+		delete(cb.subscribers, s)
+		close(s.c)
+	}
+}
+
+type shutdownHook struct {
+	fn func(ctx context.Context) error
+}
+
+// OnShutdown registers fn to be called by Shutdown, such as to persist the
+// statuses of the breakers of r, until the returned function is called.
+func (r *Registry) OnShutdown(fn func(ctx context.Context) error) (remove func()) {
+	h := &shutdownHook{fn: fn}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.hooks = append(r.hooks, h)
+
+	return func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+		for i, hook := range r.hooks {
+			if hook == h {
+				r.hooks = append(r.hooks[:i], r.hooks[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Shutdown stops the background work on the breakers of r, for a clean
+// exit of the process. It calls the functions registered with OnShutdown,
+// latest first, such as the final export of a started telemetry.Exporter,
+// then stops the Scheduler of r and closes every breaker of r, see
+// CircuitBreaker.Close. Functions are no longer called once ctx is done.
+// Shutdown returns the errors of the functions and of ctx, joined.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.mutex.Lock()
+	hooks := r.hooks
+	r.hooks = nil
+	r.mutex.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := hooks[i].fn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	r.scheduler.Stop()
+	for _, cb := range r.Breakers() {
+		cb.Close()
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,92 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestClose(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	s := cb.SubscribeTransitions(4)
+	trip(cb)
+
+	cb.Close()
+	cb.Close()
+
+	var kinds []string
+	for e := range s.C {
+		kinds = append(kinds, e.Kind)
+	}
+	if len(kinds) != 1 || kinds[0] != soteria.TraceTransition {
+		t.Errorf("events after Close = %v, want the buffered transition", kinds)
+	}
+	s.Close()
+
+	if _, ok := <-cb.Subscribe(1).C; ok {
+		t.Error("Subscribe after Close delivered an event, want C closed")
+	}
+	if err := succeed(cb); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("request after Close = %v, want ErrOpenState", err)
+	}
+}
+
+func TestRegistryShutdown(t *testing.T) {
+	r := soteria.NewRegistry()
+	cb := r.GetOrCreate("db", soteria.Settings{})
+	s := cb.Subscribe(1)
+
+	var runs atomic.Int32
+	r.Scheduler().Every(time.Millisecond, func() { runs.Add(1) })
+
+	var order []string
+	errPersist := errors.New("disk full")
+	r.OnShutdown(func(ctx context.Context) error {
+		order = append(order, "persist")
+		return errPersist
+	})
+	r.OnShutdown(func(ctx context.Context) error {
+		order = append(order, "export")
+		return nil
+	})
+	remove := r.OnShutdown(func(ctx context.Context) error {
+		order = append(order, "removed")
+		return nil
+	})
+	remove()
+
+	if err := r.Shutdown(context.Background()); !errors.Is(err, errPersist) {
+		t.Errorf("Shutdown = %v, want the error of the hook", err)
+	}
+	if got := strings.Join(order, " "); got != "export persist" {
+		t.Errorf("hooks ran as %q, want the latest first", got)
+	}
+	if _, ok := <-s.C; ok {
+		t.Error("subscription still open after Shutdown")
+	}
+
+	n := runs.Load()
+	time.Sleep(10 * time.Millisecond)
+	if runs.Load() != n {
+		t.Error("scheduled task ran after Shutdown")
+	}
+}
+
+func TestRegistryShutdownCanceled(t *testing.T) {
+	r := soteria.NewRegistry()
+	r.OnShutdown(func(ctx context.Context) error {
+		t.Error("hook called with a done context")
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := r.Shutdown(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Shutdown = %v, want context.Canceled", err)
+	}
+}
@@ -1,45 +1,83 @@
 package soteria
 
 import (
-	"github.com/jtejido/persephone"
+	"context"
 	"errors"
+	"fmt"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
 const (
-	StateClosed persephone.State = iota
+	StateClosed State = iota
 	StateHalfOpen
 	StateOpen
+	// StateIsolated is the state of a CircuitBreaker forced open by
+	// ForceOpen. It rejects every request until ForceClose or Reset.
+	StateIsolated
 )
 
 const (
-	Ok persephone.Input = iota
+	Ok Input = iota
 	NotOk
+	Trip
+	Expire
+	Recover
+	Force
+	Reset
 )
 
 const defaultTimeout = time.Duration(60) * time.Second
 
+// Rejections. Errors returned for rejected requests may carry more
+// detail, such as *OpenStateError; compare them with errors.Is.
 var (
 	ErrTooManyRequests = errors.New("too many requests")
-	ErrOpenState = errors.New("circuit breaker is open")
-	states persephone.States
-	inputs persephone.Inputs
+	ErrOpenState       = errors.New("circuit breaker is open")
 )
 
 type Stats struct {
-	Requests             uint32
-	TotalSuccesses       uint32
-	TotalFailures        uint32
-	ConsecutiveSuccesses uint32
-	ConsecutiveFailures  uint32
+	Requests             uint32 `json:"requests"`
+	TotalSuccesses       uint32 `json:"total_successes"`
+	TotalFailures        uint32 `json:"total_failures"`
+	ConsecutiveSuccesses uint32 `json:"consecutive_successes"`
+	ConsecutiveFailures  uint32 `json:"consecutive_failures"`
+
+	// WeightedRequests, WeightedSuccesses and WeightedFailures are like
+	// Requests, TotalSuccesses and TotalFailures, adding up the costs of
+	// the requests. See WithCost.
+	WeightedRequests  float64 `json:"weighted_requests,omitempty"`
+	WeightedSuccesses float64 `json:"weighted_successes,omitempty"`
+	WeightedFailures  float64 `json:"weighted_failures,omitempty"`
+
+	// Overflows counts the requests rejected in excess of the half-open
+	// probes, with Settings.HalfOpenOverflow set to OverflowCounted.
+	Overflows uint32 `json:"overflows,omitempty"`
+
+	// Generation identifies the generation the counts belong to. Every new
+	// generation is traced as a TraceGenerationRolled event.
+	Generation uint64 `json:"generation"`
+
+	// FailuresByCategory breaks TotalFailures down by the Category
+	// Settings.Classifier assigned to each failure.
+	FailuresByCategory map[Category]uint32 `json:"failures_by_category,omitempty"`
+
+	// Windows holds the rolling windows of Settings.Windows, in order.
+	// Unlike the counts above they are not cleared every Interval.
+	Windows []WindowStats `json:"windows,omitempty"`
 }
 
 func (c *Stats) request() {
 	atomic.AddUint32(&c.Requests, 1)
 }
 
+// release undoes request for a request whose outcome is not counted.
+func (c *Stats) release() {
+	atomic.AddUint32(&c.Requests, ^uint32(0))
+}
+
 func (c *Stats) success() {
 	atomic.AddUint32(&c.TotalSuccesses, 1)
 	atomic.AddUint32(&c.ConsecutiveSuccesses, 1)
@@ -53,74 +91,407 @@ func (c *Stats) failure() {
 	atomic.AddUint32(&c.ConsecutiveSuccesses, -c.ConsecutiveSuccesses)
 }
 
+// weigh adds the cost of a request, admitted or with the given outcome.
+func (c *Stats) weigh(cost float64, o outcome) {
+	switch o {
+	case outcomeSuccess:
+		c.WeightedSuccesses += cost
+	case outcomeFailure:
+		c.WeightedFailures += cost
+	default:
+		c.WeightedRequests += cost
+	}
+}
+
+func (c *Stats) categorize(category Category) {
+	if c.FailuresByCategory == nil {
+		c.FailuresByCategory = make(map[Category]uint32)
+	}
+	c.FailuresByCategory[category]++
+}
+
+// outcomes returns the number of requests that completed, as opposed to
+// Requests, which also counts those still in flight.
+func (c *Stats) outcomes() uint32 {
+	return c.TotalSuccesses + c.TotalFailures
+}
+
+// snapshot returns a copy of c that shares no memory with it.
+func (c *Stats) snapshot() Stats {
+	s := *c
+	if c.FailuresByCategory != nil {
+		s.FailuresByCategory = make(map[Category]uint32, len(c.FailuresByCategory))
+		for category, n := range c.FailuresByCategory {
+			s.FailuresByCategory[category] = n
+		}
+	}
+	s.Windows = append([]WindowStats(nil), c.Windows...)
+	return s
+}
+
 func (c *Stats) clear() {
+	c.FailuresByCategory = nil
+	c.WeightedRequests, c.WeightedSuccesses, c.WeightedFailures = 0, 0, 0
 	atomic.AddUint32(&c.Requests, -c.Requests)
 	atomic.AddUint32(&c.TotalSuccesses, -c.TotalSuccesses)
 	atomic.AddUint32(&c.TotalFailures, -c.TotalFailures)
 	atomic.AddUint32(&c.ConsecutiveSuccesses, -c.ConsecutiveSuccesses)
 	atomic.AddUint32(&c.ConsecutiveFailures, -c.ConsecutiveFailures)
+	atomic.AddUint32(&c.Overflows, -c.Overflows)
 }
 
 // Settings configures CircuitBreaker:
 //
 // Name is the name of the CircuitBreaker.
 //
+// Labels are arbitrary key/value pairs describing the CircuitBreaker
+// (owning team, tier, dependency type), for slicing telemetry.
+// They are copied by New and cannot be changed afterwards.
+//
 // MaxRequests is the maximum number of requests allowed to pass through
 // when the CircuitBreaker is half-open.
 // If MaxRequests is 0, the CircuitBreaker allows only 1 request.
 //
+// ProbeSuccesses is the number of the MaxRequests half-open probes that must
+// succeed for the CircuitBreaker to close, such as 8 of 10. The CircuitBreaker
+// opens again as soon as too many probes failed for that to happen.
+// If ProbeSuccesses is 0 or more than MaxRequests, every probe must succeed
+// and the first failure opens the CircuitBreaker again.
+//
+// ProbeWindow, if greater than 0, spreads the MaxRequests half-open probes
+// over ProbeWindow: the CircuitBreaker admits one probe as it becomes
+// half-open and earns another every ProbeWindow / MaxRequests, instead of
+// admitting all of them at once at the start of the half-open state.
+//
+// OpenPassthrough, if greater than 0, is the share of requests, between 0
+// and 1, picked at random, that the open CircuitBreaker lets through
+// rather than rejects, so that the health of the dependency keeps being
+// measured from real traffic in Stats while it is open. Their outcomes
+// are counted but never change the state; Timeout still decides when the
+// CircuitBreaker becomes half-open.
+//
 // Interval is the cyclic period of the closed state
 // for the CircuitBreaker to clear the internal Counts.
 // If Interval is 0, the CircuitBreaker doesn't clear internal Counts during the closed state.
 //
+// AlignInterval, if true, makes the closed-state clearing happen on wall-clock
+// multiples of Interval (every minute on the minute, every hour on the hour, in UTC)
+// instead of Interval after the closed state began, so the resets line up with
+// external metrics and do not drift.
+//
 // Timeout is the period of the open state,
 // after which the state of the CircuitBreaker becomes half-open.
 // If Timeout is 0, the timeout value of the CircuitBreaker is set to 60 seconds.
 //
+// HalfOpenTimeout, if greater than 0, bounds how long the CircuitBreaker
+// stays half-open without its probes deciding, so that one seeing little
+// traffic does not linger half-open. Once it elapses the CircuitBreaker
+// opens again, or closes if HalfOpenTimeoutCloses is true.
+//
 // ReadyToTrip is called with a copy of Counts whenever a request fails in the closed state.
 // If ReadyToTrip returns true, the CircuitBreaker will be placed into the open state.
 // If ReadyToTrip is nil, default ReadyToTrip is used.
 // Default ReadyToTrip returns true when the number of consecutive failures is more than 5.
+//
+// PressureFunc, if set, reports a backpressure signal of the dependency,
+// such as the depth of a queue or the utilization of a pool, relative to
+// its capacity. The closed CircuitBreaker trips as soon as it reaches
+// MaxPressure, before requests start failing, and rejects the request.
+// If MaxPressure is 0, 1 is used. PressureFunc is called before every
+// request is admitted in the closed state, while the lock of the
+// CircuitBreaker is held, so it must be quick and must not call the
+// CircuitBreaker.
+//
+// DecisionHistory, if greater than 0, is the number of the last failures
+// of the closed state for which the CircuitBreaker keeps the Stats it
+// decided to trip or not on, and the decision, for debugging why it did or
+// did not trip. See Decisions; the AdminHandler serves them.
+//
+// MinimumRequests is the number of outcomes (TotalSuccesses plus TotalFailures)
+// the current generation must have counted before ReadyToTrip is consulted at
+// all, so ratios computed by ReadyToTrip are never based on too few samples
+// (or none). Requests still in flight do not count towards it.
+// If MinimumRequests is 0, ReadyToTrip is consulted on every failure.
+//
+// AllowProbe, if set, is called in the half-open state with the context of
+// a request, before it is admitted as a probe. If AllowProbe returns false,
+// the request is rejected with ErrTooManyRequests. Requests made through
+// Execute and other context-less methods carry context.Background().
+// AllowProbe is called while the lock of the CircuitBreaker is held, so it
+// must be quick and must not call the CircuitBreaker.
+//
+// IsSuccessful is called with the error returned from a request.
+// If IsSuccessful returns true, the error is counted as a success.
+// Otherwise the error is counted as a failure.
+// If IsSuccessful is nil, default IsSuccessful is used, which returns false for all non-nil errors.
+//
+// Classifier assigns a Category to every failure, for Stats.FailuresByCategory.
+// It does not decide what counts as a failure; IsSuccessful does.
+// If Classifier is nil, DefaultClassifier is used.
+// Failures Classifier does not categorize count as CategoryOther.
+//
+// Windows lists the sizes of rolling windows, such as 1m, 5m and 15m, that
+// count the outcomes of requests alongside the counts cleared every
+// Interval, so ReadyToTrip can weigh a short spike against a longer trend.
+// They are reported in Stats.Windows, in the same order, and cleared
+// whenever the state of the CircuitBreaker changes.
+//
+// NewMachine creates the state machine the CircuitBreaker runs on.
+// If NewMachine is nil, a built-in machine is used. See persephonefsm for
+// running on persephone. NewMachine is fixed by New.
+//
+// States adds application-specific states, defined with DefineState, to
+// the CircuitBreaker, and Transitions the ways into and out of them.
+// See CustomState and Transition. They are fixed by New; UpdateSettings
+// keeps the ones given to New.
+//
+// Maintenance lists scheduled windows during which the CircuitBreaker is
+// forced open or only observes requests. See MaintenanceWindow.
+//
+// ErrorMapper, if set, is applied to every error returned by Execute and
+// the other ways of running a request, rejections included, so that they
+// can be translated into domain errors, such as ErrServiceUnavailable of
+// an API, in one place rather than at every call site. The outcome of a
+// request is judged on its error before mapping. Mapped rejections should
+// wrap the original error for IsRejected to still recognize them.
+//
+// Clock is the time source used for all expiry decisions.
+// If Clock is nil, the system clock is used.
+//
+// Rand is the source of the random decisions of Sampling and
+// OpenPassthrough, so that they can be reproduced in tests and
+// simulations. It is only used while the lock of the CircuitBreaker is
+// held. If Rand is nil, the global source of math/rand is used.
+//
+// IgnoreCallerCancellation, if true, makes ExecuteContext count neither a
+// success nor a failure when the request fails with context.Canceled or
+// context.DeadlineExceeded because the caller's context is done. Such
+// requests are released as if they had never been admitted.
+//
+// AwaitHalfOpen, if true, makes ExecuteContext attempt a request rejected
+// by the open CircuitBreaker once more as soon as it becomes half-open,
+// provided the request was marked with Idempotent and its context deadline
+// leaves time for it, so that short trips go unnoticed by callers that can
+// afford to wait. The second attempt is admitted as any other half-open
+// probe is. Requests without a deadline are never held back.
+//
+// HalfOpenOverflow decides whether the requests rejected with
+// ErrTooManyRequests because all the half-open probes are taken are traced
+// and counted, see Overflow. Such rejections never count towards the
+// probes. By default they leave ConsecutiveSuccesses alone; if
+// OverflowResetsProbes is true, each of them clears it, for ReadyToTrip
+// and Transitions requiring a run of successes without overload.
+//
+// DeadlineBudget, if greater than 0, makes ExecuteContext reject requests
+// whose context deadline leaves less time than the DeadlineBudget
+// percentile, such as 0.9, of the latencies of the last minute or two,
+// with a *DeadlineBudgetError. Such requests would most likely time out
+// anyway; they are not counted. Nothing is rejected until 20 latencies
+// have been seen.
+//
+// OnInvariantViolation, if set, enables checking of the internal invariants
+// after every request and state change, and is called with an *InvariantError
+// for each violation found. It is meant to be enabled in tests.
+//
+// OnMachineError, if set, is called with every error the state machine
+// returns while accounting for a request or an expiry, while the lock of
+// the CircuitBreaker is held. Such errors never replace the result of a
+// request. See MachineErrors.
+//
+// OnTrace, if set, is called with every outcome, rejection, state change
+// and anomaly of the CircuitBreaker, while its lock is held. See Recorder and Replay.
+//
+// Sampling, if set, bounds the cost of tracing on hot paths: it maps kinds
+// of TraceEvent, such as TraceSuccess, to the share of their events, between
+// 0 and 1, passed to OnTrace and the subscribers, picked at random. Kinds
+// not in Sampling, and transitions always, are passed in full. For example,
+// {TraceSuccess: 0.01} keeps 1% of the successes but every failure and
+// rejection. Traces sampled this way cannot be replayed faithfully.
+//
+// OnRejected, if set, is called with the error of rejected requests, while
+// the lock of the CircuitBreaker is held. If RejectedInterval is greater
+// than 0, OnRejected is called at most once per RejectedInterval, with the
+// number of rejections suppressed since the previous call, so that an open
+// CircuitBreaker under load does not flood the logs.
+//
+// Panics in OnInvariantViolation, OnMachineError, OnTrace and OnRejected are
+// recovered and counted, see Anomalies.
 type Settings struct {
-	Name          string
-	MaxRequests   uint32
-	Interval      time.Duration
-	Timeout       time.Duration
-	ReadyToTrip   func(stats Stats) bool
+	Name            string
+	Labels          map[string]string
+	MaxRequests     uint32
+	ProbeSuccesses  uint32
+	ProbeWindow     time.Duration
+	OpenPassthrough float64
+	Interval        time.Duration
+	AlignInterval   bool
+	Timeout         time.Duration
+	HalfOpenTimeout time.Duration
+	ReadyToTrip     func(stats Stats) bool
+	PressureFunc    func() float64
+	MaxPressure     float64
+	MinimumRequests uint32
+	DecisionHistory int
+	AllowProbe      func(ctx context.Context) bool
+	IsSuccessful    func(err error) bool
+	Classifier      Classifier
+	Windows         []time.Duration
+	NewMachine      NewMachineFunc
+	States          []CustomState
+	Transitions     []Transition
+	Maintenance     []MaintenanceWindow
+	ErrorMapper     func(err error) error
+	Clock           Clock
+	Rand            rand.Source
+
+	IgnoreCallerCancellation bool
+	AwaitHalfOpen            bool
+	HalfOpenTimeoutCloses    bool
+	HalfOpenOverflow         Overflow
+	OverflowResetsProbes     bool
+	DeadlineBudget           float64
+
+	OnInvariantViolation func(err error)
+	OnMachineError       func(err error)
+	OnTrace              func(e TraceEvent)
+	Sampling             map[string]float64
+	OnRejected           func(err error, suppressed uint64)
+	RejectedInterval     time.Duration
 }
 
+// CircuitBreaker is a state machine to prevent sending requests that are
+// likely to fail. A nil or zero CircuitBreaker runs every request
+// unprotected; use New to create one.
 type CircuitBreaker struct {
-	name          string
-	maxRequests   uint32
-	interval      time.Duration
-	timeout       time.Duration
-	readyToTrip   func(stats Stats) bool
+	name            string
+	labels          map[string]string
+	maxRequests     uint32
+	probeSuccesses  uint32
+	probeWindow     time.Duration
+	openPassthrough float64
+	interval        time.Duration
+	alignInterval   bool
+	timeout         time.Duration
+	halfOpenTimeout time.Duration
+	readyToTrip     func(stats Stats) bool
+	pressureFunc    func() float64
+	maxPressure     float64
+	minimumRequests uint32
+	decisionHistory int
+	allowProbe      func(ctx context.Context) bool
+	isSuccessful    func(err error) bool
+	classifier      Classifier
+	windows         []*window
+	custom          map[State]CustomState
+	transitions     []Transition
+	maintenance     []MaintenanceWindow
+	errorMapper     func(err error) error
+	clock           Clock
+	random          *random
+
+	ignoreCallerCancellation bool
+	awaitHalfOpen            bool
+	halfOpenTimeoutCloses    bool
+	overflowMode             Overflow
+	overflowResetsProbes     bool
+	deadlineBudget           float64
+
+	onInvariantViolation func(err error)
+	onMachineError       func(err error)
+	onTrace              func(e TraceEvent)
+	sampling             map[string]float64
+	onRejected           func(err error, suppressed uint64)
+	rejectedInterval     time.Duration
+
+	// settings as given to New or UpdateSettings
+	settings Settings
+
+	mutex       sync.Mutex
+	subscribers map[*Subscription]struct{}
+	closed      bool          // by Close, closing later Subscriptions right away
+	changed     chan struct{} // closed on the next change of state, see WaitUntilClosed
+	generation  uint64
+	generated   time.Time
+	stats       Stats
+	expiry      time.Time
+
+	// see Anomalies
+	anomalies      Anomalies
+	tracingAnomaly bool
+
+	// whether the half-open probes of the generation were used up
+	probesExhausted bool
+
+	// share of requests to shed, see SetShedPercent
+	shed float64
 
-	mutex      sync.Mutex
-	generation uint64
-	stats     Stats
-	expiry     time.Time
-	*persephone.AbstractFSM
+	// rejections since OnRejected was last called at lastRejected
+	lastRejected time.Time
+	suppressed   uint64
+
+	// trip history, see TripRate
+	totalTrips uint64
+	lastTrip   time.Time
+	trips      []time.Time
+
+	// latencies of requests, see Settings.DeadlineBudget
+	latencies latencies
+
+	// trip decisions, see Settings.DecisionHistory
+	decisions []TripDecision
+
+	// see AddShadow
+	shadows map[string]*shadow
+
+	machine Machine
 }
 
+// New returns a CircuitBreaker configured by settings. Every rule is added
+// to its state machine before the machine is first used, and the rules of
+// the built-in machine cannot change afterwards.
 func New(settings Settings) *CircuitBreaker {
 
-	cb := new(CircuitBreaker)
+	cb := &CircuitBreaker{name: settings.Name, labels: copyLabels(settings.Labels)}
+	cb.apply(settings)
+	cb.initStates(settings)
 
-	// add states
-	states.Add(StateClosed, persephone.INITIAL_STATE)
-	states.Add(StateHalfOpen, persephone.NORMAL_STATE)
-	states.Add(StateOpen, persephone.NORMAL_STATE)
+	// add states, the first one is the initial state
+	states := []State{StateClosed, StateHalfOpen, StateOpen, StateIsolated}
 
 	// add inputs
-	inputs.Add(Ok)
-	inputs.Add(NotOk)
+	inputs := []Input{Ok, NotOk, Trip, Expire, Recover, Force, Reset}
 
-	// initialize FSM
-	cb.AbstractFSM = persephone.New(states, inputs)
+	states, inputs = defineStates(settings, states, inputs)
+
+	// initialize FSM, with all of its rules before the breaker uses it
+	newMachine := settings.NewMachine
+	if newMachine == nil {
+		newMachine = newBuiltinMachine
+	}
+	machine := newMachine(states, inputs)
+	for _, r := range cb.rules() {
+		machine.AddRule(r.src, r.in, r.dst, r.action)
+	}
+	if m, ok := machine.(*builtinMachine); ok {
+		m.sealed = true
+	}
+	cb.machine = machine
+
+	cb.generate(cb.clock.Now())
+	return cb
+}
+
+// apply sets everything but the name and labels from settings, applying
+// defaults. cb.mutex must be held once cb is published.
+func (cb *CircuitBreaker) apply(settings Settings) {
+	if cb.custom != nil {
+		settings.States = cb.settings.States
+		settings.Transitions = cb.settings.Transitions
+	}
+	cb.settings = settings
 
-	cb.name = settings.Name
 	cb.interval = settings.Interval
+	cb.alignInterval = settings.AlignInterval
 
 	if settings.MaxRequests == 0 {
 		cb.maxRequests = 1
@@ -128,6 +499,15 @@ func New(settings Settings) *CircuitBreaker {
 		cb.maxRequests = settings.MaxRequests
 	}
 
+	if settings.ProbeSuccesses == 0 || settings.ProbeSuccesses > cb.maxRequests {
+		cb.probeSuccesses = cb.maxRequests
+	} else {
+		cb.probeSuccesses = settings.ProbeSuccesses
+	}
+
+	cb.probeWindow = settings.ProbeWindow
+	cb.openPassthrough = settings.OpenPassthrough
+
 	if settings.Timeout == 0 {
 		cb.timeout = defaultTimeout
 	} else {
@@ -140,139 +520,733 @@ func New(settings Settings) *CircuitBreaker {
 		cb.readyToTrip = settings.ReadyToTrip
 	}
 
-	cb.generate(time.Now())
-	cb.init()
-	return cb
+	cb.minimumRequests = settings.MinimumRequests
+	cb.decisionHistory = settings.DecisionHistory
+	if len(cb.decisions) > cb.decisionHistory {
+		cb.decisions = append([]TripDecision(nil), cb.decisions[len(cb.decisions)-max(cb.decisionHistory, 0):]...)
+	}
+	cb.allowProbe = settings.AllowProbe
+
+	if settings.IsSuccessful == nil {
+		cb.isSuccessful = defaultIsSuccessful
+	} else {
+		cb.isSuccessful = settings.IsSuccessful
+	}
+
+	if settings.Classifier == nil {
+		cb.classifier = DefaultClassifier
+	} else {
+		cb.classifier = settings.Classifier
+	}
+
+	if !sameWindows(cb.windows, settings.Windows) {
+		cb.windows = newWindows(settings.Windows)
+	}
+
+	cb.maintenance = append([]MaintenanceWindow(nil), settings.Maintenance...)
+	cb.errorMapper = settings.ErrorMapper
+
+	if settings.Clock == nil {
+		cb.clock = systemClock{}
+	} else {
+		cb.clock = settings.Clock
+	}
+
+	cb.random = newRandom(settings.Rand)
+	cb.ignoreCallerCancellation = settings.IgnoreCallerCancellation
+	cb.awaitHalfOpen = settings.AwaitHalfOpen
+	cb.halfOpenTimeout = settings.HalfOpenTimeout
+	cb.halfOpenTimeoutCloses = settings.HalfOpenTimeoutCloses
+	cb.overflowMode = settings.HalfOpenOverflow
+	cb.overflowResetsProbes = settings.OverflowResetsProbes
+	cb.deadlineBudget = settings.DeadlineBudget
+
+	cb.pressureFunc = settings.PressureFunc
+	if settings.MaxPressure <= 0 {
+		cb.maxPressure = 1
+	} else {
+		cb.maxPressure = settings.MaxPressure
+	}
+	cb.onInvariantViolation = settings.OnInvariantViolation
+	cb.onMachineError = settings.OnMachineError
+	cb.onTrace = settings.OnTrace
+	cb.sampling = copySampling(settings.Sampling)
+	cb.onRejected = settings.OnRejected
+	cb.rejectedInterval = settings.RejectedInterval
 }
 
 func defaultReadyToTrip(stats Stats) bool {
 	return stats.ConsecutiveFailures > 5
 }
 
-func (cb *CircuitBreaker) init() {
+func defaultIsSuccessful(err error) bool {
+	return err == nil
+}
+
+// rules returns the rules of the state machine, those of the custom states
+// and transitions included.
+func (cb *CircuitBreaker) rules() []ruleSpec {
+	var rules []ruleSpec
+	add := func(src State, in Input, dst State, action func() error) {
+		rules = append(rules, ruleSpec{src, in, dst, action})
+	}
+
 	// Add rules, you can choose to add a method as an input action for a src => input map.
-	// Alternatively, you can separate it via cb.AddInputAction(src, input, func() error)
-	cb.AddRule(StateClosed, NotOk, StateOpen, cb.ClosedNotOkAction)
-	cb.AddRule(StateClosed, Ok, StateClosed, cb.ClosedOkAction)
-	cb.AddRule(StateOpen, NotOk, StateOpen, nil)
-	cb.AddRule(StateHalfOpen, Ok, StateClosed, cb.HalfOpenOkAction)
-	cb.AddRule(StateHalfOpen, NotOk, StateOpen, cb.HalfOpenNotOkAction)
+	//
+	// Ok and NotOk only account for the outcome of a request; the decision to
+	// leave a state is fed to the FSM separately as Trip, Expire or Recover.
+	add(StateClosed, Ok, StateClosed, cb.closedOkAction)
+	add(StateClosed, NotOk, StateClosed, cb.closedNotOkAction)
+	add(StateClosed, Trip, StateOpen, nil)
+	add(StateOpen, Ok, StateOpen, cb.openOkAction)
+	add(StateOpen, NotOk, StateOpen, cb.openNotOkAction)
+	add(StateOpen, Expire, StateHalfOpen, nil)
+	add(StateHalfOpen, Ok, StateHalfOpen, cb.halfOpenOkAction)
+	add(StateHalfOpen, NotOk, StateHalfOpen, cb.halfOpenNotOkAction)
+	add(StateHalfOpen, Trip, StateOpen, nil)
+	add(StateHalfOpen, Recover, StateClosed, nil)
 
+	// manual overrides, see ForceOpen, ForceClose and Reset
+	for _, state := range []State{StateClosed, StateHalfOpen, StateOpen, StateIsolated} {
+		add(state, Force, StateIsolated, nil)
+		add(state, Reset, StateClosed, nil)
+	}
+
+	for _, cs := range cb.settings.States {
+		add(cs.State, Ok, cs.State, cb.closedOkAction)
+		add(cs.State, NotOk, cs.State, cb.closedNotOkAction)
+		add(cs.State, Force, StateIsolated, nil)
+		add(cs.State, Reset, StateClosed, nil)
+	}
+	for _, t := range cb.transitions {
+		add(t.From, shift(t.To), t.To, nil)
+	}
+	return rules
 }
 
 func (cb *CircuitBreaker) Name() string {
+	if cb == nil {
+		return ""
+	}
 	return cb.name
 }
 
-func (cb *CircuitBreaker) State() persephone.State {
+// Labels returns a copy of the labels of the CircuitBreaker.
+func (cb *CircuitBreaker) Labels() map[string]string {
+	if cb == nil {
+		return copyLabels(nil)
+	}
+	return copyLabels(cb.labels)
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	c := make(map[string]string, len(labels))
+	for k, v := range labels {
+		c[k] = v
+	}
+	return c
+}
+
+// now returns the time of the Clock of the CircuitBreaker.
+func (cb *CircuitBreaker) now() time.Time {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return cb.clock.Now()
+}
+
+// Timeout returns the period the CircuitBreaker stays open before becoming half-open.
+func (cb *CircuitBreaker) Timeout() time.Duration {
+	if cb.unprotected() {
+		return 0
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+	return cb.timeout
+}
+
+// Settings returns the settings the CircuitBreaker runs with, as last given
+// to New or UpdateSettings with the defaults applied: a zero MaxRequests
+// is returned as 1 and a nil ReadyToTrip as the default one, for instance.
+// Unlike the settings ModifySettings passes on, they cannot tell a default
+// from the same value given explicitly.
+func (cb *CircuitBreaker) Settings() Settings {
+	if cb.unprotected() {
+		return Settings{Name: cb.Name()}
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	s := cb.settings
+	s.Name = cb.name
+	s.Labels = copyLabels(cb.labels)
+	s.MaxRequests = cb.maxRequests
+	s.ProbeSuccesses = cb.probeSuccesses
+	s.Timeout = cb.timeout
+	s.ReadyToTrip = cb.readyToTrip
+	s.MaxPressure = cb.maxPressure
+	s.IsSuccessful = cb.isSuccessful
+	s.Classifier = cb.classifier
+	s.Clock = cb.clock
+	s.Windows = append([]time.Duration(nil), s.Windows...)
+	s.States = append([]CustomState(nil), s.States...)
+	s.Transitions = append([]Transition(nil), s.Transitions...)
+	s.Maintenance = append([]MaintenanceWindow(nil), cb.maintenance...)
+	s.Sampling = copySampling(cb.sampling)
+	return s
+}
+
+func (cb *CircuitBreaker) State() State {
+	if cb.unprotected() {
+		return StateClosed
+	}
 	cb.mutex.Lock()
 	defer cb.mutex.Unlock()
 
-	now := time.Now()
+	now := cb.clock.Now()
 	cb.currentState(now)
-	return cb.GetState()
+	cb.verify(now)
+	if cb.maintenanceAt(now) == MaintenanceOpen {
+		return StateOpen
+	}
+	return cb.state()
 }
 
-func (cb *CircuitBreaker) Execute(req func() (interface{}, error)) (interface{}, error) {
-	var now time.Time
-	now = time.Now()
+// state returns the current state of the FSM. cb.mutex must be held.
+func (cb *CircuitBreaker) state() State {
+	return cb.machine.State()
+}
+
+// RemainingOpenTime returns how long the CircuitBreaker stays open before
+// becoming half-open, or 0 if it is not open.
+func (cb *CircuitBreaker) RemainingOpenTime() time.Duration {
+	if cb.unprotected() {
+		return 0
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.clock.Now()
 	cb.currentState(now)
+	if cb.state() != StateOpen {
+		return 0
+	}
+	return cb.expiry.Sub(now)
+}
 
-	if cb.GetState() == StateOpen {
-		return nil, ErrOpenState
+// Stats returns a copy of the internal counters of the current generation.
+func (cb *CircuitBreaker) Stats() Stats {
+	if cb.unprotected() {
+		return Stats{}
 	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.clock.Now()
+	cb.currentState(now)
+	cb.verify(now)
+	return cb.snapshot(now)
+}
 
-	if cb.GetState() == StateHalfOpen && cb.stats.Requests >= cb.maxRequests {
-		return nil, ErrTooManyRequests
+func (cb *CircuitBreaker) Execute(req func() (interface{}, error)) (interface{}, error) {
+	if req == nil {
+		return nil, cb.errNilRequest()
+	}
+	if cb.unprotected() {
+		return req()
 	}
 
-	cb.stats.request()
+	t, err := cb.beforeRequest(context.Background())
+	if err != nil {
+		return nil, t.mapError(err)
+	}
 
+	defer cb.settlePanic(t)
 	result, err := req()
+	cb.afterRequest(t, t.outcomeOf(err), err)
+	return result, t.mapError(err)
+}
 
+// ExecuteContext is like Execute, but passes ctx to req and recognizes
+// requests that failed only because ctx is done.
+// See Settings.IgnoreCallerCancellation.
+//
+// The context passed to req carries the BreakerInfo and the CallInfo of
+// the request.
+func (cb *CircuitBreaker) ExecuteContext(ctx context.Context, req func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if req == nil {
+		return nil, cb.errNilRequest()
+	}
+	if cb.unprotected() {
+		return req(ctx)
+	}
+
+	t, err := cb.beforeRequest(ctx)
+	if err != nil && cb.waitHalfOpen(ctx, err) {
+		t, err = cb.beforeRequest(ctx)
+	}
 	if err != nil {
-		err_f := cb.Process(NotOk)
-		if err_f != nil {
-			return result, err_f
-		}
+		return nil, t.mapError(err)
+	}
 
-		return result, err
+	defer cb.settlePanic(t)
+	ctx = NewInfoContext(ctx, BreakerInfo{Name: cb.name, State: t.state})
+	result, err := req(NewCallContext(ctx, t.callInfo(cb.name)))
+
+	outcome := t.outcomeOf(err)
+	if t.ignoreCallerCancellation && cancelledByCaller(ctx, err) {
+		outcome = outcomeIgnored
+	}
+
+	cb.afterRequest(t, outcome, err)
+	return result, t.mapError(err)
+}
+
+// Run is like Execute for requests that produce no result.
+func (cb *CircuitBreaker) Run(req func() error) error {
+	if req == nil {
+		return cb.errNilRequest()
+	}
+	_, err := cb.Execute(func() (interface{}, error) {
+		return nil, req()
+	})
+	return err
+}
+
+// RunContext is like ExecuteContext for requests that produce no result.
+func (cb *CircuitBreaker) RunContext(ctx context.Context, req func(ctx context.Context) error) error {
+	if req == nil {
+		return cb.errNilRequest()
+	}
+	_, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+		return nil, req(ctx)
+	})
+	return err
+}
+
+// settlePanic is deferred around a request admitted with t. If the request
+// panics, it counts as a failure before the panic goes on, so that it does
+// not hold its ticket, such as a half-open probe, for good.
+func (cb *CircuitBreaker) settlePanic(t ticket) {
+	if r := recover(); r != nil {
+		cb.afterRequest(t, outcomeFailure, fmt.Errorf("panic: %v", r))
+		panic(r)
+	}
+}
+
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	outcomeIgnored
+)
+
+func (t ticket) outcomeOf(err error) outcome {
+	if t.isSuccessful(err) {
+		return outcomeSuccess
+	}
+	return outcomeFailure
+}
+
+func cancelledByCaller(ctx context.Context, err error) bool {
+	if ctx.Err() == nil {
+		return false
 	}
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// ticket describes how a request was admitted by beforeRequest, along
+// with the settings in effect, so that the outcome can be judged without
+// holding cb.mutex.
+type ticket struct {
+	generation  uint64
+	state       State
+	observeOnly bool
+
+	// start is when the request was admitted, zero if it was reported with
+	// its latency by ReportSuccess or ReportFailure
+	start   time.Time
+	latency time.Duration
 
-	err_t := cb.Process(Ok)	
+	// cost of the request, see WithCost
+	cost float64
 
-	if err_t != nil {
-		return result, err_t
+	isSuccessful             func(err error) bool
+	ignoreCallerCancellation bool
+	errorMapper              func(err error) error
+}
+
+// probe reports whether the request was admitted as a half-open probe.
+func (t ticket) probe() bool {
+	return t.state == StateHalfOpen && !t.observeOnly
+}
+
+func (t ticket) callInfo(name string) CallInfo {
+	return CallInfo{Name: name, Generation: t.generation, State: t.state, Start: t.start, Probe: t.probe()}
+}
+
+// mapError applies Settings.ErrorMapper to err, if not nil.
+func (t ticket) mapError(err error) error {
+	if err == nil || t.errorMapper == nil {
+		return err
 	}
+	return t.errorMapper(err)
+}
 
-	return result, err
+// admit returns a ticket for a request admitted now. cb.mutex must be held.
+func (cb *CircuitBreaker) admit(now time.Time, observeOnly bool) ticket {
+	return ticket{
+		generation:               cb.generation,
+		state:                    cb.state(),
+		observeOnly:              observeOnly,
+		start:                    now,
+		isSuccessful:             cb.isSuccessful,
+		ignoreCallerCancellation: cb.ignoreCallerCancellation,
+		errorMapper:              cb.errorMapper,
+	}
 }
 
-func (cb *CircuitBreaker) ClosedOkAction() error {
+// beforeRequest admits a request or rejects it. The ticket of a rejected
+// request only carries the error mapper.
+func (cb *CircuitBreaker) beforeRequest(ctx context.Context) (t ticket, err error) {
 	cb.mutex.Lock()
 	defer cb.mutex.Unlock()
-	cb.stats.success()
-	return nil
+	defer func() {
+		t.errorMapper = cb.errorMapper
+	}()
+
+	now := cb.clock.Now()
+	cb.currentState(now)
+
+	switch cb.maintenanceAt(now) {
+	case MaintenanceOpen:
+		return ticket{}, cb.reject(now, ErrMaintenance)
+	case MaintenanceObserve:
+		return cb.admit(now, true), nil
+	}
+
+	if cb.state() == StateIsolated {
+		return ticket{}, cb.reject(now, ErrIsolated)
+	}
+
+	if cb.state() == StateClosed && cb.pressureFunc != nil && cb.pressureFunc() >= cb.maxPressure {
+		cb.machineError(cb.process(Trip, now))
+	}
+
+	if cb.state() == StateOpen && !cb.random.passes(cb.openPassthrough) {
+		return ticket{}, cb.reject(now, &OpenStateError{Remaining: cb.expiry.Sub(now)})
+	}
+
+	if cb.shed > 0 && cb.random.passes(cb.shed) {
+		return ticket{}, cb.reject(now, ErrShed)
+	}
+
+	if cb.state() == StateHalfOpen {
+		if cb.stats.Requests >= cb.probeTokens(now) {
+			return ticket{}, cb.overflow(now)
+		}
+		if cb.allowProbe != nil && !cb.allowProbe(ctx) {
+			return ticket{}, cb.reject(now, ErrTooManyRequests)
+		}
+	}
+
+	if err := cb.checkDeadlineBudget(ctx, now); err != nil {
+		return ticket{}, cb.reject(now, err)
+	}
+
+	if err := cb.admitCustom(ctx); err != nil {
+		return ticket{}, cb.reject(now, err)
+	}
+
+	cost := costOf(ctx)
+	cb.stats.request()
+	cb.stats.weigh(cost, outcomeIgnored)
+	cb.verify(now)
+
+	t = cb.admit(now, false)
+	t.cost = cost
+	return t, nil
 }
 
-func (cb *CircuitBreaker) HalfOpenOkAction() error {
+// probeTokens returns the number of half-open probes admitted as of now.
+// cb.mutex must be held.
+func (cb *CircuitBreaker) probeTokens(now time.Time) uint32 {
+	if cb.probeWindow <= 0 {
+		return cb.maxRequests
+	}
+
+	earned := 1 + uint64(now.Sub(cb.generated))*uint64(cb.maxRequests)/uint64(cb.probeWindow)
+	if earned > uint64(cb.maxRequests) {
+		return cb.maxRequests
+	}
+	return uint32(earned)
+}
+
+// reject reports the rejection of a request with err and returns err,
+// wrapped in a BreakerError.
+// cb.mutex must be held.
+func (cb *CircuitBreaker) reject(now time.Time, err error) error {
+	cb.trace(TraceEvent{Time: now, Kind: TraceRejected, Error: err.Error()})
+	err = cb.named(err)
+
+	if cb.onRejected == nil {
+		return err
+	}
+	if cb.rejectedInterval > 0 && !cb.lastRejected.IsZero() && now.Sub(cb.lastRejected) < cb.rejectedInterval {
+		cb.suppressed++
+		return err
+	}
+
+	suppressed := cb.suppressed
+	cb.lastRejected, cb.suppressed = now, 0
+	cb.hook("OnRejected", func() { cb.onRejected(err, suppressed) })
+	return err
+}
+
+// afterRequest accounts for the outcome of a request. Errors of the state
+// machine are reported to Settings.OnMachineError, never to the caller.
+func (cb *CircuitBreaker) afterRequest(t ticket, outcome outcome, err error) {
 	cb.mutex.Lock()
 	defer cb.mutex.Unlock()
-	cb.stats.success()
-	if cb.stats.ConsecutiveSuccesses >= cb.maxRequests {
-		now := time.Now()
+
+	now := cb.clock.Now()
+	latency := t.latency
+	if !t.start.IsZero() {
+		latency = now.Sub(t.start)
+	}
+
+	// Account for the outcome before rolling an expired closed generation
+	// over, so the request can still trip the breaker it was admitted by.
+	defer cb.verify(now)
+	defer cb.currentState(now)
+
+	if t.observeOnly {
+		cb.trace(TraceEvent{Time: now, Kind: TraceIgnored, Latency: latency, Generation: t.generation})
+		return
+	}
+
+	var category Category
+	if outcome == outcomeFailure {
+		if category = cb.classifier(err); category == "" {
+			category = CategoryOther
+		}
+	}
+
+	if outcome != outcomeIgnored {
+		cb.latencies.record(now, latency)
+	}
+
+	e := TraceEvent{Time: now, Latency: latency, Generation: t.generation, Probe: t.probe()}
+	switch outcome {
+	case outcomeSuccess:
+		e.Kind = TraceSuccess
+	case outcomeFailure:
+		e.Kind, e.Category = TraceFailure, category
+	case outcomeIgnored:
+		e.Kind = TraceIgnored
+	}
+	cb.trace(e)
+
+	// the outcome belongs to a generation that has already been rolled over
+	if cb.generation != t.generation {
+		return
+	}
+
+	switch outcome {
+	case outcomeSuccess:
+		cb.stats.weigh(t.cost, outcomeSuccess)
+		cb.machineError(cb.onSuccess(now))
+	case outcomeFailure:
+		cb.stats.weigh(t.cost, outcomeFailure)
+		cb.machineError(cb.onFailure(now, category))
+	default:
+		cb.stats.release()
+		cb.stats.weigh(-t.cost, outcomeIgnored)
+	}
+
+	// the last probe was counted without a verdict
+	if cb.generation == t.generation && cb.state() == StateHalfOpen && cb.stats.outcomes() >= cb.maxRequests {
+		stats := cb.snapshot(now)
+		cb.exhausted(now, &stats, StateHalfOpen)
+	}
+}
+
+// exhausted traces the end of the half-open probes of the generation,
+// counted in stats, with the state they left the CircuitBreaker in.
+// cb.mutex must be held.
+func (cb *CircuitBreaker) exhausted(now time.Time, stats *Stats, state State) {
+	if cb.probesExhausted {
+		return
+	}
+	cb.probesExhausted = true
+	cb.trace(TraceEvent{Time: now, Kind: TraceProbesExhausted, From: StateHalfOpen, To: state, Stats: stats})
+}
+
+func (cb *CircuitBreaker) onSuccess(now time.Time) error {
+	cb.record(now, false)
+	if err := cb.process(Ok, now); err != nil {
+		return err
+	}
+
+	if cb.state() == StateHalfOpen && cb.stats.TotalSuccesses >= cb.probeSuccesses {
+		return cb.process(Recover, now)
+	}
+
+	return cb.transit(now)
+}
+
+func (cb *CircuitBreaker) onFailure(now time.Time, category Category) error {
+	cb.record(now, true)
+	if err := cb.process(NotOk, now); err != nil {
+		return err
+	}
+
+	// reopen once the probes left cannot make up for the failed ones
+	if cb.state() == StateHalfOpen {
+		if cb.stats.TotalFailures > cb.maxRequests-cb.probeSuccesses {
+			return cb.process(Trip, now)
+		}
+		return nil
+	}
+
+	if _, custom := cb.custom[cb.state()]; cb.state() != StateClosed && !custom {
+		return nil
+	}
+
+	cb.stats.categorize(category)
+	if cb.stats.outcomes() < cb.minimumRequests {
+		if cb.state() == StateClosed && cb.decisionHistory > 0 {
+			cb.decide(TripDecision{Time: now, Stats: cb.snapshot(now), Skipped: "minimum-requests"})
+		}
+		return nil
+	}
+
+	if cb.state() == StateClosed {
+		stats := cb.snapshot(now)
+		tripped := cb.readyToTrip(stats)
+		cb.decide(TripDecision{Time: now, Stats: stats, Tripped: tripped})
+		if tripped {
+			return cb.process(Trip, now)
+		}
+	}
+
+	return cb.transit(now)
+}
+
+// roll starts a new generation in the current state and traces it.
+// cb.mutex must be held.
+func (cb *CircuitBreaker) roll(now time.Time) {
+	cb.generate(now)
+	cb.rolled(now)
+}
+
+func (cb *CircuitBreaker) rolled(now time.Time) {
+	cb.trace(TraceEvent{Time: now, Kind: TraceGenerationRolled, Generation: cb.generation})
+}
+
+// process feeds input to the FSM and starts a new generation whenever the
+// input moved the CircuitBreaker into another state. cb.mutex must be held.
+func (cb *CircuitBreaker) process(input Input, now time.Time) error {
+	prev := cb.state()
+	err := cb.machine.Process(input)
+	if state := cb.state(); state != prev {
+		stats := cb.snapshot(now)
+		if prev == StateHalfOpen && stats.outcomes() >= cb.maxRequests {
+			cb.exhausted(now, &stats, state)
+		}
 		cb.generate(now)
+		for _, w := range cb.windows {
+			w.reset()
+		}
+		if state == StateOpen && (input == Trip || input == NotOk || input >= shiftInputs) {
+			cb.tripped(now)
+		}
+		cb.trace(TraceEvent{Time: now, Kind: TraceTransition, From: prev, To: state, Stats: &stats})
+		cb.rolled(now)
+		cb.notifyWaiters()
 	}
+
+	return err
+}
+
+// FSM actions run with cb.mutex held, from within process.
+
+func (cb *CircuitBreaker) closedOkAction() error {
+	cb.stats.success()
 	return nil
 }
 
-func (cb *CircuitBreaker) ClosedNotOkAction() error {
-	cb.mutex.Lock()
-	defer cb.mutex.Unlock()
+func (cb *CircuitBreaker) halfOpenOkAction() error {
+	cb.stats.success()
+	return nil
+}
+
+func (cb *CircuitBreaker) halfOpenNotOkAction() error {
 	cb.stats.failure()
-	if cb.readyToTrip(cb.stats) {
-		now := time.Now()
-		cb.generate(now)
-	}
 	return nil
 }
 
-func (cb *CircuitBreaker) HalfOpenNotOkAction() error {
-	cb.mutex.Lock()
-	defer cb.mutex.Unlock()
-	now := time.Now()
-	cb.generate(now)
+func (cb *CircuitBreaker) openOkAction() error {
+	cb.stats.success()
+	return nil
+}
+
+func (cb *CircuitBreaker) openNotOkAction() error {
+	cb.stats.failure()
+	return nil
+}
+
+func (cb *CircuitBreaker) closedNotOkAction() error {
+	cb.stats.failure()
 	return nil
 }
 
 func (cb *CircuitBreaker) currentState(now time.Time) {
-	switch cb.GetState() {
+	switch cb.state() {
 	case StateClosed:
-		if !cb.expiry.IsZero() && cb.expiry.Before(now) {
-			cb.generate(now)
+		if !cb.expiry.IsZero() && !now.Before(cb.expiry) {
+			cb.roll(now)
 		}
 	case StateOpen:
-		if cb.expiry.Before(now) {
-			cb.generate(now)
+		if !now.Before(cb.expiry) {
+			cb.machineError(cb.process(Expire, now))
+		}
+	case StateHalfOpen:
+		if !cb.expiry.IsZero() && !now.Before(cb.expiry) {
+			if cb.halfOpenTimeoutCloses {
+				cb.machineError(cb.process(Recover, now))
+			} else {
+				cb.machineError(cb.process(Trip, now))
+			}
 		}
 	}
 }
 
 func (cb *CircuitBreaker) generate(now time.Time) {
 	cb.generation++
+	cb.generated = now
+	cb.probesExhausted = false
 	cb.stats.clear()
 
 	var zero time.Time
-	switch cb.GetState() {
+	switch cb.state() {
 	case StateClosed:
 		if cb.interval == 0 {
 			cb.expiry = zero
+		} else if cb.alignInterval {
+			cb.expiry = truncate(now, cb.interval).Add(cb.interval)
 		} else {
 			cb.expiry = now.Add(cb.interval)
 		}
 	case StateOpen:
 		cb.expiry = now.Add(cb.timeout)
+	case StateHalfOpen:
+		if cb.halfOpenTimeout > 0 {
+			cb.expiry = now.Add(cb.halfOpenTimeout)
+		} else {
+			cb.expiry = zero
+		}
 	default:
 		cb.expiry = zero
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,218 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+var errFail = errors.New("fail")
+
+func succeed(cb *soteria.CircuitBreaker) error {
+	_, err := cb.Execute(func() (interface{}, error) { return nil, nil })
+	return err
+}
+
+func fail(cb *soteria.CircuitBreaker) error {
+	_, err := cb.Execute(func() (interface{}, error) { return nil, errFail })
+	return err
+}
+
+// newBreaker returns a breaker driven by a soteriatest.Clock that fails
+// the test on any invariant violation.
+func newBreaker(t *testing.T, settings soteria.Settings) (*soteria.CircuitBreaker, *soteriatest.Clock) {
+	t.Helper()
+	clock := soteriatest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	settings.Clock = clock
+	settings.OnInvariantViolation = func(err error) { t.Error(err) }
+	return soteria.New(settings), clock
+}
+
+func TestDefaultReadyToTrip(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	for i := 0; i < 5; i++ {
+		fail(cb)
+	}
+	soteriatest.AssertClosed(t, cb)
+
+	fail(cb)
+	soteriatest.AssertOpen(t, cb)
+}
+
+func TestOpenRejectsUntilTimeout(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Timeout: 10 * time.Second})
+	soteriatest.Trip(t, cb, 10)
+
+	if err := succeed(cb); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Execute while open = %v, want ErrOpenState", err)
+	}
+
+	clock.Advance(9 * time.Second)
+	soteriatest.AssertOpen(t, cb)
+
+	clock.Advance(time.Second)
+	soteriatest.AssertHalfOpen(t, cb)
+}
+
+func TestHalfOpenClosesAfterMaxRequestsSuccesses(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{MaxRequests: 2})
+	soteriatest.Trip(t, cb, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	succeed(cb)
+	soteriatest.AssertHalfOpen(t, cb)
+
+	succeed(cb)
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestHalfOpenLimitsProbes(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{MaxRequests: 1})
+	soteriatest.Trip(t, cb, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cb.Execute(func() (interface{}, error) {
+			<-release
+			return nil, nil
+		})
+	}()
+
+	// wait for the probe to be admitted
+	for cb.Stats().Requests == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := succeed(cb); err != soteria.ErrTooManyRequests {
+		t.Errorf("second probe = %v, want ErrTooManyRequests", err)
+	}
+
+	close(release)
+	<-done
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestHalfOpenFailureReopens(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{MaxRequests: 3})
+	soteriatest.Trip(t, cb, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	succeed(cb)
+	fail(cb)
+	soteriatest.AssertOpen(t, cb)
+	if got, want := cb.RemainingOpenTime(), cb.Timeout(); got != want {
+		t.Errorf("reopened for %v, want a full %v", got, want)
+	}
+}
+
+func TestProbeSuccessesToleratesFailedProbes(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{MaxRequests: 10, ProbeSuccesses: 8})
+	soteriatest.Trip(t, cb, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	fail(cb)
+	for i := 0; i < 7; i++ {
+		succeed(cb)
+	}
+	fail(cb)
+	soteriatest.AssertHalfOpen(t, cb)
+
+	succeed(cb)
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestProbeSuccessesReopensWhenOutOfReach(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{MaxRequests: 10, ProbeSuccesses: 8})
+	soteriatest.Trip(t, cb, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	fail(cb)
+	fail(cb)
+	soteriatest.AssertHalfOpen(t, cb)
+
+	fail(cb)
+	soteriatest.AssertOpen(t, cb)
+}
+
+func TestProbeSuccessesAboveMaxRequests(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{MaxRequests: 2, ProbeSuccesses: 5})
+	soteriatest.Trip(t, cb, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+
+	succeed(cb)
+	succeed(cb)
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestIntervalClearsClosedStats(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Interval: time.Minute})
+
+	for i := 0; i < 5; i++ {
+		fail(cb)
+	}
+	clock.Advance(time.Minute)
+
+	if st := cb.Stats(); st.Requests != 0 || st.TotalFailures != 0 {
+		t.Errorf("stats after Interval = %+v, want cleared", st)
+	}
+
+	fail(cb)
+	soteriatest.AssertClosed(t, cb)
+}
+
+func TestDeprecatedActionsCountOutcomes(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	cb.ClosedOkAction()
+	cb.ClosedNotOkAction()
+	if st := cb.Stats(); st.TotalSuccesses != 1 || st.TotalFailures != 1 {
+		t.Errorf("stats = %+v, want one success and one failure", st)
+	}
+
+	// no-ops outside of their state
+	if err := cb.HalfOpenOkAction(); err != nil {
+		t.Error(err)
+	}
+	if st := cb.Stats(); st.TotalSuccesses != 1 {
+		t.Errorf("HalfOpenOkAction counted while closed: %+v", st)
+	}
+}
+
+func TestRejectionsNameTheBreaker(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{Name: "payments"})
+	trip(cb)
+
+	err := succeed(cb)
+	var be *soteria.BreakerError
+	if !errors.As(err, &be) || be.Breaker != "payments" || !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Execute = %v, want an open state error naming the breaker", err)
+	}
+	var open *soteria.OpenStateError
+	if !errors.As(err, &open) || !soteria.IsRejected(err) {
+		t.Errorf("Execute = %v, want an OpenStateError", err)
+	}
+}
+
+func TestEffectiveSettings(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{Name: "db"})
+
+	s := cb.Settings()
+	if s.Name != "db" || s.MaxRequests != 1 || s.ProbeSuccesses != 1 || s.Timeout != time.Minute || s.Clock == nil {
+		t.Errorf("Settings = %+v, want the defaults applied", s)
+	}
+	if s.ReadyToTrip == nil || !s.ReadyToTrip(soteria.Stats{ConsecutiveFailures: 6}) || s.ReadyToTrip(soteria.Stats{ConsecutiveFailures: 5}) {
+		t.Error("ReadyToTrip is not the default one")
+	}
+
+	cb.UpdateSettings(soteria.Settings{MaxRequests: 5, ProbeSuccesses: 8, Timeout: time.Second})
+	if s := cb.Settings(); s.Name != "db" || s.MaxRequests != 5 || s.ProbeSuccesses != 5 || s.Timeout != time.Second {
+		t.Errorf("Settings = %+v after UpdateSettings", s)
+	}
+}
@@ -0,0 +1,116 @@
+// Package soteriaelastic guards every node of an Elasticsearch or
+// OpenSearch cluster with a soteria circuit breaker of its own:
+//
+//	nodes := soteriaelastic.New(registry, soteria.Settings{Name: "search"})
+//	client, err := elastictransport.New(elastictransport.Config{
+//		URLs:      urls,
+//		Transport: nodes.Transport(nil),
+//		Selector:  nodes.Selector(),
+//	})
+//
+// The Selector skips the nodes whose breakers are open, so that the
+// client's retries go to healthy nodes, and the Transport accounts for the
+// outcome of every request to a node.
+package soteriaelastic
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/elastic/elastic-transport-go/v8/elastictransport"
+
+	"github.com/jtejido/soteria"
+)
+
+// Nodes keeps the CircuitBreakers of the nodes of a cluster in a Registry.
+type Nodes struct {
+	registry *soteria.Registry
+	settings soteria.Settings
+
+	// Classifier decides on the status code of responses.
+	// If nil, soteria.ServerErrors is used.
+	Classifier soteria.StatusClassifier
+}
+
+// New returns Nodes creating their CircuitBreakers from settings. The
+// breaker of a node is named after its host, prefixed with settings.Name
+// and a slash if settings.Name is set.
+func New(registry *soteria.Registry, settings soteria.Settings) *Nodes {
+	return &Nodes{registry: registry, settings: settings}
+}
+
+// Breaker returns the CircuitBreaker of the node at host, creating it if
+// needed.
+func (n *Nodes) Breaker(host string) *soteria.CircuitBreaker {
+	name := host
+	if n.settings.Name != "" {
+		name = n.settings.Name + "/" + host
+	}
+	return n.registry.GetOrCreate(name, n.settings)
+}
+
+// Transport returns an http.RoundTripper sending every request through the
+// CircuitBreaker of its node, then to next, or http.DefaultTransport if
+// next is nil. See soteria.RoundTripper.
+func (n *Nodes) Transport(next http.RoundTripper) http.RoundTripper {
+	return transport{nodes: n, next: next}
+}
+
+type transport struct {
+	nodes *Nodes
+	next  http.RoundTripper
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt := &soteria.RoundTripper{
+		Breaker:    t.nodes.Breaker(req.URL.Host),
+		Next:       t.next,
+		Classifier: t.nodes.Classifier,
+	}
+	return rt.RoundTrip(req)
+}
+
+// Selector returns an elastictransport.Selector choosing, round robin,
+// among the connections whose breakers are not open or isolated. If all of
+// them are, it fails with a *soteria.OpenStateError carrying the shortest
+// remaining open time.
+func (n *Nodes) Selector() elastictransport.Selector {
+	return &selector{nodes: n}
+}
+
+type selector struct {
+	nodes *Nodes
+	next  uint64
+}
+
+func (s *selector) Select(conns []*elastictransport.Connection) (*elastictransport.Connection, error) {
+	var (
+		allowed   []*elastictransport.Connection
+		open      bool
+		remaining time.Duration
+	)
+	for _, c := range conns {
+		cb := s.nodes.Breaker(c.URL.Host)
+		switch cb.State() {
+		case soteria.StateOpen:
+			if r := cb.RemainingOpenTime(); !open || r < remaining {
+				remaining = r
+			}
+			open = true
+		case soteria.StateIsolated:
+		default:
+			allowed = append(allowed, c)
+		}
+	}
+
+	if len(allowed) == 0 {
+		if !open {
+			return nil, soteria.ErrIsolated
+		}
+		return nil, &soteria.OpenStateError{Remaining: remaining}
+	}
+
+	i := atomic.AddUint64(&s.next, 1) - 1
+	return allowed[i%uint64(len(allowed))], nil
+}
@@ -0,0 +1,94 @@
+package soteriaelastic
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/elastic/elastic-transport-go/v8/elastictransport"
+
+	"github.com/jtejido/soteria"
+)
+
+func connections(hosts ...string) []*elastictransport.Connection {
+	var conns []*elastictransport.Connection
+	for _, h := range hosts {
+		conns = append(conns, &elastictransport.Connection{URL: &url.URL{Scheme: "http", Host: h}})
+	}
+	return conns
+}
+
+func TestSelectorSkipsOpenNodes(t *testing.T) {
+	nodes := New(soteria.NewRegistry(), soteria.Settings{Name: "search"})
+	conns := connections("a:9200", "b:9200", "c:9200")
+
+	nodes.Breaker("b:9200").ForceOpen()
+	for i := 0; i < 6; i++ {
+		nodes.Breaker("c:9200").ReportFailure(errors.New("down"), 0)
+	}
+
+	s := nodes.Selector()
+	for i := 0; i < 4; i++ {
+		c, err := s.Select(conns)
+		if err != nil || c.URL.Host != "a:9200" {
+			t.Fatalf("Select = %v, %v, want a:9200", c, err)
+		}
+	}
+
+	nodes.Breaker("a:9200").ForceOpen()
+	if _, err := s.Select(conns); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Select with every node down = %v, want ErrOpenState", err)
+	}
+}
+
+func TestSelectorRoundRobin(t *testing.T) {
+	nodes := New(soteria.NewRegistry(), soteria.Settings{})
+	conns := connections("a:9200", "b:9200")
+
+	s := nodes.Selector()
+	first, _ := s.Select(conns)
+	second, _ := s.Select(conns)
+	if first == second {
+		t.Errorf("Select returned %v twice", first.URL)
+	}
+}
+
+func TestTransportBreakerPerNode(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	registry := soteria.NewRegistry()
+	nodes := New(registry, soteria.Settings{Name: "search"})
+	rt := nodes.Transport(nil)
+
+	for i := 0; i < 6; i++ {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	host := mustHost(t, srv.URL)
+	if cb, ok := registry.Get("search/" + host); !ok || cb.State() != soteria.StateOpen {
+		t.Fatalf("breaker of %s missing or not open: %v", host, registry.Names())
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if _, err := rt.RoundTrip(req); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("RoundTrip to an open node = %v, want ErrOpenState", err)
+	}
+}
+
+func mustHost(t *testing.T, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Host
+}
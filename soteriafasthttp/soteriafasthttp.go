@@ -0,0 +1,92 @@
+// Package soteriafasthttp sends fasthttp requests through soteria circuit
+// breakers, on the client side with Client and on the server side with
+// Handler, as soteria.RoundTripper does for net/http.
+package soteriafasthttp
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/jtejido/soteria"
+)
+
+// Doer performs fasthttp requests, as fasthttp.Client, fasthttp.HostClient
+// and fasthttp.LBClient do.
+type Doer interface {
+	Do(req *fasthttp.Request, resp *fasthttp.Response) error
+}
+
+// Client is a Doer sending requests through a CircuitBreaker. Errors and
+// responses whose status Classifier reports as a failure count as
+// failures; responses are still filled in either way.
+//
+// Requests rejected by the CircuitBreaker fail with its rejection error
+// without reaching Next.
+type Client struct {
+	Breaker *soteria.CircuitBreaker
+
+	// Next performs the requests.
+	Next Doer
+
+	// Classifier decides on the status code. If nil, soteria.ServerErrors is used.
+	Classifier soteria.StatusClassifier
+}
+
+func (c *Client) Do(req *fasthttp.Request, resp *fasthttp.Response) error {
+	classifier := classifierOrDefault(c.Classifier)
+
+	err := c.Breaker.Run(func() error {
+		if err := c.Next.Do(req, resp); err != nil {
+			return err
+		}
+		if code := resp.StatusCode(); classifier(code) {
+			return &soteria.StatusError{Code: code, Status: strconv.Itoa(code) + " " + fasthttp.StatusMessage(code)}
+		}
+		return nil
+	})
+
+	var se *soteria.StatusError
+	if errors.As(err, &se) {
+		return nil
+	}
+	return err
+}
+
+// Handler returns next served through cb. Responses whose status
+// classifier reports as a failure, soteria.ServerErrors if nil, count as
+// failures. Rejected requests are answered with 503 Service Unavailable,
+// and a Retry-After header while cb is open.
+func Handler(cb *soteria.CircuitBreaker, next fasthttp.RequestHandler, classifier soteria.StatusClassifier) fasthttp.RequestHandler {
+	classifier = classifierOrDefault(classifier)
+
+	return func(ctx *fasthttp.RequestCtx) {
+		admitted := false
+		err := cb.Run(func() error {
+			admitted = true
+			next(ctx)
+			if code := ctx.Response.StatusCode(); classifier(code) {
+				return &soteria.StatusError{Code: code, Status: strconv.Itoa(code) + " " + fasthttp.StatusMessage(code)}
+			}
+			return nil
+		})
+		if admitted {
+			return
+		}
+
+		ctx.Error(err.Error(), fasthttp.StatusServiceUnavailable)
+		var open *soteria.OpenStateError
+		if errors.As(err, &open) {
+			ctx.Response.Header.Set("Retry-After", strconv.Itoa(int(math.Ceil(open.Remaining.Seconds()))))
+		}
+	}
+}
+
+func classifierOrDefault(c soteria.StatusClassifier) soteria.StatusClassifier {
+	if c == nil {
+		return soteria.ServerErrors
+	}
+	return c
+}
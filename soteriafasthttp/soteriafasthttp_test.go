@@ -0,0 +1,84 @@
+package soteriafasthttp
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/jtejido/soteria"
+)
+
+type doerFunc func(req *fasthttp.Request, resp *fasthttp.Response) error
+
+func (f doerFunc) Do(req *fasthttp.Request, resp *fasthttp.Response) error {
+	return f(req, resp)
+}
+
+func TestClient(t *testing.T) {
+	cb := soteria.New(soteria.Settings{})
+	calls := 0
+	c := &Client{
+		Breaker: cb,
+		Next: doerFunc(func(req *fasthttp.Request, resp *fasthttp.Response) error {
+			calls++
+			resp.SetStatusCode(fasthttp.StatusBadGateway)
+			return nil
+		}),
+	}
+
+	req, resp := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+
+	for i := 0; i < 6; i++ {
+		if err := c.Do(req, resp); err != nil || resp.StatusCode() != fasthttp.StatusBadGateway {
+			t.Fatalf("Do = %v with status %d, want the 502 response", err, resp.StatusCode())
+		}
+	}
+	if cb.State() != soteria.StateOpen {
+		t.Fatalf("State = %v after six 502s, want open", cb.State())
+	}
+
+	if err := c.Do(req, resp); !errors.Is(err, soteria.ErrOpenState) || calls != 6 {
+		t.Errorf("Do while open = %v after %d calls", err, calls)
+	}
+}
+
+func TestClientError(t *testing.T) {
+	errDial := errors.New("dial failed")
+	cb := soteria.New(soteria.Settings{})
+	c := &Client{Breaker: cb, Next: doerFunc(func(*fasthttp.Request, *fasthttp.Response) error { return errDial })}
+
+	if err := c.Do(fasthttp.AcquireRequest(), fasthttp.AcquireResponse()); err != errDial {
+		t.Errorf("Do = %v, want %v", err, errDial)
+	}
+	if s := cb.Stats(); s.TotalFailures != 1 {
+		t.Errorf("Stats = %+v, want one failure", s)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	cb := soteria.New(soteria.Settings{Timeout: 30 * time.Second})
+	h := Handler(cb, func(ctx *fasthttp.RequestCtx) {
+		ctx.Error("broken", fasthttp.StatusInternalServerError)
+	}, nil)
+
+	for i := 0; i < 6; i++ {
+		var ctx fasthttp.RequestCtx
+		h(&ctx)
+		if ctx.Response.StatusCode() != fasthttp.StatusInternalServerError {
+			t.Fatalf("status = %d, want the 500 of the handler", ctx.Response.StatusCode())
+		}
+	}
+
+	var ctx fasthttp.RequestCtx
+	h(&ctx)
+	if ctx.Response.StatusCode() != fasthttp.StatusServiceUnavailable {
+		t.Errorf("status while open = %d, want 503", ctx.Response.StatusCode())
+	}
+	if ra := string(ctx.Response.Header.Peek("Retry-After")); ra != "30" {
+		t.Errorf("Retry-After = %q, want 30", ra)
+	}
+}
@@ -0,0 +1,111 @@
+package soteriagrpc
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	"github.com/jtejido/soteria"
+)
+
+// KeyFunc returns the key of the breaker of a call to the full method,
+// such as "/payments.Payments/Charge", of target: the target of the client
+// connection for clients, the address of the peer for servers.
+type KeyFunc func(method, target string) string
+
+// ByMethod keys breakers by full method.
+func ByMethod(method, target string) string {
+	return method
+}
+
+// ByService keys breakers by service, such as "payments.Payments".
+func ByService(method, target string) string {
+	service, _, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	return service
+}
+
+// ByTarget keys breakers by target host, such as "pay" for the targets
+// "pay:443" and "dns:///pay:443".
+func ByTarget(method, target string) string {
+	if _, endpoint, ok := strings.Cut(target, ":///"); ok {
+		target = endpoint
+	}
+	if host, _, err := net.SplitHostPort(target); err == nil {
+		return host
+	}
+	return target
+}
+
+// Breakers guards gRPC calls with a CircuitBreaker per key, so that a
+// failing method, service or host does not reject the calls to the others.
+// Breakers are registered in Registry, named Settings.Name/KEY, or KEY if
+// Settings.Name is empty.
+type Breakers struct {
+	Registry *soteria.Registry
+	// Settings are the settings of every breaker, before Overrides.
+	Settings soteria.Settings
+	// Key keys the breakers. If nil, ByMethod is used.
+	Key KeyFunc
+	// Overrides are applied to the Settings of the breakers of their key,
+	// such as those of soteria.LoadSettingsConfigs.
+	Overrides map[string]soteria.SettingsConfig
+	// Classifier decides on the status codes. If nil, ServerErrors is used.
+	Classifier CodeClassifier
+}
+
+// Breaker returns the CircuitBreaker of key, creating it if needed.
+func (b *Breakers) Breaker(key string) *soteria.CircuitBreaker {
+	name := key
+	if b.Settings.Name != "" {
+		name = b.Settings.Name + "/" + key
+	}
+	if cb, ok := b.Registry.Get(name); ok {
+		return cb
+	}
+
+	settings := b.Settings
+	if o, ok := b.Overrides[key]; ok {
+		o.Apply(&settings)
+	}
+	return b.Registry.GetOrCreate(name, settings)
+}
+
+func (b *Breakers) breaker(method, target string) *soteria.CircuitBreaker {
+	key := b.Key
+	if key == nil {
+		key = ByMethod
+	}
+	return b.Breaker(key(method, target))
+}
+
+// UnaryClientInterceptor is UnaryClientInterceptor with the breaker of
+// each call.
+func (b *Breakers) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	classifier := b.Classifier.orDefault()
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var target string
+		if cc != nil {
+			target = cc.Target()
+		}
+		return classified(ctx, b.breaker(method, target), classifier, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
+
+// UnaryServerInterceptor is UnaryServerInterceptor with the breaker of
+// each call.
+func (b *Breakers) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	classifier := b.Classifier.orDefault()
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var target string
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			target = p.Addr.String()
+		}
+
+		return serve(ctx, b.breaker(info.FullMethod, target), classifier, req, handler)
+	}
+}
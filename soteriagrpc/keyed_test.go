@@ -0,0 +1,84 @@
+package soteriagrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestKeyFuncs(t *testing.T) {
+	const method = "/payments.Payments/Charge"
+	if k := ByMethod(method, "pay:443"); k != method {
+		t.Errorf("ByMethod = %q", k)
+	}
+	if k := ByService(method, "pay:443"); k != "payments.Payments" {
+		t.Errorf("ByService = %q", k)
+	}
+	if k := ByTarget(method, "pay:443"); k != "pay" {
+		t.Errorf("ByTarget = %q", k)
+	}
+	if k := ByTarget(method, "dns:///pay:443"); k != "pay" {
+		t.Errorf("ByTarget with a scheme = %q", k)
+	}
+	if k := ByTarget(method, "dns:///pay"); k != "pay" {
+		t.Errorf("ByTarget without a port = %q", k)
+	}
+}
+
+func TestBreakersByService(t *testing.T) {
+	b := &Breakers{
+		Registry:  soteria.NewRegistry(),
+		Settings:  soteria.Settings{Name: "grpc"},
+		Key:       ByService,
+		Overrides: map[string]soteria.SettingsConfig{"payments.Payments": {Timeout: 2 * time.Minute}},
+	}
+	intercept := b.UnaryClientInterceptor()
+
+	unavailable := status.Error(codes.Unavailable, "down")
+	for i := 0; i < 10; i++ {
+		invoke, _ := invoker(unavailable)
+		intercept(context.Background(), "/payments.Payments/Charge", nil, nil, nil, invoke)
+	}
+
+	payments := b.Breaker("payments.Payments")
+	soteriatest.AssertOpen(t, payments)
+	if payments.Name() != "grpc/payments.Payments" || payments.Timeout() != 2*time.Minute {
+		t.Errorf("breaker %q with timeout %v", payments.Name(), payments.Timeout())
+	}
+	if _, ok := b.Registry.Get("grpc/payments.Payments"); !ok {
+		t.Error("breaker not registered")
+	}
+
+	invoke, calls := invoker()
+	if err := intercept(context.Background(), "/inventory.Inventory/Get", nil, nil, nil, invoke); err != nil || *calls != 1 {
+		t.Errorf("call to another service = %v after %d calls", err, *calls)
+	}
+	if cb := b.Breaker("inventory.Inventory"); cb.Timeout() == 2*time.Minute {
+		t.Error("override applied to another key")
+	}
+}
+
+func TestBreakersServerByTarget(t *testing.T) {
+	b := &Breakers{Registry: soteria.NewRegistry(), Key: ByTarget}
+	intercept := b.UnaryServerInterceptor()
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000}})
+	b.Breaker("10.0.0.1").ForceOpen()
+
+	_, err := intercept(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/db.DB/Get"}, func(ctx context.Context, req any) (any, error) {
+		t.Error("handler called for an isolated peer")
+		return nil, nil
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("call = %v, want Unavailable", err)
+	}
+}
@@ -0,0 +1,209 @@
+// Package soteriagrpc guards gRPC calls with soteria CircuitBreakers
+// through interceptors.
+package soteriagrpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jtejido/soteria"
+)
+
+// CodeClassifier reports whether a status code counts as a failure.
+type CodeClassifier func(code codes.Code) bool
+
+// ServerErrors classifies the codes of an unhealthy or overloaded server as
+// failures: Unknown, DeadlineExceeded, ResourceExhausted, Internal,
+// Unavailable and DataLoss. Errors of the caller, such as InvalidArgument
+// or NotFound, are successes.
+var ServerErrors = Codes(codes.Unknown, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unavailable, codes.DataLoss)
+
+// Codes classifies exactly codes as failures.
+func Codes(failures ...codes.Code) CodeClassifier {
+	set := make(map[codes.Code]bool, len(failures))
+	for _, c := range failures {
+		set[c] = true
+	}
+
+	return func(code codes.Code) bool {
+		return set[code]
+	}
+}
+
+// IsSuccessful can be used as Settings.IsSuccessful, judging an error by
+// its status code. Errors without one have code Unknown.
+func (c CodeClassifier) IsSuccessful(err error) bool {
+	return err == nil || !c(status.Code(err))
+}
+
+func (c CodeClassifier) orDefault() CodeClassifier {
+	if c == nil {
+		return ServerErrors
+	}
+	return c
+}
+
+// classified runs invoke through cb, returning its error as is whether it
+// counts as a failure or not.
+func classified(ctx context.Context, cb *soteria.CircuitBreaker, classifier CodeClassifier, invoke func(ctx context.Context) error) error {
+	var callErr error
+	err := cb.RunContext(ctx, func(ctx context.Context) error {
+		callErr = invoke(ctx)
+		if callErr != nil && classifier(status.Code(callErr)) {
+			return callErr
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return callErr
+}
+
+// UnaryClientInterceptor sends unary calls through cb. Calls failing with a
+// code classifier, or ServerErrors if nil, reports count as failures.
+// Calls rejected by cb fail with its error, without being sent.
+func UnaryClientInterceptor(cb *soteria.CircuitBreaker, classifier CodeClassifier) grpc.UnaryClientInterceptor {
+	classifier = classifier.orDefault()
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return classified(ctx, cb, classifier, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
+
+// ProbeMetadata is the conventional metadata key marking half-open probes,
+// for ProbeUnaryClientInterceptor.
+const ProbeMetadata = "soteria-probe"
+
+// ProbeUnaryClientInterceptor sets the metadata key to "1" on the calls
+// admitted as half-open probes by a breaker interceptor before it in the
+// chain, so that the server can tell them apart.
+func ProbeUnaryClientInterceptor(key string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if soteria.IsProbe(ctx) {
+			ctx = metadata.AppendToOutgoingContext(ctx, key, "1")
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// UnaryServerInterceptor serves unary calls through cb. Calls failing with
+// a code classifier, or ServerErrors if nil, reports count as failures.
+// Calls rejected by cb fail with Unavailable.
+func UnaryServerInterceptor(cb *soteria.CircuitBreaker, classifier CodeClassifier) grpc.UnaryServerInterceptor {
+	classifier = classifier.orDefault()
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return serve(ctx, cb, classifier, req, handler)
+	}
+}
+
+func serve(ctx context.Context, cb *soteria.CircuitBreaker, classifier CodeClassifier, req any, handler grpc.UnaryHandler) (any, error) {
+	var resp any
+	err := classified(ctx, cb, classifier, func(ctx context.Context) error {
+		var err error
+		resp, err = handler(ctx, req)
+		return err
+	})
+	if soteria.IsRejected(err) {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return resp, err
+}
+
+// RetryUnaryClientInterceptor retries unary calls with retry. If
+// retry.Retryable is nil, calls failing with a code classifier, or
+// ServerErrors if nil, reports are retried.
+func RetryUnaryClientInterceptor(retry soteria.Retry, classifier CodeClassifier) grpc.UnaryClientInterceptor {
+	if retry.Retryable == nil {
+		retry.Retryable = func(err error) bool { return !classifier.orDefault().IsSuccessful(err) }
+	}
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return retry.Do(ctx, func(ctx context.Context) error {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		})
+	}
+}
+
+// ClientStack composes the unary interceptors of a client in the order
+//
+//	Tracing → Metrics → Breaker → Retry
+//
+// as soteria.HTTPStack does for HTTP clients, and for the same reasons.
+type ClientStack struct {
+	Tracing grpc.UnaryClientInterceptor
+	Metrics grpc.UnaryClientInterceptor
+
+	Breaker    *soteria.CircuitBreaker
+	Classifier CodeClassifier
+	Retry      soteria.Retry
+
+	// ProbeMetadata, if not empty, marks the half-open probes of the
+	// Breaker, as with ProbeUnaryClientInterceptor.
+	ProbeMetadata string
+}
+
+// Interceptors returns the interceptors of the stack, for
+// grpc.WithChainUnaryInterceptor.
+func (s ClientStack) Interceptors() []grpc.UnaryClientInterceptor {
+	var chain []grpc.UnaryClientInterceptor
+	if s.Tracing != nil {
+		chain = append(chain, s.Tracing)
+	}
+	if s.Metrics != nil {
+		chain = append(chain, s.Metrics)
+	}
+	if s.Breaker != nil {
+		chain = append(chain, UnaryClientInterceptor(s.Breaker, s.Classifier))
+		if s.ProbeMetadata != "" {
+			chain = append(chain, ProbeUnaryClientInterceptor(s.ProbeMetadata))
+		}
+	}
+	if s.Retry.Attempts > 1 {
+		chain = append(chain, RetryUnaryClientInterceptor(s.Retry, s.Classifier))
+	}
+	return chain
+}
+
+// DialOption returns the stack as a grpc.DialOption.
+func (s ClientStack) DialOption() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(s.Interceptors()...)
+}
+
+// ServerStack composes the unary interceptors of a server in the order
+//
+//	Tracing → Metrics → Breaker
+//
+// so that rejected calls are traced and measured like the others.
+type ServerStack struct {
+	Tracing grpc.UnaryServerInterceptor
+	Metrics grpc.UnaryServerInterceptor
+
+	Breaker    *soteria.CircuitBreaker
+	Classifier CodeClassifier
+}
+
+// Interceptors returns the interceptors of the stack, for
+// grpc.ChainUnaryInterceptor.
+func (s ServerStack) Interceptors() []grpc.UnaryServerInterceptor {
+	var chain []grpc.UnaryServerInterceptor
+	if s.Tracing != nil {
+		chain = append(chain, s.Tracing)
+	}
+	if s.Metrics != nil {
+		chain = append(chain, s.Metrics)
+	}
+	if s.Breaker != nil {
+		chain = append(chain, UnaryServerInterceptor(s.Breaker, s.Classifier))
+	}
+	return chain
+}
+
+// ServerOption returns the stack as a grpc.ServerOption.
+func (s ServerStack) ServerOption() grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(s.Interceptors()...)
+}
@@ -0,0 +1,132 @@
+package soteriagrpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func invoker(errs ...error) (grpc.UnaryInvoker, *int) {
+	calls := 0
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		calls++
+		if calls > len(errs) {
+			return nil
+		}
+		return errs[calls-1]
+	}, &calls
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	cb := soteria.New(soteria.Settings{Timeout: time.Minute})
+	intercept := UnaryClientInterceptor(cb, nil)
+
+	notFound := status.Error(codes.NotFound, "no row")
+	invoke, _ := invoker(notFound)
+	if err := intercept(context.Background(), "/db.DB/Get", nil, nil, nil, invoke); status.Code(err) != codes.NotFound {
+		t.Errorf("call = %v, want NotFound", err)
+	}
+	if s := cb.Stats(); s.TotalSuccesses != 1 {
+		t.Errorf("Stats = %+v, want NotFound counted as a success", s)
+	}
+
+	soteriatest.Trip(t, cb, 10)
+	invoke, calls := invoker()
+	if err := intercept(context.Background(), "/db.DB/Get", nil, nil, nil, invoke); !errors.Is(err, soteria.ErrOpenState) || *calls != 0 {
+		t.Errorf("call while open = %v after %d calls, want ErrOpenState without calling", err, *calls)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	cb := soteria.New(soteria.Settings{Timeout: time.Minute})
+	soteriatest.Trip(t, cb, 10)
+
+	intercept := UnaryServerInterceptor(cb, nil)
+	_, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		t.Error("handler called while open")
+		return nil, nil
+	})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("call while open = %v, want Unavailable", err)
+	}
+}
+
+func TestClientStack(t *testing.T) {
+	var order []string
+	layer := func(name string) grpc.UnaryClientInterceptor {
+		return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			order = append(order, name)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+
+	cb := soteria.New(soteria.Settings{})
+	chain := ClientStack{
+		Tracing: layer("tracing"),
+		Metrics: layer("metrics"),
+		Breaker: cb,
+		Retry:   soteria.Retry{Attempts: 3, Backoff: time.Millisecond},
+	}.Interceptors()
+	chain = append(chain, layer("invoke"))
+
+	unavailable := status.Error(codes.Unavailable, "down")
+	invoke, calls := invoker(unavailable, unavailable)
+
+	var call grpc.UnaryInvoker = invoke
+	for i := len(chain) - 1; i >= 0; i-- {
+		intercept, next := chain[i], call
+		call = func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return intercept(ctx, method, req, reply, cc, next, opts...)
+		}
+	}
+
+	if err := call(context.Background(), "/db.DB/Get", nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 3 || strings.Join(order, " ") != "tracing metrics invoke invoke invoke" {
+		t.Errorf("%d calls through %v", *calls, order)
+	}
+	if s := cb.Stats(); s.Requests != 1 || s.TotalSuccesses != 1 {
+		t.Errorf("Stats = %+v, want one successful request for all attempts", s)
+	}
+}
+
+func TestProbeMetadata(t *testing.T) {
+	clock := soteriatest.NewClock(time.Now())
+	cb := soteria.New(soteria.Settings{Timeout: time.Minute, Clock: clock})
+	chain := ClientStack{Breaker: cb, ProbeMetadata: ProbeMetadata}.Interceptors()
+
+	var probes []string
+	invoke := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		probes = append(probes, strings.Join(md.Get(ProbeMetadata), ","))
+		return nil
+	}
+	call := func() error {
+		return chain[0](context.Background(), "/db.DB/Get", nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return chain[1](ctx, method, req, reply, cc, invoke, opts...)
+		})
+	}
+
+	if err := call(); err != nil {
+		t.Fatal(err)
+	}
+	soteriatest.Trip(t, cb, 10)
+	soteriatest.AdvanceToHalfOpen(t, clock, cb)
+	if err := call(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(probes, " "); got != " 1" {
+		t.Errorf("probe metadata = %q, want only the probe marked", got)
+	}
+}
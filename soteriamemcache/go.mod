@@ -0,0 +1,9 @@
+module github.com/jtejido/soteria/soteriamemcache
+
+go 1.25.0
+
+require github.com/jtejido/soteria v0.0.0
+
+require github.com/bradfitz/gomemcache v0.0.0-20260422231931-4d751bb6e37c
+
+replace github.com/jtejido/soteria => ../
@@ -0,0 +1,160 @@
+// Package soteriamemcache shards soteria circuit breakers over the servers
+// of a gomemcache client, so that a failing server degrades into cache
+// misses, and traffic to the origin, rather than into errors:
+//
+//	ss := new(memcache.ServerList)
+//	ss.SetServers("cache-1:11211", "cache-2:11211")
+//	c := soteriamemcache.New(ss, registry, soteria.Settings{Name: "cache"})
+package soteriamemcache
+
+import (
+	"errors"
+
+	"github.com/bradfitz/gomemcache/memcache"
+
+	"github.com/jtejido/soteria"
+)
+
+// Client is a memcache.Client guarding every server with a CircuitBreaker.
+// Reads from a server whose breaker rejects them return
+// memcache.ErrCacheMiss; writes return the rejection error, since dropping
+// a Delete could leave stale entries behind once the server recovers.
+// The methods of memcache.Client not redefined by Client are not guarded.
+//
+// Cache misses and the other errors memcache reports about items, such as
+// memcache.ErrNotStored, count as successes unless settings.IsSuccessful
+// is set.
+type Client struct {
+	*memcache.Client
+
+	selector memcache.ServerSelector
+	registry *soteria.Registry
+	settings soteria.Settings
+}
+
+// New returns a Client for the servers of selector that keeps their
+// CircuitBreakers in registry, creating them from settings. The breaker
+// of a server is named after its address, prefixed with settings.Name and
+// a slash if settings.Name is set.
+func New(selector memcache.ServerSelector, registry *soteria.Registry, settings soteria.Settings) *Client {
+	if settings.IsSuccessful == nil {
+		settings.IsSuccessful = isSuccessful
+	}
+	return &Client{
+		Client:   memcache.NewFromSelector(selector),
+		selector: selector,
+		registry: registry,
+		settings: settings,
+	}
+}
+
+func isSuccessful(err error) bool {
+	return err == nil ||
+		errors.Is(err, memcache.ErrCacheMiss) ||
+		errors.Is(err, memcache.ErrNotStored) ||
+		errors.Is(err, memcache.ErrCASConflict) ||
+		errors.Is(err, memcache.ErrMalformedKey)
+}
+
+// Breaker returns the CircuitBreaker of the server at addr, creating it if
+// needed.
+func (c *Client) Breaker(addr string) *soteria.CircuitBreaker {
+	name := addr
+	if c.settings.Name != "" {
+		name = c.settings.Name + "/" + addr
+	}
+	return c.registry.GetOrCreate(name, c.settings)
+}
+
+// breaker returns the CircuitBreaker of the server of key.
+func (c *Client) breaker(key string) (*soteria.CircuitBreaker, error) {
+	addr, err := c.selector.PickServer(key)
+	if err != nil {
+		return nil, err
+	}
+	return c.Breaker(addr.String()), nil
+}
+
+// do runs fn through the breaker of the server of key.
+func (c *Client) do(key string, fn func() error) error {
+	cb, err := c.breaker(key)
+	if err != nil {
+		return err
+	}
+	return cb.Run(fn)
+}
+
+// rejected reports whether err is a rejection of a CircuitBreaker.
+func rejected(err error) bool {
+	return errors.Is(err, soteria.ErrOpenState) ||
+		errors.Is(err, soteria.ErrIsolated) ||
+		errors.Is(err, soteria.ErrTooManyRequests) ||
+		errors.Is(err, soteria.ErrMaintenance)
+}
+
+func (c *Client) Get(key string) (*memcache.Item, error) {
+	var item *memcache.Item
+	err := c.do(key, func() error {
+		var err error
+		item, err = c.Client.Get(key)
+		return err
+	})
+	if rejected(err) {
+		return nil, memcache.ErrCacheMiss
+	}
+	return item, err
+}
+
+// GetMulti is like memcache.Client.GetMulti, sending the keys of every
+// server through its CircuitBreaker. The keys of rejecting servers are
+// missing from the result.
+func (c *Client) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	byServer := make(map[*soteria.CircuitBreaker][]string)
+	for _, key := range keys {
+		cb, err := c.breaker(key)
+		if err != nil {
+			return nil, err
+		}
+		byServer[cb] = append(byServer[cb], key)
+	}
+
+	items := make(map[string]*memcache.Item, len(keys))
+	var errs []error
+	for cb, keys := range byServer {
+		err := cb.Run(func() error {
+			found, err := c.Client.GetMulti(keys)
+			for k, item := range found {
+				items[k] = item
+			}
+			return err
+		})
+		if err != nil && !rejected(err) {
+			errs = append(errs, err)
+		}
+	}
+	return items, errors.Join(errs...)
+}
+
+func (c *Client) Set(item *memcache.Item) error {
+	return c.do(item.Key, func() error { return c.Client.Set(item) })
+}
+
+func (c *Client) Add(item *memcache.Item) error {
+	return c.do(item.Key, func() error { return c.Client.Add(item) })
+}
+
+func (c *Client) Replace(item *memcache.Item) error {
+	return c.do(item.Key, func() error { return c.Client.Replace(item) })
+}
+
+func (c *Client) CompareAndSwap(item *memcache.Item) error {
+	return c.do(item.Key, func() error { return c.Client.CompareAndSwap(item) })
+}
+
+func (c *Client) Delete(key string) error {
+	return c.do(key, func() error { return c.Client.Delete(key) })
+}
+
+func (c *Client) Touch(key string, seconds int32) error {
+	return c.do(key, func() error { return c.Client.Touch(key, seconds) })
+}
@@ -0,0 +1,134 @@
+package soteriamemcache
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bradfitz/gomemcache/memcache"
+
+	"github.com/jtejido/soteria"
+)
+
+// serve runs a memcached stand-in answering every get with a hit for the
+// key "hit" and every set with STORED.
+func serve(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					fields := strings.Fields(line)
+					switch fields[0] {
+					case "gets", "get":
+						for _, key := range fields[1:] {
+							if key == "hit" {
+								conn.Write([]byte("VALUE hit 0 1 1\r\nx\r\n"))
+							}
+						}
+						conn.Write([]byte("END\r\n"))
+					case "set":
+						r.ReadString('\n')
+						conn.Write([]byte("STORED\r\n"))
+					}
+				}
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+// closedAddr returns an address nothing listens on.
+func closedAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return addr
+}
+
+func TestGet(t *testing.T) {
+	ss := new(memcache.ServerList)
+	ss.SetServers(serve(t))
+	c := New(ss, soteria.NewRegistry(), soteria.Settings{Name: "cache"})
+
+	if item, err := c.Get("hit"); err != nil || string(item.Value) != "x" {
+		t.Fatalf("Get(hit) = %v, %v", item, err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := c.Get("miss"); err != memcache.ErrCacheMiss {
+			t.Fatalf("Get(miss) = %v, want ErrCacheMiss", err)
+		}
+	}
+	if err := c.Set(&memcache.Item{Key: "k", Value: []byte("v")}); err != nil {
+		t.Fatal(err)
+	}
+
+	addr, _ := ss.PickServer("hit")
+	if cb := c.Breaker(addr.String()); cb.State() != soteria.StateClosed {
+		t.Errorf("State = %v after cache misses, want closed", cb.State())
+	}
+}
+
+func TestDownServerMisses(t *testing.T) {
+	ss := new(memcache.ServerList)
+	ss.SetServers(closedAddr(t))
+	c := New(ss, soteria.NewRegistry(), soteria.Settings{})
+
+	for i := 0; i < 6; i++ {
+		if _, err := c.Get("k"); err == nil || err == memcache.ErrCacheMiss {
+			t.Fatalf("Get from a down server = %v, want its error", err)
+		}
+	}
+
+	if _, err := c.Get("k"); err != memcache.ErrCacheMiss {
+		t.Errorf("Get once open = %v, want ErrCacheMiss", err)
+	}
+	if items, err := c.GetMulti([]string{"a", "b"}); err != nil || len(items) != 0 {
+		t.Errorf("GetMulti once open = %v, %v, want no items", items, err)
+	}
+	if err := c.Set(&memcache.Item{Key: "k"}); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("Set once open = %v, want ErrOpenState", err)
+	}
+}
+
+func TestGetMultiAcrossServers(t *testing.T) {
+	down := closedAddr(t)
+	ss := new(memcache.ServerList)
+	ss.SetServers(serve(t), down)
+	c := New(ss, soteria.NewRegistry(), soteria.Settings{})
+	c.Breaker(down).ForceOpen()
+
+	keys := []string{"hit"}
+	for i := 0; i < 20; i++ {
+		keys = append(keys, "k"+strconv.Itoa(i))
+	}
+
+	items, err := c.GetMulti(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr, _ := ss.PickServer("hit"); addr.String() != down && items["hit"] == nil {
+		t.Errorf("GetMulti = %v, want hit from the healthy server", items)
+	}
+}
@@ -0,0 +1,9 @@
+module github.com/jtejido/soteria/soteriamongo
+
+go 1.25.0
+
+require github.com/jtejido/soteria v0.0.0
+
+require go.mongodb.org/mongo-driver/v2 v2.9.1
+
+replace github.com/jtejido/soteria => ../
@@ -0,0 +1,94 @@
+// Package soteriamongo feeds the outcomes of the commands of a MongoDB
+// client to a soteria circuit breaker:
+//
+//	m := soteriamongo.New(cb)
+//	client, err := mongo.Connect(options.Client().ApplyURI(uri).
+//		SetMonitor(m.CommandMonitor()).
+//		SetPoolMonitor(m.PoolMonitor()))
+//
+// Monitors only observe commands; they cannot stop them. Call Allow, or
+// use Do, before issuing commands so they are short-circuited while the
+// breaker is open.
+package soteriamongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+
+	"github.com/jtejido/soteria"
+)
+
+// Monitor reports the outcomes of MongoDB commands to a CircuitBreaker.
+type Monitor struct {
+	cb *soteria.CircuitBreaker
+}
+
+func New(cb *soteria.CircuitBreaker) *Monitor {
+	return &Monitor{cb: cb}
+}
+
+// CommandMonitor returns a monitor reporting every command that succeeded
+// or failed, with its duration.
+func (m *Monitor) CommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			m.cb.ReportSuccess(e.Duration)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			m.cb.ReportFailure(e.Failure, e.Duration)
+		},
+	}
+}
+
+// PoolMonitor returns a monitor reporting every connection check out that
+// failed because of a timeout or a connection error, which no command
+// would report.
+func (m *Monitor) PoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			if e.Type != event.ConnectionCheckOutFailed {
+				return
+			}
+			if e.Reason == event.ReasonTimedOut || e.Reason == event.ReasonConnectionErrored {
+				err := e.Error
+				if err == nil {
+					err = &CheckOutError{Address: e.Address, Reason: e.Reason}
+				}
+				m.cb.ReportFailure(err, e.Duration)
+			}
+		},
+	}
+}
+
+// CheckOutError reports a failed connection check out that carried no
+// error of its own.
+type CheckOutError struct {
+	Address string
+	Reason  string
+}
+
+func (e *CheckOutError) Error() string {
+	return "soteriamongo: connection check out from " + e.Address + " failed: " + e.Reason
+}
+
+// Allow returns the error the CircuitBreaker rejects requests with while it
+// is open or isolated, and nil otherwise. Allow does not admit a request;
+// outcomes are still reported by the monitors.
+func (m *Monitor) Allow() error {
+	switch m.cb.State() {
+	case soteria.StateOpen:
+		return &soteria.OpenStateError{Remaining: m.cb.RemainingOpenTime()}
+	case soteria.StateIsolated:
+		return soteria.ErrIsolated
+	}
+	return nil
+}
+
+// Do calls fn unless Allow returns an error.
+func (m *Monitor) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := m.Allow(); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,82 @@
+package soteriamongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestCommandMonitor(t *testing.T) {
+	var latencies []time.Duration
+	cb := soteria.New(soteria.Settings{
+		OnTrace: func(e soteria.TraceEvent) {
+			if e.Kind == soteria.TraceSuccess || e.Kind == soteria.TraceFailure {
+				latencies = append(latencies, e.Latency)
+			}
+		},
+	})
+	cm := New(cb).CommandMonitor()
+
+	cm.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", Duration: time.Millisecond},
+	})
+	for i := 0; i < 6; i++ {
+		cm.Failed(context.Background(), &event.CommandFailedEvent{
+			CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find", Duration: time.Second},
+			Failure:              errors.New("node is recovering"),
+		})
+	}
+
+	if cb.State() != soteria.StateOpen {
+		t.Errorf("State = %v after six failed commands, want open", cb.State())
+	}
+	if len(latencies) != 7 || latencies[0] != time.Millisecond || latencies[1] != time.Second {
+		t.Errorf("latencies = %v", latencies)
+	}
+}
+
+func TestPoolMonitor(t *testing.T) {
+	cb := soteria.New(soteria.Settings{})
+	pm := New(cb).PoolMonitor()
+
+	pm.Event(&event.PoolEvent{Type: event.ConnectionCheckedOut})
+	pm.Event(&event.PoolEvent{Type: event.ConnectionCheckOutFailed, Reason: event.ReasonPoolClosed})
+	if s := cb.Stats(); s.TotalFailures != 0 {
+		t.Errorf("Stats = %+v, want no failure counted", s)
+	}
+
+	pm.Event(&event.PoolEvent{Type: event.ConnectionCheckOutFailed, Reason: event.ReasonTimedOut, Address: "db:27017"})
+	if s := cb.Stats(); s.TotalFailures != 1 {
+		t.Errorf("Stats = %+v, want the timed out check out counted", s)
+	}
+}
+
+func TestDo(t *testing.T) {
+	cb := soteria.New(soteria.Settings{})
+	m := New(cb)
+
+	calls := 0
+	fn := func(ctx context.Context) error { calls++; return nil }
+
+	if err := m.Do(context.Background(), fn); err != nil || calls != 1 {
+		t.Errorf("Do while closed = %v after %d calls", err, calls)
+	}
+
+	cb.ForceOpen()
+	if err := m.Do(context.Background(), fn); !errors.Is(err, soteria.ErrIsolated) || calls != 1 {
+		t.Errorf("Do while isolated = %v after %d calls", err, calls)
+	}
+
+	cb.Reset()
+	for i := 0; i < 6; i++ {
+		cb.ReportFailure(errors.New("down"), 0)
+	}
+	if err := m.Do(context.Background(), fn); !errors.Is(err, soteria.ErrOpenState) || calls != 1 {
+		t.Errorf("Do while open = %v after %d calls", err, calls)
+	}
+}
@@ -0,0 +1,194 @@
+package soteriapb
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jtejido/soteria"
+)
+
+// FromState converts a soteria.State. States defined with
+// soteria.DefineState convert to STATE_CUSTOM and their name, which is
+// empty for the others.
+func FromState(s soteria.State) (state State, custom string) {
+	switch s {
+	case soteria.StateClosed:
+		return State_STATE_CLOSED, ""
+	case soteria.StateHalfOpen:
+		return State_STATE_HALF_OPEN, ""
+	case soteria.StateOpen:
+		return State_STATE_OPEN, ""
+	case soteria.StateIsolated:
+		return State_STATE_ISOLATED, ""
+	}
+	return State_STATE_CUSTOM, s.String()
+}
+
+// ToState converts s to a soteria.State. STATE_CUSTOM converts by custom,
+// its name, which must have been defined with soteria.DefineState in this
+// process. STATE_UNSPECIFIED does not convert.
+func ToState(s State, custom string) (soteria.State, error) {
+	switch s {
+	case State_STATE_CLOSED:
+		return soteria.StateClosed, nil
+	case State_STATE_HALF_OPEN:
+		return soteria.StateHalfOpen, nil
+	case State_STATE_OPEN:
+		return soteria.StateOpen, nil
+	case State_STATE_ISOLATED:
+		return soteria.StateIsolated, nil
+	case State_STATE_CUSTOM:
+		var state soteria.State
+		if err := state.UnmarshalText([]byte(custom)); err != nil {
+			return 0, err
+		}
+		return state, nil
+	}
+	return 0, fmt.Errorf("soteriapb: cannot convert %v", s)
+}
+
+// FromStats converts a soteria.Stats.
+func FromStats(st soteria.Stats) *Stats {
+	s := &Stats{
+		Requests:             st.Requests,
+		TotalSuccesses:       st.TotalSuccesses,
+		TotalFailures:        st.TotalFailures,
+		ConsecutiveSuccesses: st.ConsecutiveSuccesses,
+		ConsecutiveFailures:  st.ConsecutiveFailures,
+		WeightedRequests:     st.WeightedRequests,
+		WeightedSuccesses:    st.WeightedSuccesses,
+		WeightedFailures:     st.WeightedFailures,
+		Overflows:            st.Overflows,
+		Generation:           st.Generation,
+	}
+
+	if len(st.FailuresByCategory) > 0 {
+		s.FailuresByCategory = make(map[string]uint32, len(st.FailuresByCategory))
+		for category, n := range st.FailuresByCategory {
+			s.FailuresByCategory[string(category)] = n
+		}
+	}
+
+	for _, w := range st.Windows {
+		s.Windows = append(s.Windows, &WindowStats{
+			Window:    durationpb.New(w.Window),
+			Successes: w.Successes,
+			Failures:  w.Failures,
+		})
+	}
+	return s
+}
+
+// ToStats converts s to a soteria.Stats.
+func ToStats(s *Stats) soteria.Stats {
+	st := soteria.Stats{
+		Requests:             s.GetRequests(),
+		TotalSuccesses:       s.GetTotalSuccesses(),
+		TotalFailures:        s.GetTotalFailures(),
+		ConsecutiveSuccesses: s.GetConsecutiveSuccesses(),
+		ConsecutiveFailures:  s.GetConsecutiveFailures(),
+		WeightedRequests:     s.GetWeightedRequests(),
+		WeightedSuccesses:    s.GetWeightedSuccesses(),
+		WeightedFailures:     s.GetWeightedFailures(),
+		Overflows:            s.GetOverflows(),
+		Generation:           s.GetGeneration(),
+	}
+
+	if m := s.GetFailuresByCategory(); len(m) > 0 {
+		st.FailuresByCategory = make(map[soteria.Category]uint32, len(m))
+		for category, n := range m {
+			st.FailuresByCategory[soteria.Category(category)] = n
+		}
+	}
+
+	for _, w := range s.GetWindows() {
+		st.Windows = append(st.Windows, soteria.WindowStats{
+			Window:    w.GetWindow().AsDuration(),
+			Successes: w.GetSuccesses(),
+			Failures:  w.GetFailures(),
+		})
+	}
+	return st
+}
+
+var kinds = map[string]EventKind{
+	soteria.TraceSuccess:    EventKind_EVENT_KIND_SUCCESS,
+	soteria.TraceFailure:    EventKind_EVENT_KIND_FAILURE,
+	soteria.TraceRejected:   EventKind_EVENT_KIND_REJECTED,
+	soteria.TraceIgnored:    EventKind_EVENT_KIND_IGNORED,
+	soteria.TraceTransition: EventKind_EVENT_KIND_TRANSITION,
+
+	soteria.TraceAnomaly:          EventKind_EVENT_KIND_ANOMALY,
+	soteria.TraceStatsReset:       EventKind_EVENT_KIND_STATS_RESET,
+	soteria.TraceGenerationRolled: EventKind_EVENT_KIND_GENERATION_ROLLED,
+	soteria.TraceProbesExhausted:  EventKind_EVENT_KIND_PROBES_EXHAUSTED,
+}
+
+// FromEvent converts a soteria.TraceEvent. From and To are only set for
+// transitions and exhausted probes.
+func FromEvent(e soteria.TraceEvent) *Event {
+	ev := &Event{
+		Breaker:    e.Breaker,
+		Time:       timestamppb.New(e.Time),
+		Kind:       kinds[e.Kind],
+		Error:      e.Error,
+		Category:   string(e.Category),
+		Labels:     e.Labels,
+		Reason:     e.Reason,
+		Generation: e.Generation,
+		Probe:      e.Probe,
+	}
+
+	if e.Latency != 0 {
+		ev.Latency = durationpb.New(e.Latency)
+	}
+	if e.Stats != nil {
+		ev.Stats = FromStats(*e.Stats)
+	}
+	if e.Kind == soteria.TraceTransition || e.Kind == soteria.TraceProbesExhausted {
+		ev.From, ev.FromCustom = FromState(e.From)
+		ev.To, ev.ToCustom = FromState(e.To)
+	}
+	return ev
+}
+
+// ToEvent converts e to a soteria.TraceEvent. It fails for transitions
+// between states that do not convert.
+func ToEvent(e *Event) (soteria.TraceEvent, error) {
+	var kind string
+	for k, v := range kinds {
+		if v == e.GetKind() {
+			kind = k
+		}
+	}
+
+	te := soteria.TraceEvent{
+		Breaker:    e.GetBreaker(),
+		Time:       e.GetTime().AsTime(),
+		Kind:       kind,
+		Error:      e.GetError(),
+		Category:   soteria.Category(e.GetCategory()),
+		Labels:     e.GetLabels(),
+		Latency:    e.GetLatency().AsDuration(),
+		Reason:     e.GetReason(),
+		Generation: e.GetGeneration(),
+		Probe:      e.GetProbe(),
+	}
+	if e.Stats != nil {
+		stats := ToStats(e.Stats)
+		te.Stats = &stats
+	}
+
+	if kind == soteria.TraceTransition || kind == soteria.TraceProbesExhausted {
+		var err error
+		if te.From, err = ToState(e.GetFrom(), e.GetFromCustom()); err != nil {
+			return soteria.TraceEvent{}, err
+		}
+		if te.To, err = ToState(e.GetTo(), e.GetToCustom()); err != nil {
+			return soteria.TraceEvent{}, err
+		}
+	}
+	return te, nil
+}
@@ -0,0 +1,117 @@
+package soteriapb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+var degraded = soteria.DefineState("soteriapb-test-degraded")
+
+func TestStateRoundTrip(t *testing.T) {
+	for _, s := range []soteria.State{soteria.StateClosed, soteria.StateHalfOpen, soteria.StateOpen, soteria.StateIsolated, degraded} {
+		got, err := ToState(FromState(s))
+		if err != nil {
+			t.Fatalf("%v: %v", s, err)
+		}
+		if got != s {
+			t.Errorf("round trip of %v = %v", s, got)
+		}
+	}
+}
+
+func TestCustomStateCarriesName(t *testing.T) {
+	state, custom := FromState(degraded)
+	if state != State_STATE_CUSTOM || custom != degraded.String() {
+		t.Errorf("FromState(degraded) = %v, %q", state, custom)
+	}
+}
+
+func TestToStateRejectsUnknown(t *testing.T) {
+	if _, err := ToState(State_STATE_UNSPECIFIED, ""); err == nil {
+		t.Error("STATE_UNSPECIFIED converted")
+	}
+	if _, err := ToState(State_STATE_CUSTOM, "no-such-state"); err == nil {
+		t.Error("undefined custom state converted")
+	}
+}
+
+func TestStatsRoundTrip(t *testing.T) {
+	st := soteria.Stats{
+		Requests:            5,
+		TotalSuccesses:      2,
+		TotalFailures:       3,
+		ConsecutiveFailures: 1,
+		FailuresByCategory:  map[soteria.Category]uint32{soteria.CategoryTimeout: 3},
+		Windows:             []soteria.WindowStats{{Window: time.Minute, Successes: 2, Failures: 3}},
+	}
+	if got := ToStats(FromStats(st)); !reflect.DeepEqual(got, st) {
+		t.Errorf("round trip = %+v, want %+v", got, st)
+	}
+}
+
+func TestEventStatesOnlyForTransitions(t *testing.T) {
+	now := time.Unix(1000, 0).UTC()
+
+	failure := FromEvent(soteria.TraceEvent{Breaker: "b", Time: now, Kind: soteria.TraceFailure, Category: soteria.CategoryServer})
+	if failure.GetFrom() != State_STATE_UNSPECIFIED || failure.GetTo() != State_STATE_UNSPECIFIED {
+		t.Errorf("failure event has states %v -> %v", failure.GetFrom(), failure.GetTo())
+	}
+
+	want := soteria.TraceEvent{Breaker: "b", Time: now, Kind: soteria.TraceTransition, From: soteria.StateClosed, To: degraded}
+	got, err := ToEvent(FromEvent(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestEventCarriesLabels(t *testing.T) {
+	labels := map[string]string{"region": "eu", "tier": "db"}
+	e := soteria.TraceEvent{Kind: soteria.TraceSuccess, Time: time.Unix(1, 0).UTC(), Labels: labels}
+
+	got, err := ToEvent(FromEvent(e))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Labels, labels) {
+		t.Errorf("Labels = %v, want %v", got.Labels, labels)
+	}
+}
+
+func TestEventCarriesLatency(t *testing.T) {
+	e := soteria.TraceEvent{Kind: soteria.TraceFailure, Time: time.Unix(1, 0).UTC(), Latency: 250 * time.Millisecond}
+
+	got, err := ToEvent(FromEvent(e))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Latency != e.Latency {
+		t.Errorf("Latency = %v, want %v", got.Latency, e.Latency)
+	}
+}
+
+func TestEventRoundTrip(t *testing.T) {
+	now := time.Unix(1000, 0).UTC()
+	for _, want := range []soteria.TraceEvent{
+		{Breaker: "b", Time: now, Kind: soteria.TraceTransition, From: soteria.StateClosed, To: soteria.StateOpen,
+			Stats: &soteria.Stats{Requests: 6, TotalFailures: 6, ConsecutiveFailures: 6, WeightedRequests: 6, WeightedFailures: 6, Generation: 3}},
+		{Breaker: "b", Time: now, Kind: soteria.TraceStatsReset, Reason: "incident 42"},
+		{Breaker: "b", Time: now, Kind: soteria.TraceGenerationRolled, Generation: 4},
+		{Breaker: "b", Time: now, Kind: soteria.TraceSuccess, Generation: 4, Probe: true},
+		{Breaker: "b", Time: now, Kind: soteria.TraceProbesExhausted, From: soteria.StateHalfOpen, To: soteria.StateOpen,
+			Stats: &soteria.Stats{Requests: 1, TotalFailures: 1}},
+	} {
+		got, err := ToEvent(FromEvent(want))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("round trip = %+v, want %+v", got, want)
+		}
+	}
+}
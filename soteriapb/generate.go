@@ -0,0 +1,6 @@
+// Package soteriapb holds the protobuf definitions of soteria's State,
+// Stats and events, the Go code generated from them, and conversions
+// from and to the soteria types.
+package soteriapb
+
+//go:generate protoc -I .. --go_out=.. --go_opt=paths=source_relative soteriapb/soteria.proto
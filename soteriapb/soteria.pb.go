@@ -0,0 +1,653 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: soteriapb/soteria.proto
+
+package soteriapb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type State int32
+
+const (
+	State_STATE_UNSPECIFIED State = 0
+	State_STATE_CLOSED      State = 1
+	State_STATE_HALF_OPEN   State = 2
+	State_STATE_OPEN        State = 3
+	// an application-defined state, see soteria.DefineState; messages
+	// carrying a state carry its name alongside
+	State_STATE_CUSTOM   State = 4
+	State_STATE_ISOLATED State = 5
+)
+
+// Enum value maps for State.
+var (
+	State_name = map[int32]string{
+		0: "STATE_UNSPECIFIED",
+		1: "STATE_CLOSED",
+		2: "STATE_HALF_OPEN",
+		3: "STATE_OPEN",
+		4: "STATE_CUSTOM",
+		5: "STATE_ISOLATED",
+	}
+	State_value = map[string]int32{
+		"STATE_UNSPECIFIED": 0,
+		"STATE_CLOSED":      1,
+		"STATE_HALF_OPEN":   2,
+		"STATE_OPEN":        3,
+		"STATE_CUSTOM":      4,
+		"STATE_ISOLATED":    5,
+	}
+)
+
+func (x State) Enum() *State {
+	p := new(State)
+	*p = x
+	return p
+}
+
+func (x State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (State) Descriptor() protoreflect.EnumDescriptor {
+	return file_soteriapb_soteria_proto_enumTypes[0].Descriptor()
+}
+
+func (State) Type() protoreflect.EnumType {
+	return &file_soteriapb_soteria_proto_enumTypes[0]
+}
+
+func (x State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use State.Descriptor instead.
+func (State) EnumDescriptor() ([]byte, []int) {
+	return file_soteriapb_soteria_proto_rawDescGZIP(), []int{0}
+}
+
+type EventKind int32
+
+const (
+	EventKind_EVENT_KIND_UNSPECIFIED       EventKind = 0
+	EventKind_EVENT_KIND_SUCCESS           EventKind = 1
+	EventKind_EVENT_KIND_FAILURE           EventKind = 2
+	EventKind_EVENT_KIND_REJECTED          EventKind = 3
+	EventKind_EVENT_KIND_IGNORED           EventKind = 4
+	EventKind_EVENT_KIND_TRANSITION        EventKind = 5
+	EventKind_EVENT_KIND_ANOMALY           EventKind = 6
+	EventKind_EVENT_KIND_STATS_RESET       EventKind = 7
+	EventKind_EVENT_KIND_GENERATION_ROLLED EventKind = 8
+	EventKind_EVENT_KIND_PROBES_EXHAUSTED  EventKind = 9
+)
+
+// Enum value maps for EventKind.
+var (
+	EventKind_name = map[int32]string{
+		0: "EVENT_KIND_UNSPECIFIED",
+		1: "EVENT_KIND_SUCCESS",
+		2: "EVENT_KIND_FAILURE",
+		3: "EVENT_KIND_REJECTED",
+		4: "EVENT_KIND_IGNORED",
+		5: "EVENT_KIND_TRANSITION",
+		6: "EVENT_KIND_ANOMALY",
+		7: "EVENT_KIND_STATS_RESET",
+		8: "EVENT_KIND_GENERATION_ROLLED",
+		9: "EVENT_KIND_PROBES_EXHAUSTED",
+	}
+	EventKind_value = map[string]int32{
+		"EVENT_KIND_UNSPECIFIED":       0,
+		"EVENT_KIND_SUCCESS":           1,
+		"EVENT_KIND_FAILURE":           2,
+		"EVENT_KIND_REJECTED":          3,
+		"EVENT_KIND_IGNORED":           4,
+		"EVENT_KIND_TRANSITION":        5,
+		"EVENT_KIND_ANOMALY":           6,
+		"EVENT_KIND_STATS_RESET":       7,
+		"EVENT_KIND_GENERATION_ROLLED": 8,
+		"EVENT_KIND_PROBES_EXHAUSTED":  9,
+	}
+)
+
+func (x EventKind) Enum() *EventKind {
+	p := new(EventKind)
+	*p = x
+	return p
+}
+
+func (x EventKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_soteriapb_soteria_proto_enumTypes[1].Descriptor()
+}
+
+func (EventKind) Type() protoreflect.EnumType {
+	return &file_soteriapb_soteria_proto_enumTypes[1]
+}
+
+func (x EventKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventKind.Descriptor instead.
+func (EventKind) EnumDescriptor() ([]byte, []int) {
+	return file_soteriapb_soteria_proto_rawDescGZIP(), []int{1}
+}
+
+type Stats struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Requests             uint32                 `protobuf:"varint,1,opt,name=requests,proto3" json:"requests,omitempty"`
+	TotalSuccesses       uint32                 `protobuf:"varint,2,opt,name=total_successes,json=totalSuccesses,proto3" json:"total_successes,omitempty"`
+	TotalFailures        uint32                 `protobuf:"varint,3,opt,name=total_failures,json=totalFailures,proto3" json:"total_failures,omitempty"`
+	ConsecutiveSuccesses uint32                 `protobuf:"varint,4,opt,name=consecutive_successes,json=consecutiveSuccesses,proto3" json:"consecutive_successes,omitempty"`
+	ConsecutiveFailures  uint32                 `protobuf:"varint,5,opt,name=consecutive_failures,json=consecutiveFailures,proto3" json:"consecutive_failures,omitempty"`
+	FailuresByCategory   map[string]uint32      `protobuf:"bytes,6,rep,name=failures_by_category,json=failuresByCategory,proto3" json:"failures_by_category,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Windows              []*WindowStats         `protobuf:"bytes,7,rep,name=windows,proto3" json:"windows,omitempty"`
+	WeightedRequests     float64                `protobuf:"fixed64,8,opt,name=weighted_requests,json=weightedRequests,proto3" json:"weighted_requests,omitempty"`
+	WeightedSuccesses    float64                `protobuf:"fixed64,9,opt,name=weighted_successes,json=weightedSuccesses,proto3" json:"weighted_successes,omitempty"`
+	WeightedFailures     float64                `protobuf:"fixed64,10,opt,name=weighted_failures,json=weightedFailures,proto3" json:"weighted_failures,omitempty"`
+	Overflows            uint32                 `protobuf:"varint,11,opt,name=overflows,proto3" json:"overflows,omitempty"`
+	Generation           uint64                 `protobuf:"varint,12,opt,name=generation,proto3" json:"generation,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Stats) Reset() {
+	*x = Stats{}
+	mi := &file_soteriapb_soteria_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Stats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Stats) ProtoMessage() {}
+
+func (x *Stats) ProtoReflect() protoreflect.Message {
+	mi := &file_soteriapb_soteria_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Stats.ProtoReflect.Descriptor instead.
+func (*Stats) Descriptor() ([]byte, []int) {
+	return file_soteriapb_soteria_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Stats) GetRequests() uint32 {
+	if x != nil {
+		return x.Requests
+	}
+	return 0
+}
+
+func (x *Stats) GetTotalSuccesses() uint32 {
+	if x != nil {
+		return x.TotalSuccesses
+	}
+	return 0
+}
+
+func (x *Stats) GetTotalFailures() uint32 {
+	if x != nil {
+		return x.TotalFailures
+	}
+	return 0
+}
+
+func (x *Stats) GetConsecutiveSuccesses() uint32 {
+	if x != nil {
+		return x.ConsecutiveSuccesses
+	}
+	return 0
+}
+
+func (x *Stats) GetConsecutiveFailures() uint32 {
+	if x != nil {
+		return x.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (x *Stats) GetFailuresByCategory() map[string]uint32 {
+	if x != nil {
+		return x.FailuresByCategory
+	}
+	return nil
+}
+
+func (x *Stats) GetWindows() []*WindowStats {
+	if x != nil {
+		return x.Windows
+	}
+	return nil
+}
+
+func (x *Stats) GetWeightedRequests() float64 {
+	if x != nil {
+		return x.WeightedRequests
+	}
+	return 0
+}
+
+func (x *Stats) GetWeightedSuccesses() float64 {
+	if x != nil {
+		return x.WeightedSuccesses
+	}
+	return 0
+}
+
+func (x *Stats) GetWeightedFailures() float64 {
+	if x != nil {
+		return x.WeightedFailures
+	}
+	return 0
+}
+
+func (x *Stats) GetOverflows() uint32 {
+	if x != nil {
+		return x.Overflows
+	}
+	return 0
+}
+
+func (x *Stats) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+type WindowStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Window        *durationpb.Duration   `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Successes     uint32                 `protobuf:"varint,2,opt,name=successes,proto3" json:"successes,omitempty"`
+	Failures      uint32                 `protobuf:"varint,3,opt,name=failures,proto3" json:"failures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WindowStats) Reset() {
+	*x = WindowStats{}
+	mi := &file_soteriapb_soteria_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WindowStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WindowStats) ProtoMessage() {}
+
+func (x *WindowStats) ProtoReflect() protoreflect.Message {
+	mi := &file_soteriapb_soteria_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WindowStats.ProtoReflect.Descriptor instead.
+func (*WindowStats) Descriptor() ([]byte, []int) {
+	return file_soteriapb_soteria_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *WindowStats) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *WindowStats) GetSuccesses() uint32 {
+	if x != nil {
+		return x.Successes
+	}
+	return 0
+}
+
+func (x *WindowStats) GetFailures() uint32 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+// Event is a soteria.TraceEvent.
+type Event struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Breaker string                 `protobuf:"bytes,1,opt,name=breaker,proto3" json:"breaker,omitempty"`
+	Time    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Kind    EventKind              `protobuf:"varint,3,opt,name=kind,proto3,enum=soteria.v1.EventKind" json:"kind,omitempty"`
+	// set for EVENT_KIND_TRANSITION and EVENT_KIND_PROBES_EXHAUSTED
+	From State `protobuf:"varint,4,opt,name=from,proto3,enum=soteria.v1.State" json:"from,omitempty"`
+	To   State `protobuf:"varint,5,opt,name=to,proto3,enum=soteria.v1.State" json:"to,omitempty"`
+	// set for EVENT_KIND_REJECTED
+	Error string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	// set for EVENT_KIND_FAILURE
+	Category string `protobuf:"bytes,7,opt,name=category,proto3" json:"category,omitempty"`
+	// the names of from and to if they are STATE_CUSTOM
+	FromCustom string            `protobuf:"bytes,8,opt,name=from_custom,json=fromCustom,proto3" json:"from_custom,omitempty"`
+	ToCustom   string            `protobuf:"bytes,9,opt,name=to_custom,json=toCustom,proto3" json:"to_custom,omitempty"`
+	Labels     map[string]string `protobuf:"bytes,10,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// set for EVENT_KIND_SUCCESS, EVENT_KIND_FAILURE and EVENT_KIND_IGNORED
+	Latency *durationpb.Duration `protobuf:"bytes,11,opt,name=latency,proto3" json:"latency,omitempty"`
+	// set for EVENT_KIND_STATS_RESET
+	Reason string `protobuf:"bytes,12,opt,name=reason,proto3" json:"reason,omitempty"`
+	// the generation a request was admitted in, or the new generation for
+	// EVENT_KIND_GENERATION_ROLLED
+	Generation uint64 `protobuf:"varint,13,opt,name=generation,proto3" json:"generation,omitempty"`
+	Probe      bool   `protobuf:"varint,14,opt,name=probe,proto3" json:"probe,omitempty"`
+	// set for EVENT_KIND_TRANSITION, the stats of the generation left, and
+	// EVENT_KIND_PROBES_EXHAUSTED, the stats of the probes
+	Stats         *Stats `protobuf:"bytes,15,opt,name=stats,proto3" json:"stats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_soteriapb_soteria_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_soteriapb_soteria_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_soteriapb_soteria_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Event) GetBreaker() string {
+	if x != nil {
+		return x.Breaker
+	}
+	return ""
+}
+
+func (x *Event) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Event) GetKind() EventKind {
+	if x != nil {
+		return x.Kind
+	}
+	return EventKind_EVENT_KIND_UNSPECIFIED
+}
+
+func (x *Event) GetFrom() State {
+	if x != nil {
+		return x.From
+	}
+	return State_STATE_UNSPECIFIED
+}
+
+func (x *Event) GetTo() State {
+	if x != nil {
+		return x.To
+	}
+	return State_STATE_UNSPECIFIED
+}
+
+func (x *Event) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Event) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Event) GetFromCustom() string {
+	if x != nil {
+		return x.FromCustom
+	}
+	return ""
+}
+
+func (x *Event) GetToCustom() string {
+	if x != nil {
+		return x.ToCustom
+	}
+	return ""
+}
+
+func (x *Event) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Event) GetLatency() *durationpb.Duration {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+func (x *Event) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Event) GetGeneration() uint64 {
+	if x != nil {
+		return x.Generation
+	}
+	return 0
+}
+
+func (x *Event) GetProbe() bool {
+	if x != nil {
+		return x.Probe
+	}
+	return false
+}
+
+func (x *Event) GetStats() *Stats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+var File_soteriapb_soteria_proto protoreflect.FileDescriptor
+
+const file_soteriapb_soteria_proto_rawDesc = "" +
+	"\n" +
+	"\x17soteriapb/soteria.proto\x12\n" +
+	"soteria.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf9\x04\n" +
+	"\x05Stats\x12\x1a\n" +
+	"\brequests\x18\x01 \x01(\rR\brequests\x12'\n" +
+	"\x0ftotal_successes\x18\x02 \x01(\rR\x0etotalSuccesses\x12%\n" +
+	"\x0etotal_failures\x18\x03 \x01(\rR\rtotalFailures\x123\n" +
+	"\x15consecutive_successes\x18\x04 \x01(\rR\x14consecutiveSuccesses\x121\n" +
+	"\x14consecutive_failures\x18\x05 \x01(\rR\x13consecutiveFailures\x12[\n" +
+	"\x14failures_by_category\x18\x06 \x03(\v2).soteria.v1.Stats.FailuresByCategoryEntryR\x12failuresByCategory\x121\n" +
+	"\awindows\x18\a \x03(\v2\x17.soteria.v1.WindowStatsR\awindows\x12+\n" +
+	"\x11weighted_requests\x18\b \x01(\x01R\x10weightedRequests\x12-\n" +
+	"\x12weighted_successes\x18\t \x01(\x01R\x11weightedSuccesses\x12+\n" +
+	"\x11weighted_failures\x18\n" +
+	" \x01(\x01R\x10weightedFailures\x12\x1c\n" +
+	"\toverflows\x18\v \x01(\rR\toverflows\x12\x1e\n" +
+	"\n" +
+	"generation\x18\f \x01(\x04R\n" +
+	"generation\x1aE\n" +
+	"\x17FailuresByCategoryEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\rR\x05value:\x028\x01\"z\n" +
+	"\vWindowStats\x121\n" +
+	"\x06window\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\x06window\x12\x1c\n" +
+	"\tsuccesses\x18\x02 \x01(\rR\tsuccesses\x12\x1a\n" +
+	"\bfailures\x18\x03 \x01(\rR\bfailures\"\xd4\x04\n" +
+	"\x05Event\x12\x18\n" +
+	"\abreaker\x18\x01 \x01(\tR\abreaker\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12)\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x15.soteria.v1.EventKindR\x04kind\x12%\n" +
+	"\x04from\x18\x04 \x01(\x0e2\x11.soteria.v1.StateR\x04from\x12!\n" +
+	"\x02to\x18\x05 \x01(\x0e2\x11.soteria.v1.StateR\x02to\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\x12\x1a\n" +
+	"\bcategory\x18\a \x01(\tR\bcategory\x12\x1f\n" +
+	"\vfrom_custom\x18\b \x01(\tR\n" +
+	"fromCustom\x12\x1b\n" +
+	"\tto_custom\x18\t \x01(\tR\btoCustom\x125\n" +
+	"\x06labels\x18\n" +
+	" \x03(\v2\x1d.soteria.v1.Event.LabelsEntryR\x06labels\x123\n" +
+	"\alatency\x18\v \x01(\v2\x19.google.protobuf.DurationR\alatency\x12\x16\n" +
+	"\x06reason\x18\f \x01(\tR\x06reason\x12\x1e\n" +
+	"\n" +
+	"generation\x18\r \x01(\x04R\n" +
+	"generation\x12\x14\n" +
+	"\x05probe\x18\x0e \x01(\bR\x05probe\x12'\n" +
+	"\x05stats\x18\x0f \x01(\v2\x11.soteria.v1.StatsR\x05stats\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*{\n" +
+	"\x05State\x12\x15\n" +
+	"\x11STATE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fSTATE_CLOSED\x10\x01\x12\x13\n" +
+	"\x0fSTATE_HALF_OPEN\x10\x02\x12\x0e\n" +
+	"\n" +
+	"STATE_OPEN\x10\x03\x12\x10\n" +
+	"\fSTATE_CUSTOM\x10\x04\x12\x12\n" +
+	"\x0eSTATE_ISOLATED\x10\x05*\x9a\x02\n" +
+	"\tEventKind\x12\x1a\n" +
+	"\x16EVENT_KIND_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12EVENT_KIND_SUCCESS\x10\x01\x12\x16\n" +
+	"\x12EVENT_KIND_FAILURE\x10\x02\x12\x17\n" +
+	"\x13EVENT_KIND_REJECTED\x10\x03\x12\x16\n" +
+	"\x12EVENT_KIND_IGNORED\x10\x04\x12\x19\n" +
+	"\x15EVENT_KIND_TRANSITION\x10\x05\x12\x16\n" +
+	"\x12EVENT_KIND_ANOMALY\x10\x06\x12\x1a\n" +
+	"\x16EVENT_KIND_STATS_RESET\x10\a\x12 \n" +
+	"\x1cEVENT_KIND_GENERATION_ROLLED\x10\b\x12\x1f\n" +
+	"\x1bEVENT_KIND_PROBES_EXHAUSTED\x10\tB&Z$github.com/jtejido/soteria/soteriapbb\x06proto3"
+
+var (
+	file_soteriapb_soteria_proto_rawDescOnce sync.Once
+	file_soteriapb_soteria_proto_rawDescData []byte
+)
+
+func file_soteriapb_soteria_proto_rawDescGZIP() []byte {
+	file_soteriapb_soteria_proto_rawDescOnce.Do(func() {
+		file_soteriapb_soteria_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_soteriapb_soteria_proto_rawDesc), len(file_soteriapb_soteria_proto_rawDesc)))
+	})
+	return file_soteriapb_soteria_proto_rawDescData
+}
+
+var file_soteriapb_soteria_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_soteriapb_soteria_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_soteriapb_soteria_proto_goTypes = []any{
+	(State)(0),                    // 0: soteria.v1.State
+	(EventKind)(0),                // 1: soteria.v1.EventKind
+	(*Stats)(nil),                 // 2: soteria.v1.Stats
+	(*WindowStats)(nil),           // 3: soteria.v1.WindowStats
+	(*Event)(nil),                 // 4: soteria.v1.Event
+	nil,                           // 5: soteria.v1.Stats.FailuresByCategoryEntry
+	nil,                           // 6: soteria.v1.Event.LabelsEntry
+	(*durationpb.Duration)(nil),   // 7: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+}
+var file_soteriapb_soteria_proto_depIdxs = []int32{
+	5,  // 0: soteria.v1.Stats.failures_by_category:type_name -> soteria.v1.Stats.FailuresByCategoryEntry
+	3,  // 1: soteria.v1.Stats.windows:type_name -> soteria.v1.WindowStats
+	7,  // 2: soteria.v1.WindowStats.window:type_name -> google.protobuf.Duration
+	8,  // 3: soteria.v1.Event.time:type_name -> google.protobuf.Timestamp
+	1,  // 4: soteria.v1.Event.kind:type_name -> soteria.v1.EventKind
+	0,  // 5: soteria.v1.Event.from:type_name -> soteria.v1.State
+	0,  // 6: soteria.v1.Event.to:type_name -> soteria.v1.State
+	6,  // 7: soteria.v1.Event.labels:type_name -> soteria.v1.Event.LabelsEntry
+	7,  // 8: soteria.v1.Event.latency:type_name -> google.protobuf.Duration
+	2,  // 9: soteria.v1.Event.stats:type_name -> soteria.v1.Stats
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_soteriapb_soteria_proto_init() }
+func file_soteriapb_soteria_proto_init() {
+	if File_soteriapb_soteria_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_soteriapb_soteria_proto_rawDesc), len(file_soteriapb_soteria_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_soteriapb_soteria_proto_goTypes,
+		DependencyIndexes: file_soteriapb_soteria_proto_depIdxs,
+		EnumInfos:         file_soteriapb_soteria_proto_enumTypes,
+		MessageInfos:      file_soteriapb_soteria_proto_msgTypes,
+	}.Build()
+	File_soteriapb_soteria_proto = out.File
+	file_soteriapb_soteria_proto_goTypes = nil
+	file_soteriapb_soteria_proto_depIdxs = nil
+}
@@ -0,0 +1,70 @@
+// Package soteriatemporal wraps Temporal activities in soteria circuit
+// breakers, returning rejections as application errors so that workflow
+// retries follow the health of the downstream service:
+//
+//	w.RegisterActivity(soteriatemporal.Activity(cb, charge))
+package soteriatemporal
+
+import (
+	"context"
+	"errors"
+
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/jtejido/soteria"
+)
+
+// Types of the application errors returned for rejected activities.
+const (
+	// ErrorTypeOpen is retryable, after the time the CircuitBreaker stays open.
+	ErrorTypeOpen = "CircuitBreakerOpen"
+	// ErrorTypeIsolated is not retryable: the CircuitBreaker was isolated by
+	// an operator, and retries would only use up attempts.
+	ErrorTypeIsolated = "CircuitBreakerIsolated"
+	// ErrorTypeRejected is retryable, and covers the other rejections, such
+	// as ErrTooManyRequests while half-open.
+	ErrorTypeRejected = "CircuitBreakerRejected"
+)
+
+// Activity returns the activity fn run through cb. See ApplicationError for
+// the errors of rejected calls.
+func Activity[I, O any](cb *soteria.CircuitBreaker, fn func(ctx context.Context, in I) (O, error)) func(ctx context.Context, in I) (O, error) {
+	return func(ctx context.Context, in I) (O, error) {
+		var (
+			out O
+			ran bool
+		)
+		_, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+			var err error
+			ran = true
+			out, err = fn(ctx, in)
+			return nil, err
+		})
+		if !ran {
+			return out, ApplicationError(err)
+		}
+		return out, err
+	}
+}
+
+// ApplicationError converts a rejection of a CircuitBreaker into a
+// *temporal.ApplicationError of one of the ErrorTypes, with err as its
+// cause. An open CircuitBreaker sets the next retry delay to its remaining
+// open time. Other errors are returned unchanged.
+func ApplicationError(err error) error {
+	var open *soteria.OpenStateError
+	switch {
+	case errors.As(err, &open):
+		return temporal.NewApplicationErrorWithOptions(err.Error(), ErrorTypeOpen, temporal.ApplicationErrorOptions{
+			Cause:          err,
+			NextRetryDelay: open.Remaining,
+		})
+	case errors.Is(err, soteria.ErrIsolated):
+		return temporal.NewNonRetryableApplicationError(err.Error(), ErrorTypeIsolated, err)
+	case errors.Is(err, soteria.ErrOpenState),
+		errors.Is(err, soteria.ErrTooManyRequests),
+		errors.Is(err, soteria.ErrMaintenance):
+		return temporal.NewApplicationErrorWithCause(err.Error(), ErrorTypeRejected, err)
+	}
+	return err
+}
@@ -0,0 +1,66 @@
+package soteriatemporal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+
+	"github.com/jtejido/soteria"
+)
+
+var errDown = errors.New("down")
+
+func TestActivity(t *testing.T) {
+	cb := soteria.New(soteria.Settings{Timeout: time.Minute})
+	activity := Activity(cb, func(ctx context.Context, in int) (int, error) {
+		if in < 0 {
+			return 0, errDown
+		}
+		return in * 2, nil
+	})
+
+	if out, err := activity(context.Background(), 21); out != 42 || err != nil {
+		t.Errorf("activity(21) = %d, %v", out, err)
+	}
+	for i := 0; i < 6; i++ {
+		if _, err := activity(context.Background(), -1); err != errDown {
+			t.Fatalf("failing activity = %v, want its error unchanged", err)
+		}
+	}
+
+	_, err := activity(context.Background(), 1)
+	var ae *temporal.ApplicationError
+	if !errors.As(err, &ae) || ae.Type() != ErrorTypeOpen || ae.NonRetryable() {
+		t.Fatalf("activity while open = %v, want a retryable %s", err, ErrorTypeOpen)
+	}
+	if d := ae.NextRetryDelay(); d <= 0 || d > time.Minute {
+		t.Errorf("NextRetryDelay = %v, want the remaining open time", d)
+	}
+	if !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("%v does not wrap ErrOpenState", err)
+	}
+}
+
+func TestApplicationError(t *testing.T) {
+	for _, tc := range []struct {
+		err          error
+		errType      string
+		nonRetryable bool
+	}{
+		{soteria.ErrIsolated, ErrorTypeIsolated, true},
+		{soteria.ErrTooManyRequests, ErrorTypeRejected, false},
+		{soteria.ErrMaintenance, ErrorTypeRejected, false},
+	} {
+		var ae *temporal.ApplicationError
+		if err := ApplicationError(tc.err); !errors.As(err, &ae) || ae.Type() != tc.errType || ae.NonRetryable() != tc.nonRetryable {
+			t.Errorf("ApplicationError(%v) = %v, want type %s, non-retryable %v", tc.err, err, tc.errType, tc.nonRetryable)
+		}
+	}
+
+	if err := ApplicationError(errDown); err != errDown {
+		t.Errorf("ApplicationError(%v) = %v, want it unchanged", errDown, err)
+	}
+}
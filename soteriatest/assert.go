@@ -0,0 +1,67 @@
+package soteriatest
+
+import (
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+// StateReader is implemented by soteria.CircuitBreaker and FakeBreaker.
+type StateReader interface {
+	Name() string
+	State() soteria.State
+}
+
+// AssertState fails the test if b is not in state want.
+func AssertState(t testing.TB, b StateReader, want soteria.State) {
+	t.Helper()
+	if got := b.State(); got != want {
+		t.Errorf("breaker %q: state is %v, want %v", b.Name(), got, want)
+	}
+}
+
+// AssertClosed fails the test if b is not closed.
+func AssertClosed(t testing.TB, b StateReader) {
+	t.Helper()
+	AssertState(t, b, soteria.StateClosed)
+}
+
+// AssertHalfOpen fails the test if b is not half-open.
+func AssertHalfOpen(t testing.TB, b StateReader) {
+	t.Helper()
+	AssertState(t, b, soteria.StateHalfOpen)
+}
+
+// AssertOpen fails the test if b is not open.
+func AssertOpen(t testing.TB, b StateReader) {
+	t.Helper()
+	AssertState(t, b, soteria.StateOpen)
+}
+
+// AssertIsolated fails the test if b is not isolated.
+func AssertIsolated(t testing.TB, b StateReader) {
+	t.Helper()
+	AssertState(t, b, soteria.StateIsolated)
+}
+
+// Trip fails requests through cb until it opens, giving up after max attempts.
+func Trip(t testing.TB, cb *soteria.CircuitBreaker, max int) {
+	t.Helper()
+	for i := 0; i < max && cb.State() != soteria.StateOpen; i++ {
+		cb.Execute(func() (interface{}, error) {
+			return nil, errTrip
+		})
+	}
+	AssertOpen(t, cb)
+}
+
+// AdvanceToHalfOpen moves clock past the open timeout of cb, which must be
+// open and driven by clock, and asserts that cb became half-open.
+func AdvanceToHalfOpen(t testing.TB, clock *Clock, cb *soteria.CircuitBreaker) {
+	t.Helper()
+	if cb.State() != soteria.StateOpen {
+		t.Fatalf("breaker %q: AdvanceToHalfOpen called while not open", cb.Name())
+	}
+	clock.Advance(cb.Timeout())
+	AssertHalfOpen(t, cb)
+}
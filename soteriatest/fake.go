@@ -0,0 +1,106 @@
+package soteriatest
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+// FakeBreaker mimics the method set of soteria.CircuitBreaker with a state
+// and rejections that are entirely scripted by the test.
+//
+// An open FakeBreaker rejects with a *soteria.OpenStateError, an isolated
+// one with soteria.ErrIsolated; any other state lets requests through
+// unless a rejection was queued with RejectNext.
+type FakeBreaker struct {
+	mutex      sync.Mutex
+	name       string
+	state      soteria.State
+	remaining  time.Duration
+	rejections []error
+	calls      int
+}
+
+// NewFakeBreaker returns a closed FakeBreaker.
+func NewFakeBreaker(name string) *FakeBreaker {
+	return &FakeBreaker{name: name, state: soteria.StateClosed}
+}
+
+func (f *FakeBreaker) Name() string {
+	return f.name
+}
+
+func (f *FakeBreaker) State() soteria.State {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.state
+}
+
+// SetState forces the state reported and enforced by the FakeBreaker.
+func (f *FakeBreaker) SetState(state soteria.State) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.state = state
+}
+
+// SetRemainingOpenTime sets the time reported by RemainingOpenTime and
+// carried by the rejections of the FakeBreaker while it is open.
+func (f *FakeBreaker) SetRemainingOpenTime(d time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.remaining = d
+}
+
+// RemainingOpenTime returns the time set with SetRemainingOpenTime, or 0
+// if the FakeBreaker is not open.
+func (f *FakeBreaker) RemainingOpenTime() time.Duration {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.state != soteria.StateOpen {
+		return 0
+	}
+	return f.remaining
+}
+
+// RejectNext queues errs; each subsequent Execute consumes one of them
+// and returns it without running the request.
+func (f *FakeBreaker) RejectNext(errs ...error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.rejections = append(f.rejections, errs...)
+}
+
+// Calls returns the number of requests that were let through.
+func (f *FakeBreaker) Calls() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.calls
+}
+
+func (f *FakeBreaker) Execute(req func() (interface{}, error)) (interface{}, error) {
+	f.mutex.Lock()
+	if len(f.rejections) > 0 {
+		err := f.rejections[0]
+		f.rejections = f.rejections[1:]
+		f.mutex.Unlock()
+		return nil, err
+	}
+
+	switch f.state {
+	case soteria.StateOpen:
+		err := &soteria.OpenStateError{Remaining: f.remaining}
+		f.mutex.Unlock()
+		return nil, err
+	case soteria.StateIsolated:
+		f.mutex.Unlock()
+		return nil, soteria.ErrIsolated
+	}
+
+	f.calls++
+	f.mutex.Unlock()
+	return req()
+}
+
+var errTrip = errors.New("soteriatest: induced failure")
@@ -0,0 +1,55 @@
+package soteriatest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestFakeBreakerEnforcesState(t *testing.T) {
+	f := NewFakeBreaker("fake")
+
+	if _, err := f.Execute(func() (interface{}, error) { return 1, nil }); err != nil {
+		t.Fatal(err)
+	}
+
+	f.SetState(soteria.StateOpen)
+	if _, err := f.Execute(func() (interface{}, error) { return 1, nil }); !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("open fake = %v, want ErrOpenState", err)
+	}
+	AssertOpen(t, f)
+
+	if f.Calls() != 1 {
+		t.Errorf("Calls = %d, want 1", f.Calls())
+	}
+}
+
+func TestFakeBreakerRejectNext(t *testing.T) {
+	f := NewFakeBreaker("fake")
+	f.RejectNext(soteria.ErrTooManyRequests)
+
+	if _, err := f.Execute(func() (interface{}, error) { return nil, nil }); err != soteria.ErrTooManyRequests {
+		t.Errorf("first call = %v, want the queued rejection", err)
+	}
+	if _, err := f.Execute(func() (interface{}, error) { return nil, nil }); err != nil {
+		t.Errorf("second call = %v, want nil", err)
+	}
+}
+
+func TestFakeBreakerOpenStateError(t *testing.T) {
+	f := NewFakeBreaker("fake")
+	f.SetState(soteria.StateOpen)
+	f.SetRemainingOpenTime(time.Minute)
+
+	if got := f.RemainingOpenTime(); got != time.Minute {
+		t.Errorf("RemainingOpenTime = %v, want 1m", got)
+	}
+
+	_, err := f.Execute(func() (interface{}, error) { return nil, nil })
+	var oe *soteria.OpenStateError
+	if !errors.As(err, &oe) || oe.Remaining != time.Minute {
+		t.Errorf("Execute = %v, want an *OpenStateError with 1m remaining", err)
+	}
+}
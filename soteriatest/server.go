@@ -0,0 +1,105 @@
+package soteriatest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Response is a scripted response of a FlakyServer. A zero Status is
+// 200 OK. Latency delays the response, unless the request is canceled
+// first. Drop closes the connection without responding, as a crashing
+// server does.
+type Response struct {
+	Status  int
+	Latency time.Duration
+	Body    string
+	Drop    bool
+}
+
+// Repeat returns n copies of r, for scripting patterns such as
+// Repeat(5, Response{Status: 503}).
+func Repeat(n int, r Response) []Response {
+	rs := make([]Response, n)
+	for i := range rs {
+		rs[i] = r
+	}
+	return rs
+}
+
+// FlakyServer is an HTTP server answering with scripted responses, for
+// testing soteria.RoundTripper, soteria.Handler and other integrations end
+// to end. Once the script is used up it answers 200 OK.
+type FlakyServer struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	script   []Response
+	requests int
+}
+
+// NewFlakyServer starts a FlakyServer answering with script, in order.
+// The caller must Close it.
+func NewFlakyServer(script ...Response) *FlakyServer {
+	s := &FlakyServer{script: script}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Then appends responses to the script.
+func (s *FlakyServer) Then(responses ...Response) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.script = append(s.script, responses...)
+}
+
+// Requests returns the number of requests the FlakyServer received.
+func (s *FlakyServer) Requests() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+func (s *FlakyServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	s.requests++
+	var resp Response
+	if len(s.script) > 0 {
+		resp, s.script = s.script[0], s.script[1:]
+	}
+	s.mutex.Unlock()
+
+	if resp.Latency > 0 {
+		timer := time.NewTimer(resp.Latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if resp.Drop {
+		if conn, _, err := http.NewResponseController(w).Hijack(); err == nil {
+			conn.Close()
+			return
+		}
+	}
+
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+	w.WriteHeader(resp.Status)
+	w.Write([]byte(resp.Body))
+}
+
+// AssertRequests fails the test if s did not receive want requests, such
+// as when a CircuitBreaker should have stopped the traffic to it.
+func AssertRequests(t testing.TB, s *FlakyServer, want int) {
+	t.Helper()
+	if got := s.Requests(); got != want {
+		t.Errorf("server received %d requests, want %d", got, want)
+	}
+}
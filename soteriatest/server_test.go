@@ -0,0 +1,55 @@
+package soteriatest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestFlakyServerShieldedByBreaker(t *testing.T) {
+	s := NewFlakyServer(Repeat(6, Response{Status: http.StatusServiceUnavailable})...)
+	defer s.Close()
+
+	clock := NewClock(time.Unix(0, 0))
+	cb := soteria.New(soteria.Settings{Name: "flaky", Clock: clock})
+	client := &http.Client{Transport: &soteria.RoundTripper{Breaker: cb}}
+
+	for i := 0; i < 7; i++ {
+		resp, err := client.Get(s.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if i == 6 && !errors.Is(err, soteria.ErrOpenState) {
+			t.Errorf("seventh request = %v, want it rejected", err)
+		}
+	}
+	AssertOpen(t, cb)
+	AssertRequests(t, s, 6)
+
+	AdvanceToHalfOpen(t, clock, cb)
+	resp, err := client.Get(s.URL)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("probe = %v, %v, want 200 once the script is used up", resp, err)
+	}
+	resp.Body.Close()
+	AssertClosed(t, cb)
+}
+
+func TestFlakyServerDrop(t *testing.T) {
+	s := NewFlakyServer(Response{Drop: true})
+	defer s.Close()
+
+	if resp, err := http.Get(s.URL); err == nil {
+		resp.Body.Close()
+		t.Errorf("dropped request = %v, want an error", resp.Status)
+	}
+	s.Then(Response{Status: http.StatusTeapot, Body: "tea"})
+	resp, err := http.Get(s.URL)
+	if err != nil || resp.StatusCode != http.StatusTeapot {
+		t.Fatalf("Get = %v, %v", resp, err)
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,49 @@
+package soteriatest
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+// RandomWalk drives a breaker built from settings through steps random
+// successes, failures and clock advances derived from seed, failing the
+// test on the first invariant violation. Settings.Clock and
+// Settings.OnInvariantViolation are overridden.
+//
+// It is meant to be called with many seeds to property-test transitions.
+func RandomWalk(t testing.TB, settings soteria.Settings, seed int64, steps int) {
+	t.Helper()
+
+	rnd := rand.New(rand.NewSource(seed))
+	clock := NewClock(time.Unix(0, 0))
+	settings.Clock = clock
+	settings.OnInvariantViolation = func(err error) {
+		t.Helper()
+		t.Fatalf("seed %d: %v", seed, err)
+	}
+
+	cb := soteria.New(settings)
+	step := cb.Timeout() / 4
+	if settings.Interval != 0 && settings.Interval < cb.Timeout() {
+		step = settings.Interval / 4
+	}
+
+	for i := 0; i < steps; i++ {
+		switch n := rnd.Intn(10); {
+		case n < 2:
+			clock.Advance(time.Duration(rnd.Int63n(int64(step) + 1)))
+			cb.State()
+		case n < 5:
+			cb.Execute(func() (interface{}, error) {
+				return nil, errTrip
+			})
+		default:
+			cb.Execute(func() (interface{}, error) {
+				return nil, nil
+			})
+		}
+	}
+}
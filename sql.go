@@ -0,0 +1,67 @@
+package soteria
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+)
+
+// SQLClassifier categorizes the errors of database/sql and its drivers:
+// broken connections (driver.ErrBadConn, sql.ErrConnDone, SQLSTATE class
+// 08) and a server out of connections as CategoryConnection, timeouts and
+// cancelled statements as CategoryTimeout, network failures as
+// NetClassifier does and any other error as CategoryOther. sql.ErrNoRows
+// and constraint violations, such as a duplicate unique key, are answers of
+// a healthy database and are not categorized, so
+// SQLClassifier.IsSuccessful counts them as successes.
+//
+// Drivers are recognized by the SQLSTATE of errors with a SQLState method,
+// as those of pgx and lib/pq have, or else by the messages of MySQL,
+// PostgreSQL and SQLite.
+var SQLClassifier Classifier = SQLCategory
+
+// SQLCategory returns the Category of a failed database request, or "" if
+// err is nil or not a failure. See SQLClassifier.
+func SQLCategory(err error) Category {
+	switch {
+	case err == nil, errors.Is(err, sql.ErrNoRows):
+		return ""
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, sql.ErrConnDone):
+		return CategoryConnection
+	}
+
+	var se interface{ SQLState() string }
+	if errors.As(err, &se) {
+		switch state := se.SQLState(); {
+		case strings.HasPrefix(state, "23"):
+			return ""
+		case strings.HasPrefix(state, "08"), state == "53300":
+			return CategoryConnection
+		case state == "57014":
+			return CategoryTimeout
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case containsAny(msg, "duplicate key", "duplicate entry", "unique constraint", "violates foreign key", "constraint failed"):
+		return ""
+	case containsAny(msg, "too many connections", "too many clients"):
+		return CategoryConnection
+	}
+
+	if category := NetCategory(err); category != "" {
+		return category
+	}
+	return CategoryOther
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package soteria_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sql error " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestSQLCategory(t *testing.T) {
+	for _, c := range []struct {
+		err  error
+		want soteria.Category
+	}{
+		{nil, ""},
+		{sql.ErrNoRows, ""},
+		{fmt.Errorf("get user: %w", sql.ErrNoRows), ""},
+		{sqlStateError("23505"), ""},
+		{errors.New(`pq: duplicate key value violates unique constraint "users_pkey"`), ""},
+		{errors.New("Error 1062 (23000): Duplicate entry 'a' for key 'PRIMARY'"), ""},
+		{errors.New("UNIQUE constraint failed: users.email"), ""},
+		{driver.ErrBadConn, soteria.CategoryConnection},
+		{sql.ErrConnDone, soteria.CategoryConnection},
+		{sqlStateError("08006"), soteria.CategoryConnection},
+		{sqlStateError("53300"), soteria.CategoryConnection},
+		{errors.New("Error 1040: Too many connections"), soteria.CategoryConnection},
+		{errors.New("pq: sorry, too many clients already"), soteria.CategoryConnection},
+		{sqlStateError("57014"), soteria.CategoryTimeout},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), soteria.CategoryTimeout},
+		{errors.New("syntax error at or near \"SELEC\""), soteria.CategoryOther},
+	} {
+		if got := soteria.SQLCategory(c.err); got != c.want {
+			t.Errorf("SQLCategory(%v) = %q, want %q", c.err, got, c.want)
+		}
+	}
+}
+
+func TestSQLClassifierIsSuccessful(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{IsSuccessful: soteria.SQLClassifier.IsSuccessful, Classifier: soteria.SQLClassifier})
+
+	for i := 0; i < 10; i++ {
+		cb.Run(func() error { return sql.ErrNoRows })
+	}
+	cb.Run(func() error { return driver.ErrBadConn })
+
+	if s := cb.Stats(); s.TotalSuccesses != 10 || s.FailuresByCategory[soteria.CategoryConnection] != 1 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
@@ -0,0 +1,55 @@
+package soteria
+
+import (
+	"fmt"
+	"sync"
+)
+
+// State is the state of a CircuitBreaker.
+type State int
+
+var (
+	stateMutex sync.RWMutex
+	stateNames = map[State]string{
+		StateClosed:   "closed",
+		StateHalfOpen: "half-open",
+		StateOpen:     "open",
+		StateIsolated: "isolated",
+	}
+)
+
+func stateName(s State) (string, bool) {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+	name, ok := stateNames[s]
+	return name, ok
+}
+
+func (s State) String() string {
+	if name, ok := stateName(s); ok {
+		return name
+	}
+	return fmt.Sprintf("unknown state: %d", int(s))
+}
+
+// MarshalText encodes s as its name, so State reads as a string in JSON.
+func (s State) MarshalText() ([]byte, error) {
+	if _, ok := stateName(s); !ok {
+		return nil, fmt.Errorf("soteria: cannot marshal %v", s)
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a state name as produced by MarshalText.
+func (s *State) UnmarshalText(text []byte) error {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+
+	for state, name := range stateNames {
+		if name == string(text) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("soteria: unknown state %q", text)
+}
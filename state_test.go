@@ -0,0 +1,50 @@
+package soteria_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestStateString(t *testing.T) {
+	for state, want := range map[soteria.State]string{
+		soteria.StateClosed:   "closed",
+		soteria.StateHalfOpen: "half-open",
+		soteria.StateOpen:     "open",
+		soteria.State(100):    "unknown state: 100",
+	} {
+		if got := state.String(); got != want {
+			t.Errorf("%d.String() = %q, want %q", int(state), got, want)
+		}
+	}
+}
+
+func TestStateJSON(t *testing.T) {
+	b, err := json.Marshal(map[string]soteria.State{"state": soteria.StateHalfOpen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"state":"half-open"}` {
+		t.Errorf("Marshal = %s", b)
+	}
+
+	var got map[string]soteria.State
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["state"] != soteria.StateHalfOpen {
+		t.Errorf("Unmarshal = %v", got["state"])
+	}
+}
+
+func TestStateJSONRejectsUnknown(t *testing.T) {
+	if _, err := json.Marshal(soteria.State(100)); err == nil {
+		t.Error("marshaled an unknown state")
+	}
+
+	var s soteria.State
+	if err := json.Unmarshal([]byte(`"ajar"`), &s); err == nil {
+		t.Error("unmarshaled an unknown state name")
+	}
+}
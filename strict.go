@@ -0,0 +1,46 @@
+package soteria
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrMisuse is returned for requests the CircuitBreaker cannot run because
+// it is wired wrongly, such as a nil request.
+var ErrMisuse = errors.New("misuse of circuit breaker")
+
+var strict atomic.Bool
+
+// SetStrict enables or disables strict mode for the whole process. In
+// strict mode misuse panics rather than being tolerated, so that wiring
+// bugs surface at startup and in tests instead of in production:
+//
+//   - running a nil request, which otherwise returns ErrMisuse,
+//   - using a zero CircuitBreaker, not created by New, which otherwise
+//     runs requests unprotected, as a nil *CircuitBreaker does,
+//   - adding a CircuitBreaker to a Registry under the name of another one,
+//     which otherwise replaces it.
+func SetStrict(enabled bool) {
+	strict.Store(enabled)
+}
+
+// Strict reports whether strict mode is enabled, see SetStrict.
+func Strict() bool {
+	return strict.Load()
+}
+
+// misuse returns an error for the misuse described by format, or panics
+// with it in strict mode.
+func misuse(format string, args ...interface{}) error {
+	err := fmt.Errorf("%w: %s", ErrMisuse, fmt.Sprintf(format, args...))
+	if strict.Load() {
+		panic(err)
+	}
+	return err
+}
+
+// errNilRequest returns the error of running a nil request on cb.
+func (cb *CircuitBreaker) errNilRequest() error {
+	return misuse("nil request for breaker %q", cb.Name())
+}
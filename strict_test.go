@@ -0,0 +1,45 @@
+package soteria_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestMisuse(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	if _, err := cb.Execute(nil); !errors.Is(err, soteria.ErrMisuse) {
+		t.Errorf("Execute(nil) = %v, want ErrMisuse", err)
+	}
+	if err := cb.Run(nil); !errors.Is(err, soteria.ErrMisuse) {
+		t.Errorf("Run(nil) = %v, want ErrMisuse", err)
+	}
+	if s := cb.Stats(); s.Requests != 0 {
+		t.Errorf("Stats = %+v, want nil requests not admitted", s)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	soteria.SetStrict(true)
+	defer soteria.SetStrict(false)
+
+	mustPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if err, _ := recover().(error); !errors.Is(err, soteria.ErrMisuse) {
+				t.Errorf("%s did not panic with ErrMisuse", name)
+			}
+		}()
+		f()
+	}
+
+	cb, _ := newBreaker(t, soteria.Settings{Name: "db"})
+	mustPanic("Execute(nil)", func() { cb.Execute(nil) })
+	mustPanic("Execute on a zero CircuitBreaker", func() { succeed(&soteria.CircuitBreaker{}) })
+
+	r := soteria.NewRegistry()
+	r.Add(cb)
+	r.Add(cb)
+	mustPanic("Add of a duplicate name", func() { r.Add(soteria.New(soteria.Settings{Name: "db"})) })
+}
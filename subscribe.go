@@ -0,0 +1,84 @@
+package soteria
+
+import "sync/atomic"
+
+// Subscription delivers the TraceEvents of a CircuitBreaker on C.
+// Events are dropped rather than blocking the CircuitBreaker when C is full.
+type Subscription struct {
+	C <-chan TraceEvent
+
+	c       chan TraceEvent
+	cb      *CircuitBreaker
+	dropped uint64
+
+	// only deliver TraceTransition events
+	transitions bool
+}
+
+// Subscribe returns a Subscription to the events of the CircuitBreaker,
+// buffering up to buffer events.
+func (cb *CircuitBreaker) Subscribe(buffer int) *Subscription {
+	return cb.subscribe(buffer, false)
+}
+
+// SubscribeTransitions is like Subscribe, but only delivers the
+// TraceTransition events, so that state changes are not dropped because
+// of a burst of requests.
+func (cb *CircuitBreaker) SubscribeTransitions(buffer int) *Subscription {
+	return cb.subscribe(buffer, true)
+}
+
+func (cb *CircuitBreaker) subscribe(buffer int, transitions bool) *Subscription {
+	c := make(chan TraceEvent, buffer)
+	s := &Subscription{C: c, c: c, cb: cb, transitions: transitions}
+	if cb.unprotected() {
+		close(c)
+		return s
+	}
+
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.closed {
+		close(c)
+		return s
+	}
+	if cb.subscribers == nil {
+		cb.subscribers = make(map[*Subscription]struct{})
+	}
+	cb.subscribers[s] = struct{}{}
+	return s
+}
+
+// Close ends the Subscription and closes C. It is safe to call more than once.
+func (s *Subscription) Close() {
+	if s.cb.unprotected() {
+		return
+	}
+	s.cb.mutex.Lock()
+	defer s.cb.mutex.Unlock()
+
+	if _, ok := s.cb.subscribers[s]; ok {
+		delete(s.cb.subscribers, s)
+		close(s.c)
+	}
+}
+
+// Dropped returns the number of events dropped because C was full.
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// publish delivers e to the subscribers. cb.mutex must be held.
+func (cb *CircuitBreaker) publish(e TraceEvent) {
+	for s := range cb.subscribers {
+		if s.transitions && e.Kind != TraceTransition {
+			continue
+		}
+		select {
+		case s.c <- e:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+}
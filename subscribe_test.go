@@ -0,0 +1,67 @@
+package soteria_test
+
+import (
+	"testing"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestSubscribe(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	s := cb.Subscribe(10)
+	defer s.Close()
+
+	succeed(cb)
+	trip(cb)
+
+	var kinds []string
+	for len(s.C) > 0 {
+		kinds = append(kinds, (<-s.C).Kind)
+	}
+	if len(kinds) != 9 || kinds[0] != soteria.TraceSuccess || kinds[7] != soteria.TraceTransition || kinds[8] != soteria.TraceGenerationRolled {
+		t.Errorf("events = %v, want a success, six failures, a transition and a new generation", kinds)
+	}
+}
+
+func TestSubscriptionDrops(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	s := cb.Subscribe(1)
+	defer s.Close()
+
+	succeed(cb)
+	succeed(cb)
+	succeed(cb)
+
+	if got := s.Dropped(); got != 2 {
+		t.Errorf("Dropped = %d, want 2", got)
+	}
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	s := cb.Subscribe(1)
+
+	s.Close()
+	s.Close()
+	succeed(cb)
+
+	if _, ok := <-s.C; ok {
+		t.Error("received an event after Close")
+	}
+}
+
+func TestSubscribeTransitions(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	s := cb.SubscribeTransitions(1)
+	defer s.Close()
+
+	succeed(cb)
+	trip(cb)
+
+	if e := <-s.C; e.Kind != soteria.TraceTransition || e.To != soteria.StateOpen {
+		t.Errorf("event = %+v, want the transition to open", e)
+	}
+	if s.Dropped() != 0 {
+		t.Errorf("Dropped = %d, want 0", s.Dropped())
+	}
+}
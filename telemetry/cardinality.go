@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"sort"
+
+	"github.com/jtejido/soteria"
+)
+
+// DefaultOtherName names the entry Limit folds breakers into.
+const DefaultOtherName = "other"
+
+// Limit caps the breakers of s to the k with the most requests, folding
+// the stats of the rest into a single entry named other, so that per-key
+// breakers (per URL, per tenant) do not turn into unbounded metric label
+// values. The folded entry has no labels and the most severe state of the
+// breakers it stands for: isolated, open, half-open, then closed.
+//
+// Breakers are compared by Stats.Requests, then by name. s is returned
+// unchanged if it has no more than k breakers or k is 0 or less.
+func Limit(s Snapshot, k int, other string) Snapshot {
+	if k <= 0 || len(s.Breakers) <= k {
+		return s
+	}
+
+	ranked := append([]soteria.BreakerStatus(nil), s.Breakers...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		ri, rj := requests(ranked[i]), requests(ranked[j])
+		if ri != rj {
+			return ri > rj
+		}
+		return ranked[i].Name < ranked[j].Name
+	})
+
+	folded := soteria.BreakerStatus{Name: other, State: soteria.StateClosed, Stats: &soteria.Stats{}}
+	for _, b := range ranked[k:] {
+		if severity(b.State) > severity(folded.State) {
+			folded.State = b.State
+		}
+		if b.Trips != nil {
+			if folded.Trips == nil {
+				folded.Trips = &soteria.TripRate{}
+			}
+			folded.Trips.Total += b.Trips.Total
+			folded.Trips.LastHour += b.Trips.LastHour
+			if b.Trips.LastTrip != nil && (folded.Trips.LastTrip == nil || b.Trips.LastTrip.After(*folded.Trips.LastTrip)) {
+				folded.Trips.LastTrip = b.Trips.LastTrip
+				folded.Trips.SinceLastTrip = b.Trips.SinceLastTrip
+			}
+		}
+		if b.Anomalies != nil {
+			if folded.Anomalies == nil {
+				folded.Anomalies = &soteria.Anomalies{}
+			}
+			folded.Anomalies.MachineErrors += b.Anomalies.MachineErrors
+			folded.Anomalies.InvariantViolations += b.Anomalies.InvariantViolations
+			folded.Anomalies.Panics += b.Anomalies.Panics
+		}
+		if b.Stats == nil {
+			continue
+		}
+
+		folded.Stats.Requests += b.Stats.Requests
+		folded.Stats.TotalSuccesses += b.Stats.TotalSuccesses
+		folded.Stats.TotalFailures += b.Stats.TotalFailures
+		folded.Stats.WeightedRequests += b.Stats.WeightedRequests
+		folded.Stats.WeightedSuccesses += b.Stats.WeightedSuccesses
+		folded.Stats.WeightedFailures += b.Stats.WeightedFailures
+		folded.Stats.Overflows += b.Stats.Overflows
+		for category, n := range b.Stats.FailuresByCategory {
+			if folded.Stats.FailuresByCategory == nil {
+				folded.Stats.FailuresByCategory = make(map[soteria.Category]uint32)
+			}
+			folded.Stats.FailuresByCategory[category] += n
+		}
+	}
+
+	s.Breakers = append(ranked[:k:k], folded)
+	return s
+}
+
+func requests(b soteria.BreakerStatus) uint32 {
+	if b.Stats == nil {
+		return 0
+	}
+	return b.Stats.Requests
+}
+
+func severity(s soteria.State) int {
+	switch s {
+	case soteria.StateIsolated:
+		return 3
+	case soteria.StateOpen:
+		return 2
+	case soteria.StateHalfOpen:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,97 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func status(name string, state soteria.State, requests, failures uint32) soteria.BreakerStatus {
+	return soteria.BreakerStatus{
+		Name:   name,
+		State:  state,
+		Labels: map[string]string{"name": name},
+		Stats: &soteria.Stats{
+			Requests:           requests,
+			TotalFailures:      failures,
+			FailuresByCategory: map[soteria.Category]uint32{soteria.CategoryOther: failures},
+		},
+	}
+}
+
+func TestLimitKeepsBusiest(t *testing.T) {
+	s := Snapshot{Breakers: []soteria.BreakerStatus{
+		status("a", soteria.StateClosed, 1, 0),
+		status("b", soteria.StateClosed, 30, 0),
+		status("c", soteria.StateOpen, 2, 2),
+		status("d", soteria.StateClosed, 20, 0),
+		status("e", soteria.StateHalfOpen, 3, 1),
+	}}
+
+	got := Limit(s, 2, DefaultOtherName).Breakers
+	if len(got) != 3 || got[0].Name != "b" || got[1].Name != "d" {
+		t.Fatalf("breakers = %+v", got)
+	}
+
+	other := got[2]
+	if other.Name != DefaultOtherName || other.Labels != nil {
+		t.Errorf("folded entry = %+v", other)
+	}
+	if other.State != soteria.StateOpen {
+		t.Errorf("folded State = %v, want the most severe, open", other.State)
+	}
+	if other.Stats.Requests != 6 || other.Stats.TotalFailures != 3 || other.Stats.FailuresByCategory[soteria.CategoryOther] != 3 {
+		t.Errorf("folded Stats = %+v", other.Stats)
+	}
+}
+
+func TestLimitTiesByName(t *testing.T) {
+	s := Snapshot{Breakers: []soteria.BreakerStatus{
+		status("z", soteria.StateClosed, 5, 0),
+		status("y", soteria.StateClosed, 5, 0),
+		status("x", soteria.StateClosed, 5, 0),
+	}}
+
+	got := Limit(s, 1, "rest").Breakers
+	if got[0].Name != "x" || got[1].Name != "rest" {
+		t.Errorf("breakers = %v, %v", got[0].Name, got[1].Name)
+	}
+}
+
+func TestLimitIsolatedIsMostSevere(t *testing.T) {
+	s := Snapshot{Breakers: []soteria.BreakerStatus{
+		status("a", soteria.StateClosed, 10, 0),
+		status("b", soteria.StateOpen, 0, 0),
+		status("c", soteria.StateIsolated, 0, 0),
+	}}
+
+	if got := Limit(s, 1, "rest").Breakers[1].State; got != soteria.StateIsolated {
+		t.Errorf("folded State = %v, want isolated", got)
+	}
+}
+
+func TestLimitUnchanged(t *testing.T) {
+	s := Snapshot{Breakers: []soteria.BreakerStatus{status("a", soteria.StateClosed, 1, 0)}}
+
+	for _, k := range []int{0, -1, 1, 2} {
+		if got := Limit(s, k, "rest").Breakers; len(got) != 1 || got[0].Name != "a" {
+			t.Errorf("Limit(k=%d) = %+v", k, got)
+		}
+	}
+}
+
+func TestExporterMaxBreakers(t *testing.T) {
+	var got Snapshot
+	r := newRegistry("a", "b", "c")
+	e := NewExporter(r, SinkFunc(func(_ context.Context, s Snapshot) error {
+		got = s
+		return nil
+	}), Options{MaxBreakers: 1, Interval: time.Second})
+
+	e.Flush(context.Background())
+	if len(got.Breakers) != 2 || got.Breakers[1].Name != DefaultOtherName {
+		t.Errorf("exported %+v", got.Breakers)
+	}
+}
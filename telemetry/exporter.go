@@ -0,0 +1,162 @@
+// Package telemetry periodically exports snapshots of the breakers of a
+// soteria.Registry to pluggable sinks.
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+const defaultInterval = time.Duration(10) * time.Second
+
+// Snapshot is the state of every breaker of a Registry at a point in time.
+type Snapshot struct {
+	Time     time.Time               `json:"time"`
+	Source   string                  `json:"source,omitempty"`
+	Breakers []soteria.BreakerStatus `json:"breakers"`
+}
+
+// Take returns a Snapshot of registry, including stats.
+func Take(registry *soteria.Registry, source string) Snapshot {
+	breakers := registry.Breakers()
+	s := Snapshot{
+		Time:     time.Now(),
+		Source:   source,
+		Breakers: make([]soteria.BreakerStatus, 0, len(breakers)),
+	}
+	for _, cb := range breakers {
+		s.Breakers = append(s.Breakers, soteria.Status(cb, true))
+	}
+	return s
+}
+
+// Sink receives exported snapshots.
+type Sink interface {
+	Export(ctx context.Context, s Snapshot) error
+}
+
+// SinkFunc adapts a function to a Sink.
+type SinkFunc func(ctx context.Context, s Snapshot) error
+
+func (f SinkFunc) Export(ctx context.Context, s Snapshot) error {
+	return f(ctx, s)
+}
+
+// Options configures an Exporter:
+//
+// Interval is the period between exports. If Interval is 0, snapshots are
+// exported every 10 seconds.
+//
+// Source identifies the process in snapshots, such as a host or service name.
+//
+// Timeout bounds every export. If Timeout is 0, it is set to Interval.
+//
+// MaxBreakers, if greater than 0, caps the breakers of every snapshot to the
+// MaxBreakers busiest ones plus an entry named OtherName aggregating the rest.
+// See Limit. If OtherName is empty, DefaultOtherName is used.
+//
+// OnError is called with every error returned by the sink. If OnError is
+// nil, errors are dropped.
+type Options struct {
+	Interval time.Duration
+	Source   string
+	Timeout  time.Duration
+
+	MaxBreakers int
+	OtherName   string
+
+	OnError func(err error)
+}
+
+// Exporter exports snapshots of a Registry to a Sink, periodically once
+// started and on demand with Flush.
+type Exporter struct {
+	registry *soteria.Registry
+	sink     Sink
+	options  Options
+
+	mutex  sync.Mutex
+	stop   func()
+	remove func() // the hook of Registry.OnShutdown
+
+	// periodic exports run off the Scheduler, one at a time
+	exporting sync.WaitGroup
+	busy      atomic.Bool
+}
+
+func NewExporter(registry *soteria.Registry, sink Sink, options Options) *Exporter {
+	if options.Interval == 0 {
+		options.Interval = defaultInterval
+	}
+	if options.Timeout == 0 {
+		options.Timeout = options.Interval
+	}
+	if options.OtherName == "" {
+		options.OtherName = DefaultOtherName
+	}
+
+	return &Exporter{registry: registry, sink: sink, options: options}
+}
+
+// Start begins exporting periodically, timed by the Scheduler of the
+// Registry, until Stop or Registry.Shutdown, which exports a last snapshot.
+// Exports run on a goroutine of their own, so that a slow sink does not
+// hold up the other tasks of the Scheduler; a period is skipped while the
+// export of the last one still runs. It is a no-op if already started.
+func (e *Exporter) Start() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.stop != nil {
+		return
+	}
+	e.stop = e.registry.Scheduler().Every(e.options.Interval, e.tick)
+	e.remove = e.registry.OnShutdown(func(ctx context.Context) error {
+		e.Stop()
+		return e.Flush(ctx)
+	})
+}
+
+// Stop ends periodic exporting and waits for an export in progress.
+func (e *Exporter) Stop() {
+	e.mutex.Lock()
+	stop, remove := e.stop, e.remove
+	e.stop, e.remove = nil, nil
+	e.mutex.Unlock()
+
+	if stop != nil {
+		stop()
+		remove()
+		e.exporting.Wait()
+	}
+}
+
+func (e *Exporter) tick() {
+	if !e.busy.CompareAndSwap(false, true) {
+		return
+	}
+
+	e.exporting.Add(1)
+	go func() {
+		defer e.exporting.Done()
+		defer e.busy.Store(false)
+		e.Flush(context.Background())
+	}()
+}
+
+// Flush exports a snapshot now.
+func (e *Exporter) Flush(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, e.options.Timeout)
+	defer cancel()
+
+	snapshot := Limit(Take(e.registry, e.options.Source), e.options.MaxBreakers, e.options.OtherName)
+	err := e.sink.Export(ctx, snapshot)
+	if err != nil && e.options.OnError != nil {
+		e.options.OnError(err)
+	}
+	return err
+}
@@ -0,0 +1,137 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func newRegistry(names ...string) *soteria.Registry {
+	r := soteria.NewRegistry()
+	for _, name := range names {
+		r.GetOrCreate(name, soteria.Settings{})
+	}
+	return r
+}
+
+func TestTake(t *testing.T) {
+	r := newRegistry("db", "api")
+	cb, _ := r.Get("db")
+	cb.Execute(func() (interface{}, error) { return nil, nil })
+
+	s := Take(r, "host-1")
+	if s.Source != "host-1" || len(s.Breakers) != 2 {
+		t.Fatalf("Snapshot = %+v", s)
+	}
+	if db := s.Breakers[1]; db.Name != "db" || db.Stats == nil || db.Stats.TotalSuccesses != 1 {
+		t.Errorf("db = %+v", db)
+	}
+}
+
+func TestExporterFlush(t *testing.T) {
+	var got []Snapshot
+	sink := SinkFunc(func(ctx context.Context, s Snapshot) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("export without a deadline")
+		}
+		got = append(got, s)
+		return nil
+	})
+
+	e := NewExporter(newRegistry("db"), sink, Options{Source: "host-1"})
+	if err := e.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Source != "host-1" || len(got[0].Breakers) != 1 {
+		t.Errorf("exported %+v", got)
+	}
+}
+
+func TestExporterOnError(t *testing.T) {
+	errSink := errors.New("sink down")
+	var reported error
+
+	e := NewExporter(newRegistry("db"), SinkFunc(func(context.Context, Snapshot) error {
+		return errSink
+	}), Options{OnError: func(err error) { reported = err }})
+
+	if err := e.Flush(context.Background()); err != errSink || reported != errSink {
+		t.Errorf("Flush = %v, reported %v", err, reported)
+	}
+}
+
+func TestExporterStartStop(t *testing.T) {
+	exported := make(chan Snapshot, 1)
+	e := NewExporter(newRegistry("db"), SinkFunc(func(ctx context.Context, s Snapshot) error {
+		select {
+		case exported <- s:
+		default:
+		}
+		return nil
+	}), Options{Interval: time.Millisecond})
+
+	e.Start()
+	e.Start()
+	select {
+	case <-exported:
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing exported")
+	}
+
+	e.Stop()
+	e.Stop()
+}
+
+func TestExporterShutdown(t *testing.T) {
+	registry := newRegistry("db")
+	var exports int
+	e := NewExporter(registry, SinkFunc(func(ctx context.Context, s Snapshot) error {
+		exports++
+		return nil
+	}), Options{Interval: time.Hour})
+
+	e.Start()
+	if err := registry.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if exports != 1 {
+		t.Errorf("%d exports on Shutdown, want a last one", exports)
+	}
+
+	e.Start()
+	e.Stop()
+	if err := registry.Shutdown(context.Background()); err != nil || exports != 1 {
+		t.Errorf("Shutdown after Stop = %v after %d exports, want no export", err, exports)
+	}
+}
+
+func TestExporterDoesNotHoldUpScheduler(t *testing.T) {
+	registry := newRegistry("db")
+	release := make(chan struct{})
+	var exports atomic.Int32
+	e := NewExporter(registry, SinkFunc(func(ctx context.Context, s Snapshot) error {
+		exports.Add(1)
+		<-release
+		return nil
+	}), Options{Interval: time.Millisecond, Timeout: time.Hour})
+
+	var fast atomic.Int32
+	stopFast := registry.Scheduler().Every(time.Millisecond, func() { fast.Add(1) })
+	defer stopFast()
+
+	e.Start()
+	deadline := time.Now().Add(5 * time.Second)
+	for (exports.Load() == 0 || fast.Load() < 10) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if fast.Load() < 10 || exports.Load() != 1 {
+		t.Errorf("fast task ran %d times with %d exports blocked, want it running next to one export", fast.Load(), exports.Load())
+	}
+
+	close(release)
+	e.Stop()
+}
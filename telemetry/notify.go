@@ -0,0 +1,90 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/jtejido/soteria"
+)
+
+// Notifier POSTs the state changes of CircuitBreakers to a webhook, as
+// JSON encoded TraceEvents, or as Slack-compatible messages if Slack is
+// true.
+type Notifier struct {
+	URL   string
+	Slack bool
+	// Client sends the requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// Header is added to every request, for authentication for instance.
+	Header http.Header
+	// OnError, if set, is called with every notification that failed.
+	OnError func(err error)
+}
+
+// Notify posts e.
+func (n *Notifier) Notify(ctx context.Context, e soteria.TraceEvent) error {
+	var v interface{} = e
+	if n.Slack {
+		v = slackMessage{Text: slackText(e)}
+	}
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return post(ctx, n.Client, n.URL, n.Header, body)
+}
+
+// Watch notifies the state changes of cb, in order, until the returned
+// function is called.
+func (n *Notifier) Watch(cb *soteria.CircuitBreaker) (stop func()) {
+	s := cb.SubscribeTransitions(64)
+
+	go func() {
+		for e := range s.C {
+			if err := n.Notify(context.Background(), e); err != nil && n.OnError != nil {
+				n.OnError(err)
+			}
+		}
+	}()
+
+	return s.Close
+}
+
+// WatchRegistry watches every CircuitBreaker registered with registry when
+// it is called.
+func (n *Notifier) WatchRegistry(registry *soteria.Registry) (stop func()) {
+	var stops []func()
+	for _, cb := range registry.Breakers() {
+		stops = append(stops, n.Watch(cb))
+	}
+
+	return func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}
+}
+
+// slackMessage is the payload of a Slack incoming webhook.
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+func slackText(e soteria.TraceEvent) string {
+	text := fmt.Sprintf("Circuit breaker *%s* went from %s to *%s*", e.Breaker, e.From, e.To)
+
+	if len(e.Labels) > 0 {
+		labels := make([]string, 0, len(e.Labels))
+		for k, v := range e.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+		text += " (" + strings.Join(labels, ", ") + ")"
+	}
+	return text
+}
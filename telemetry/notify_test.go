@@ -0,0 +1,95 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestNotifierPostsEvents(t *testing.T) {
+	events := make(chan soteria.TraceEvent, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e soteria.TraceEvent
+		json.NewDecoder(r.Body).Decode(&e)
+		events <- e
+	}))
+	defer srv.Close()
+
+	registry := newRegistry("api")
+	cb, _ := registry.Get("api")
+
+	n := &Notifier{URL: srv.URL, OnError: func(err error) { t.Error(err) }}
+	stop := n.WatchRegistry(registry)
+	defer stop()
+
+	for i := 0; i < 10; i++ {
+		cb.Execute(func() (interface{}, error) { return nil, nil })
+	}
+	cb.ForceOpen()
+
+	select {
+	case e := <-events:
+		if e.Kind != soteria.TraceTransition || e.Breaker != "api" || e.To != soteria.StateIsolated {
+			t.Errorf("received %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification")
+	}
+
+	select {
+	case e := <-events:
+		t.Errorf("received %+v, want state changes only", e)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestNotifierSlack(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	n := &Notifier{URL: srv.URL, Slack: true}
+	e := soteria.TraceEvent{
+		Breaker: "db",
+		Kind:    soteria.TraceTransition,
+		From:    soteria.StateClosed,
+		To:      soteria.StateOpen,
+		Labels:  map[string]string{"team": "core", "region": "eu"},
+	}
+	if err := n.Notify(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "Circuit breaker *db* went from closed to *open* (region=eu, team=core)"
+	if len(got) != 1 || got["text"] != want {
+		t.Errorf("payload = %v, want text %q", got, want)
+	}
+}
+
+func TestNotifierError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	errs := make(chan error, 1)
+	registry := newRegistry("api")
+	cb, _ := registry.Get("api")
+
+	n := &Notifier{URL: srv.URL, OnError: func(err error) { errs <- err }}
+	defer n.Watch(cb)()
+
+	cb.ForceOpen()
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnError not called on a 403")
+	}
+}
@@ -0,0 +1,119 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+// HTTPSink POSTs every snapshot as JSON to URL.
+type HTTPSink struct {
+	URL string
+	// Client sends the requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+	// Header is added to every request, for authentication for instance.
+	Header http.Header
+}
+
+func (s *HTTPSink) Export(ctx context.Context, snapshot Snapshot) error {
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return post(ctx, s.Client, s.URL, s.Header, body)
+}
+
+// post POSTs body as JSON to url with client, or http.DefaultClient.
+func post(ctx context.Context, client *http.Client, url string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("telemetry: %s responded %s", url, resp.Status)
+	}
+	return nil
+}
+
+// WriterSink writes every snapshot as a line of JSON to an io.Writer,
+// such as an append-only file.
+type WriterSink struct {
+	mutex sync.Mutex
+	w     io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Export(ctx context.Context, snapshot Snapshot) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return json.NewEncoder(s.w).Encode(snapshot)
+}
+
+// ProducerSink hands every breaker of a snapshot, as JSON keyed by the
+// breaker name, to Produce. It plugs into message brokers such as Kafka
+// through the producer of any client library.
+type ProducerSink struct {
+	Produce func(ctx context.Context, key, value []byte) error
+}
+
+func (s *ProducerSink) Export(ctx context.Context, snapshot Snapshot) error {
+	for _, b := range snapshot.Breakers {
+		value, err := json.Marshal(message{Time: snapshot.Time, Source: snapshot.Source, BreakerStatus: b})
+		if err != nil {
+			return err
+		}
+		if err := s.Produce(ctx, []byte(b.Name), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// message is the value produced by ProducerSink.
+type message struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source,omitempty"`
+	soteria.BreakerStatus
+}
+
+// MultiSink exports to each of its sinks in turn, returning the errors
+// of all that failed.
+type MultiSink []Sink
+
+func (m MultiSink) Export(ctx context.Context, snapshot Snapshot) error {
+	var errs []error
+	for _, s := range m {
+		if err := s.Export(ctx, snapshot); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,108 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+var snapshot = Snapshot{
+	Time:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	Source: "host-1",
+	Breakers: []soteria.BreakerStatus{
+		{Name: "api", State: soteria.StateClosed},
+		{Name: "db", State: soteria.StateOpen},
+	},
+}
+
+func TestHTTPSink(t *testing.T) {
+	var got Snapshot
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("%s request with Authorization %q", r.Method, r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	s := &HTTPSink{URL: srv.URL, Header: http.Header{"Authorization": {"Bearer token"}}}
+	if err := s.Export(context.Background(), snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if got.Source != "host-1" || len(got.Breakers) != 2 {
+		t.Errorf("received %+v", got)
+	}
+}
+
+func TestHTTPSinkStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	if err := (&HTTPSink{URL: srv.URL}).Export(context.Background(), snapshot); err == nil {
+		t.Error("Export succeeded on a 503")
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewWriterSink(&buf)
+	s.Export(context.Background(), snapshot)
+	s.Export(context.Background(), snapshot)
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 2 {
+		t.Errorf("wrote %d lines, want 2", lines)
+	}
+}
+
+func TestProducerSink(t *testing.T) {
+	var keys []string
+	s := &ProducerSink{Produce: func(ctx context.Context, key, value []byte) error {
+		keys = append(keys, string(key))
+
+		var m struct {
+			Source string        `json:"source"`
+			Name   string        `json:"name"`
+			State  soteria.State `json:"state"`
+		}
+		if err := json.Unmarshal(value, &m); err != nil {
+			t.Fatal(err)
+		}
+		if m.Source != "host-1" || m.Name != string(key) {
+			t.Errorf("message %s for key %s", value, key)
+		}
+		return nil
+	}}
+
+	if err := s.Export(context.Background(), snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(keys, ",") != "api,db" {
+		t.Errorf("keys = %v", keys)
+	}
+}
+
+func TestMultiSink(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	var called int
+	sink := func(err error) Sink {
+		return SinkFunc(func(context.Context, Snapshot) error {
+			called++
+			return err
+		})
+	}
+
+	err := MultiSink{sink(errA), sink(nil), sink(errB)}.Export(context.Background(), snapshot)
+	if called != 3 || !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("Export = %v after %d sinks", err, called)
+	}
+}
@@ -0,0 +1,248 @@
+package soteria
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ThresholdProfile is a failure-rate threshold in effect while its
+// conditions hold, such as a stricter one during peak hours.
+type ThresholdProfile struct {
+	Name string
+
+	// Schedule, if not nil, restricts the profile to the times it contains.
+	Schedule Schedule
+	// MinRate, if greater than 0, restricts the profile to when the
+	// CircuitBreaker sees at least MinRate requests per second over the
+	// rate window of the Thresholds.
+	MinRate float64
+
+	// FailureRatio is the share of failed requests, between 0 and 1, at
+	// which the CircuitBreaker trips.
+	FailureRatio float64
+	// MinimumRequests is the number of outcomes the ratio must be based on
+	// before the CircuitBreaker trips.
+	MinimumRequests uint32
+}
+
+// Thresholds picks the ThresholdProfile in effect for each decision to
+// trip. Its ReadyToTrip method is meant to be used as Settings.ReadyToTrip;
+// the profiles can be replaced at any time with Store or Reload, without
+// touching the CircuitBreakers using it.
+//
+// Request rates are measured over rateWindow, which must be one of the
+// Settings.Windows of those CircuitBreakers.
+type Thresholds struct {
+	rateWindow time.Duration
+	clock      Clock
+	profiles   atomic.Value // []ThresholdProfile
+}
+
+// NewThresholds returns Thresholds choosing among profiles, in order.
+// If clock is nil, the system clock is used.
+func NewThresholds(rateWindow time.Duration, clock Clock, profiles ...ThresholdProfile) *Thresholds {
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	t := &Thresholds{rateWindow: rateWindow, clock: clock}
+	t.Store(profiles)
+	return t
+}
+
+// Store replaces the profiles.
+func (t *Thresholds) Store(profiles []ThresholdProfile) {
+	t.profiles.Store(append([]ThresholdProfile(nil), profiles...))
+}
+
+// Profiles returns a copy of the profiles.
+func (t *Thresholds) Profiles() []ThresholdProfile {
+	return append([]ThresholdProfile(nil), t.profiles.Load().([]ThresholdProfile)...)
+}
+
+// Active returns the first profile whose conditions hold for stats now.
+func (t *Thresholds) Active(stats Stats) (ThresholdProfile, bool) {
+	now := t.clock.Now()
+
+	var rate float64
+	if w, ok := stats.Window(t.rateWindow); ok && t.rateWindow > 0 {
+		rate = float64(w.Requests()) / t.rateWindow.Seconds()
+	}
+
+	for _, p := range t.profiles.Load().([]ThresholdProfile) {
+		if p.Schedule != nil && !p.Schedule.Contains(now) {
+			continue
+		}
+		if p.MinRate > 0 && rate < p.MinRate {
+			continue
+		}
+		return p, true
+	}
+	return ThresholdProfile{}, false
+}
+
+// ReadyToTrip trips once the failure ratio of stats reaches the one of the
+// active profile. It never trips if no profile is active.
+func (t *Thresholds) ReadyToTrip(stats Stats) bool {
+	p, ok := t.Active(stats)
+	if !ok {
+		return false
+	}
+
+	n := stats.TotalSuccesses + stats.TotalFailures
+	if n == 0 || n < p.MinimumRequests {
+		return false
+	}
+	return float64(stats.TotalFailures)/float64(n) >= p.FailureRatio
+}
+
+// Reload replaces the profiles with those of the file at path.
+// See ParseThresholdProfiles for its format.
+func (t *Thresholds) Reload(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	profiles, err := ParseThresholdProfiles(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	t.Store(profiles)
+	return nil
+}
+
+// Watch reloads the profiles from path whenever its modification time
+// changes, checking every interval until ctx is done. Errors are passed to
+// onError, if not nil, and leave the profiles unchanged.
+func (t *Thresholds) Watch(ctx context.Context, path string, interval time.Duration, onError func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var modified time.Time
+	for {
+		info, err := os.Stat(path)
+		if err == nil && !info.ModTime().Equal(modified) {
+			if err = t.Reload(path); err == nil {
+				modified = info.ModTime()
+			}
+		}
+		if err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// thresholdConfig is a ThresholdProfile as a config file holds it.
+type thresholdConfig struct {
+	Name            string         `json:"name"`
+	Daily           *dailyConfig   `json:"daily,omitempty"`
+	Weekly          *weeklyConfig  `json:"weekly,omitempty"`
+	Between         *betweenConfig `json:"between,omitempty"`
+	MinRate         float64        `json:"min_rate,omitempty"`
+	FailureRatio    float64        `json:"failure_ratio"`
+	MinimumRequests uint32         `json:"minimum_requests,omitempty"`
+}
+
+type dailyConfig struct {
+	Start    jsonDuration `json:"start"`
+	Duration jsonDuration `json:"duration"`
+	Location string       `json:"location,omitempty"`
+}
+
+type weeklyConfig struct {
+	Day string `json:"day"`
+	dailyConfig
+}
+
+type betweenConfig struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// ParseThresholdProfiles parses a JSON array of profiles, such as
+//
+//	[
+//	  {"name": "peak", "daily": {"start": "9h", "duration": "8h", "location": "Europe/Berlin"},
+//	   "min_rate": 50, "failure_ratio": 0.2, "minimum_requests": 20},
+//	  {"name": "default", "failure_ratio": 0.5, "minimum_requests": 10}
+//	]
+//
+// A profile has at most one of a "daily", a "weekly" (with a "day" such as
+// "monday") or a "between" (with "start" and "end" times) schedule.
+func ParseThresholdProfiles(data []byte) ([]ThresholdProfile, error) {
+	var configs []thresholdConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, err
+	}
+
+	profiles := make([]ThresholdProfile, 0, len(configs))
+	for _, c := range configs {
+		p := ThresholdProfile{
+			Name:            c.Name,
+			MinRate:         c.MinRate,
+			FailureRatio:    c.FailureRatio,
+			MinimumRequests: c.MinimumRequests,
+		}
+
+		var err error
+		if p.Schedule, err = c.schedule(); err != nil {
+			return nil, fmt.Errorf("profile %q: %w", c.Name, err)
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, nil
+}
+
+func (c thresholdConfig) schedule() (Schedule, error) {
+	switch {
+	case c.Daily != nil && c.Weekly == nil && c.Between == nil:
+		loc, err := location(c.Daily.Location)
+		if err != nil {
+			return nil, err
+		}
+		return Daily{Start: time.Duration(c.Daily.Start), Duration: time.Duration(c.Daily.Duration), Location: loc}, nil
+	case c.Weekly != nil && c.Daily == nil && c.Between == nil:
+		loc, err := location(c.Weekly.Location)
+		if err != nil {
+			return nil, err
+		}
+		day, err := weekday(c.Weekly.Day)
+		if err != nil {
+			return nil, err
+		}
+		return Weekly{Day: day, Start: time.Duration(c.Weekly.Start), Duration: time.Duration(c.Weekly.Duration), Location: loc}, nil
+	case c.Between != nil && c.Daily == nil && c.Weekly == nil:
+		return Between{Start: c.Between.Start, End: c.Between.End}, nil
+	case c.Daily == nil && c.Weekly == nil && c.Between == nil:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("more than one schedule")
+}
+
+func location(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	return time.LoadLocation(name)
+}
+
+func weekday(name string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(d.String(), name) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", name)
+}
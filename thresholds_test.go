@@ -0,0 +1,155 @@
+package soteria_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestThresholdsBySchedule(t *testing.T) {
+	clock := soteriatest.NewClock(monday)
+	th := soteria.NewThresholds(time.Minute, clock,
+		soteria.ThresholdProfile{
+			Name:         "peak",
+			Schedule:     soteria.Daily{Start: 9 * time.Hour, Duration: 8 * time.Hour},
+			FailureRatio: 0.2,
+		},
+		soteria.ThresholdProfile{Name: "default", FailureRatio: 0.5},
+	)
+
+	stats := soteria.Stats{TotalSuccesses: 7, TotalFailures: 3}
+	if p, _ := th.Active(stats); p.Name != "default" || th.ReadyToTrip(stats) {
+		t.Errorf("at midnight: profile %q, ReadyToTrip %v", p.Name, th.ReadyToTrip(stats))
+	}
+
+	clock.Advance(10 * time.Hour)
+	if p, _ := th.Active(stats); p.Name != "peak" || !th.ReadyToTrip(stats) {
+		t.Errorf("at 10:00: profile %q, ReadyToTrip %v", p.Name, th.ReadyToTrip(stats))
+	}
+}
+
+func TestThresholdsByRate(t *testing.T) {
+	th := soteria.NewThresholds(time.Minute, nil,
+		soteria.ThresholdProfile{Name: "busy", MinRate: 1, FailureRatio: 0.1, MinimumRequests: 10},
+	)
+
+	quiet := soteria.Stats{
+		TotalSuccesses: 40, TotalFailures: 20,
+		Windows: []soteria.WindowStats{{Window: time.Minute, Successes: 40, Failures: 19}},
+	}
+	if th.ReadyToTrip(quiet) {
+		t.Error("tripped below the rate of the only profile")
+	}
+
+	busy := quiet
+	busy.Windows = []soteria.WindowStats{{Window: time.Minute, Successes: 40, Failures: 20}}
+	if !th.ReadyToTrip(busy) {
+		t.Error("did not trip at the rate of the profile")
+	}
+}
+
+func TestThresholdsMinimumRequests(t *testing.T) {
+	th := soteria.NewThresholds(0, nil, soteria.ThresholdProfile{FailureRatio: 0.5, MinimumRequests: 10})
+
+	if th.ReadyToTrip(soteria.Stats{TotalFailures: 9}) {
+		t.Error("tripped below MinimumRequests")
+	}
+	if !th.ReadyToTrip(soteria.Stats{TotalFailures: 10}) {
+		t.Error("did not trip at MinimumRequests")
+	}
+}
+
+func TestThresholdsDriveBreaker(t *testing.T) {
+	th := soteria.NewThresholds(time.Minute, nil, soteria.ThresholdProfile{FailureRatio: 0.5, MinimumRequests: 4})
+	cb, _ := newBreaker(t, soteria.Settings{ReadyToTrip: th.ReadyToTrip, Windows: []time.Duration{time.Minute}})
+
+	succeed(cb)
+	fail(cb)
+	succeed(cb)
+	soteriatest.AssertClosed(t, cb)
+
+	fail(cb)
+	soteriatest.AssertOpen(t, cb)
+}
+
+func TestParseThresholdProfiles(t *testing.T) {
+	profiles, err := soteria.ParseThresholdProfiles([]byte(`[
+		{"name": "peak", "daily": {"start": "9h", "duration": "8h", "location": "UTC"},
+		 "min_rate": 50, "failure_ratio": 0.2, "minimum_requests": 20},
+		{"name": "batch", "weekly": {"day": "Sunday", "start": "1h", "duration": "2h"}, "failure_ratio": 0.8},
+		{"name": "default", "failure_ratio": 0.5}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(profiles) != 3 {
+		t.Fatalf("got %d profiles", len(profiles))
+	}
+	peak := profiles[0]
+	if d, ok := peak.Schedule.(soteria.Daily); !ok || d.Start != 9*time.Hour || d.Duration != 8*time.Hour || peak.MinRate != 50 || peak.MinimumRequests != 20 {
+		t.Errorf("peak = %+v", peak)
+	}
+	if w, ok := profiles[1].Schedule.(soteria.Weekly); !ok || w.Day != time.Sunday {
+		t.Errorf("batch = %+v", profiles[1])
+	}
+	if profiles[2].Schedule != nil {
+		t.Errorf("default = %+v", profiles[2])
+	}
+}
+
+func TestParseThresholdProfilesErrors(t *testing.T) {
+	for _, config := range []string{
+		`{}`,
+		`[{"daily": {"start": "9h", "duration": "1h"}, "weekly": {"day": "monday"}}]`,
+		`[{"weekly": {"day": "someday", "start": "1h", "duration": "1h"}}]`,
+		`[{"daily": {"start": "9", "duration": "1h"}}]`,
+		`[{"daily": {"start": "9h", "duration": "1h", "location": "Nowhere/City"}}]`,
+	} {
+		if _, err := soteria.ParseThresholdProfiles([]byte(config)); err == nil {
+			t.Errorf("parsed %s", config)
+		}
+	}
+}
+
+func TestThresholdsReloadAndWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "thresholds.json")
+	if err := os.WriteFile(path, []byte(`[{"name": "one", "failure_ratio": 0.5}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	th := soteria.NewThresholds(time.Minute, nil)
+	if err := th.Reload(path); err != nil {
+		t.Fatal(err)
+	}
+	if p := th.Profiles(); len(p) != 1 || p[0].Name != "one" {
+		t.Fatalf("Profiles = %+v", p)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		th.Watch(ctx, path, time.Millisecond, func(err error) { t.Error(err) })
+	}()
+
+	later := time.Now().Add(time.Hour)
+	os.WriteFile(path, []byte(`[{"name": "two", "failure_ratio": 0.5}]`), 0o644)
+	os.Chtimes(path, later, later)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for th.Profiles()[0].Name != "two" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if p := th.Profiles(); p[0].Name != "two" {
+		t.Errorf("Profiles = %+v after the file changed", p)
+	}
+}
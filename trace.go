@@ -0,0 +1,257 @@
+package soteria
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// Kinds of TraceEvent.
+const (
+	TraceSuccess    = "success"
+	TraceFailure    = "failure"
+	TraceRejected   = "rejected"
+	TraceIgnored    = "ignored"
+	TraceTransition = "transition"
+	TraceAnomaly    = "anomaly"
+	TraceStatsReset = "stats-reset"
+
+	TraceGenerationRolled = "generation-rolled"
+	TraceProbesExhausted  = "probes-exhausted"
+)
+
+// TraceEvent is a single entry of a CircuitBreaker trace. It is the one
+// event type of soteria: subscriptions deliver it, and the Notifier of
+// package telemetry and the AdminHandler send it, in the same JSON
+// encoding; soteriapb.Event is its protobuf encoding.
+//
+// Success, failure and ignored events are stamped when the request completes,
+// rejected events when the request is refused. Transition events carry
+// the states the CircuitBreaker moved between and the Stats of the
+// generation it left, rejected events the rejection error, anomaly events
+// the error describing the anomaly (see Anomalies), stats reset events the
+// Reason given to ResetStats, generation rolled events the new Generation
+// and failure events the failure Category. Probes exhausted events are
+// traced once the outcomes of all the MaxRequests half-open probes are
+// counted, whatever they were, with their Stats and the state they left
+// the CircuitBreaker in as To, half-open if they did not decide. Success, failure and ignored
+// events carry the Latency of the request, encoded in JSON as text, such as
+// "120ms". Labels are those of the CircuitBreaker, shared by all of its
+// events, and Stats is shared by all the receivers of an event; they must
+// not be modified.
+type TraceEvent struct {
+	Breaker  string    `json:"breaker,omitempty"`
+	Time     time.Time `json:"time"`
+	Kind     string    `json:"kind"`
+	From     State     `json:"from"`
+	To       State     `json:"to"`
+	Error    string    `json:"error,omitempty"`
+	Category Category  `json:"category,omitempty"`
+	Reason   string    `json:"reason,omitempty"`
+
+	Latency time.Duration `json:"latency,omitempty"`
+
+	// Generation and Probe describe how the request of an outcome was
+	// admitted, as its CallInfo does.
+	Generation uint64 `json:"generation,omitempty"`
+	Probe      bool   `json:"probe,omitempty"`
+
+	Stats *Stats `json:"stats,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+func (e TraceEvent) MarshalJSON() ([]byte, error) {
+	type plain TraceEvent
+	return json.Marshal(struct {
+		plain
+		Latency jsonDuration `json:"latency,omitempty"`
+	}{plain(e), jsonDuration(e.Latency)})
+}
+
+func (e *TraceEvent) UnmarshalJSON(data []byte) error {
+	type plain TraceEvent
+	v := struct {
+		*plain
+		Latency jsonDuration `json:"latency,omitempty"`
+	}{plain: (*plain)(e)}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	e.Latency = time.Duration(v.Latency)
+	return nil
+}
+
+// trace reports e to the shadow policies and, if sampled, to onTrace and
+// the subscribers. cb.mutex must be held.
+func (cb *CircuitBreaker) trace(e TraceEvent) {
+	e.Breaker = cb.name
+	if len(cb.labels) > 0 {
+		e.Labels = cb.labels
+	}
+	for _, s := range cb.shadows {
+		if d, diverged := s.observe(e); diverged && s.onDivergence != nil {
+			cb.hook("OnDivergence", func() { s.onDivergence(d) })
+		}
+	}
+	if !cb.sampled(e.Kind) {
+		return
+	}
+	if cb.onTrace != nil {
+		cb.hook("OnTrace", func() { cb.onTrace(e) })
+	}
+	cb.publish(e)
+}
+
+// sampled reports whether an event of kind is kept, see Settings.Sampling.
+func (cb *CircuitBreaker) sampled(kind string) bool {
+	rate, ok := cb.sampling[kind]
+	if !ok || kind == TraceTransition {
+		return true
+	}
+	return cb.random.passes(rate)
+}
+
+func copySampling(sampling map[string]float64) map[string]float64 {
+	if sampling == nil {
+		return nil
+	}
+	c := make(map[string]float64, len(sampling))
+	for kind, rate := range sampling {
+		c[kind] = rate
+	}
+	return c
+}
+
+// Recorder writes a trace as one JSON encoded TraceEvent per line.
+// Its Record method is meant to be used as Settings.OnTrace.
+type Recorder struct {
+	mutex sync.Mutex
+	enc   *json.Encoder
+	err   error
+}
+
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+func (r *Recorder) Record(e TraceEvent) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.err == nil {
+		r.err = r.enc.Encode(e)
+	}
+}
+
+// Err returns the first error encountered while writing the trace.
+func (r *Recorder) Err() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.err
+}
+
+// ReplayReport summarizes how a CircuitBreaker would have behaved on a
+// recorded trace.
+type ReplayReport struct {
+	// Requests is the number of requests found in the trace.
+	Requests int
+	// Rejected is the number of requests the replayed CircuitBreaker refused.
+	Rejected int
+	// Trips is the number of times the replayed CircuitBreaker opened.
+	Trips int
+	// Unknown is the number of requests rejected in the recording but
+	// admitted by the replay. Their outcome was never observed; they are
+	// replayed as successes.
+	Unknown int
+	// Transitions lists the state changes of the replayed CircuitBreaker.
+	Transitions []TraceEvent
+}
+
+// Replay feeds the requests of a trace written by a Recorder to a new
+// CircuitBreaker built from settings, on a clock following the recorded
+// timestamps. Failures keep their recorded Category. Settings.Clock,
+// Settings.IsSuccessful, Settings.Classifier and Settings.OnTrace are
+// overridden.
+func Replay(r io.Reader, settings Settings) (*ReplayReport, error) {
+	var (
+		report   ReplayReport
+		clock    replayClock
+		category Category
+		cb       *CircuitBreaker
+	)
+
+	settings.Clock = &clock
+	settings.IsSuccessful = defaultIsSuccessful
+	settings.Classifier = func(error) Category {
+		return category
+	}
+	settings.OnTrace = func(e TraceEvent) {
+		if e.Kind != TraceTransition {
+			return
+		}
+		if e.To == StateOpen {
+			report.Trips++
+		}
+		report.Transitions = append(report.Transitions, e)
+	}
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	for {
+		var e TraceEvent
+		if err := dec.Decode(&e); err == io.EOF {
+			break
+		} else if err != nil {
+			return &report, err
+		}
+
+		var outcome error
+		switch e.Kind {
+		case TraceSuccess:
+		case TraceFailure:
+			outcome = errReplayFailure
+		case TraceRejected:
+		default:
+			continue
+		}
+
+		clock.now = e.Time
+		category = e.Category
+		if cb == nil {
+			cb = New(settings)
+		}
+
+		report.Requests++
+		admitted := false
+		_, err := cb.Execute(func() (interface{}, error) {
+			admitted = true
+			return nil, outcome
+		})
+
+		if !admitted {
+			report.Rejected++
+		} else if e.Kind == TraceRejected {
+			report.Unknown++
+		}
+
+		if err != nil && err != errReplayFailure && admitted {
+			return &report, err
+		}
+	}
+
+	return &report, nil
+}
+
+var errReplayFailure = errors.New("replayed failure")
+
+type replayClock struct {
+	now time.Time
+}
+
+func (c *replayClock) Now() time.Time {
+	return c.now
+}
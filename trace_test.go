@@ -0,0 +1,133 @@
+package soteria_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+// record drives a breaker built from settings through outcomes, one per
+// second, and returns the recorded trace.
+func record(t *testing.T, settings soteria.Settings, outcomes string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	rec := soteria.NewRecorder(&buf)
+	settings.OnTrace = rec.Record
+
+	cb, clock := newBreaker(t, settings)
+	for _, o := range outcomes {
+		if o == 'x' {
+			fail(cb)
+		} else {
+			succeed(cb)
+		}
+		clock.Advance(time.Second)
+	}
+
+	if err := rec.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestRecorderWritesOneEventPerLine(t *testing.T) {
+	trace := record(t, soteria.Settings{Name: "rec"}, "x.")
+
+	lines := strings.Split(strings.TrimSpace(string(trace)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2:\n%s", len(lines), trace)
+	}
+
+	var e soteria.TraceEvent
+	if err := json.Unmarshal([]byte(lines[0]), &e); err != nil {
+		t.Fatal(err)
+	}
+	if e.Kind != soteria.TraceFailure || e.Breaker != "rec" || e.Category != soteria.CategoryOther {
+		t.Errorf("first event = %+v", e)
+	}
+}
+
+func TestReplayReproducesTrips(t *testing.T) {
+	settings := soteria.Settings{Timeout: 5 * time.Second}
+	trace := record(t, settings, "xxxxxx.....xxxxxx")
+
+	report, err := soteria.Replay(bytes.NewReader(trace), settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Requests != 17 {
+		t.Errorf("Requests = %d, want 17", report.Requests)
+	}
+	if report.Trips != 2 {
+		t.Errorf("Trips = %d, want 2", report.Trips)
+	}
+}
+
+func TestReplayWithLooserSettings(t *testing.T) {
+	trace := record(t, soteria.Settings{Timeout: 5 * time.Second}, "xxxxxx.....xxxxxx")
+
+	looser := soteria.Settings{
+		ReadyToTrip: func(s soteria.Stats) bool { return s.ConsecutiveFailures > 10 },
+	}
+	report, err := soteria.Replay(bytes.NewReader(trace), looser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Trips != 0 || report.Rejected != 0 {
+		t.Errorf("report = %+v, want no trips or rejections", report)
+	}
+
+	// the requests rejected while the recording was open were never seen
+	if report.Unknown == 0 {
+		t.Error("Unknown = 0, want the requests rejected in the recording")
+	}
+}
+
+func TestTraceEventLatencyJSON(t *testing.T) {
+	e := soteria.TraceEvent{Kind: soteria.TraceSuccess, Latency: 120 * time.Millisecond}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"latency":"120ms"`) {
+		t.Errorf("json = %s, want the latency as text", data)
+	}
+
+	var got soteria.TraceEvent
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Latency != e.Latency || got.Kind != e.Kind {
+		t.Errorf("round trip = %+v, want %+v", got, e)
+	}
+}
+
+func TestTransitionEventJSON(t *testing.T) {
+	var transition soteria.TraceEvent
+	cb, _ := newBreaker(t, soteria.Settings{
+		Name: "db",
+		OnTrace: func(e soteria.TraceEvent) {
+			if e.Kind == soteria.TraceTransition {
+				transition = e
+			}
+		},
+	})
+	trip(cb)
+
+	got, err := json.Marshal(transition)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"breaker":"db","time":"2024-01-01T00:00:00Z","kind":"transition","from":"closed","to":"open",` +
+		`"stats":{"requests":6,"total_successes":0,"total_failures":6,"consecutive_successes":0,"consecutive_failures":6,` +
+		`"weighted_requests":6,"weighted_failures":6,"generation":1,"failures_by_category":{"other":6}}}`
+	if string(got) != want {
+		t.Errorf("transition = %s\nwant %s", got, want)
+	}
+}
@@ -0,0 +1,94 @@
+package soteria
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TripRate describes how often a CircuitBreaker trips, for spotting noisy
+// breakers whose thresholds need retuning. Trips are the transitions into
+// the open state caused by failures; ForceOpen and maintenance windows do
+// not count. Durations are encoded in JSON as text, such as "1m30s".
+type TripRate struct {
+	// Total is the number of trips since New.
+	Total uint64 `json:"total"`
+	// LastHour is the number of trips within the last hour.
+	LastHour int `json:"last_hour"`
+	// LastTrip is the time of the most recent trip, nil if there was none.
+	LastTrip *time.Time `json:"last_trip,omitempty"`
+	// SinceLastTrip is the time elapsed since LastTrip, 0 if there was none.
+	SinceLastTrip time.Duration `json:"since_last_trip,omitempty"`
+}
+
+func (r TripRate) MarshalJSON() ([]byte, error) {
+	type plain TripRate
+	return json.Marshal(struct {
+		plain
+		SinceLastTrip jsonDuration `json:"since_last_trip,omitempty"`
+	}{plain(r), jsonDuration(r.SinceLastTrip)})
+}
+
+func (r *TripRate) UnmarshalJSON(data []byte) error {
+	type plain TripRate
+	v := struct {
+		*plain
+		SinceLastTrip jsonDuration `json:"since_last_trip,omitempty"`
+	}{plain: (*plain)(r)}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	r.SinceLastTrip = time.Duration(v.SinceLastTrip)
+	return nil
+}
+
+const tripRateWindow = time.Hour
+
+// TripRate returns the TripRate of cb.
+func (cb *CircuitBreaker) TripRate() TripRate {
+	if cb.unprotected() {
+		return TripRate{}
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.clock.Now()
+	cb.pruneTrips(now)
+
+	rate := TripRate{Total: cb.totalTrips, LastHour: len(cb.trips)}
+	if !cb.lastTrip.IsZero() {
+		lastTrip := cb.lastTrip
+		rate.LastTrip = &lastTrip
+		rate.SinceLastTrip = now.Sub(cb.lastTrip)
+	}
+	return rate
+}
+
+// tripped records a trip at now. cb.mutex must be held.
+func (cb *CircuitBreaker) tripped(now time.Time) {
+	cb.totalTrips++
+	cb.lastTrip = now
+	cb.trips = append(cb.trips, now)
+	cb.pruneTrips(now)
+}
+
+// pruneTrips drops the trips that fell out of the window. cb.mutex must be held.
+func (cb *CircuitBreaker) pruneTrips(now time.Time) {
+	i := 0
+	for i < len(cb.trips) && now.Sub(cb.trips[i]) >= tripRateWindow {
+		i++
+	}
+	cb.trips = append(cb.trips[:0], cb.trips[i:]...)
+}
+
+// Noisy returns the names of the breakers of r that tripped at least
+// perHour times within the last hour.
+func (r *Registry) Noisy(perHour int) []string {
+	var names []string
+	for _, cb := range r.Breakers() {
+		if cb.TripRate().LastHour >= perHour {
+			names = append(names, cb.Name())
+		}
+	}
+	return names
+}
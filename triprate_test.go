@@ -0,0 +1,85 @@
+package soteria_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestTripRate(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{Timeout: time.Minute})
+	if rate := cb.TripRate(); rate.Total != 0 || rate.LastTrip != nil || rate.SinceLastTrip != 0 {
+		t.Errorf("TripRate = %+v before any trip", rate)
+	}
+
+	trip(cb)
+	tripped := clock.Now()
+	clock.Advance(30 * time.Minute)
+	cb.Reset()
+	trip(cb)
+	clock.Advance(45 * time.Minute)
+
+	rate := cb.TripRate()
+	if rate.Total != 2 || rate.LastHour != 1 {
+		t.Errorf("TripRate = %+v, want 2 trips, 1 within the last hour", rate)
+	}
+	if rate.LastTrip == nil || !rate.LastTrip.Equal(tripped.Add(30*time.Minute)) || rate.SinceLastTrip != 45*time.Minute {
+		t.Errorf("LastTrip = %v, SinceLastTrip = %v", rate.LastTrip, rate.SinceLastTrip)
+	}
+}
+
+func TestTripRateIgnoresIsolation(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	cb.ForceOpen()
+
+	if rate := cb.TripRate(); rate.Total != 0 {
+		t.Errorf("Total = %d, want isolation not to count as a trip", rate.Total)
+	}
+}
+
+func TestTripRateJSON(t *testing.T) {
+	b, err := json.Marshal(soteria.TripRate{Total: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"total":3,"last_hour":0}` {
+		t.Errorf("Marshal without trips = %s", b)
+	}
+
+	last := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rate := soteria.TripRate{Total: 3, LastHour: 1, LastTrip: &last, SinceLastTrip: 90 * time.Second}
+	if b, err = json.Marshal(rate); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"since_last_trip":"1m30s"`) {
+		t.Errorf("Marshal = %s, want the duration as text", b)
+	}
+
+	var got soteria.TripRate
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, rate) {
+		t.Errorf("round trip = %+v, want %+v", got, rate)
+	}
+}
+
+func TestRegistryNoisy(t *testing.T) {
+	r := soteria.NewRegistry()
+	quiet := r.GetOrCreate("quiet", soteria.Settings{})
+	noisy := r.GetOrCreate("noisy", soteria.Settings{})
+
+	trip(quiet)
+	for i := 0; i < 3; i++ {
+		trip(noisy)
+		noisy.Reset()
+	}
+
+	if got := r.Noisy(3); !reflect.DeepEqual(got, []string{"noisy"}) {
+		t.Errorf("Noisy = %v", got)
+	}
+}
@@ -0,0 +1,46 @@
+package soteria
+
+import "context"
+
+// WaitUntilClosed blocks until the CircuitBreaker is closed, or ctx is
+// done, in which case it returns ctx.Err(), so that background workers can
+// pause during an outage instead of spinning on ErrOpenState. It returns
+// at once if the CircuitBreaker is closed already.
+//
+// An open CircuitBreaker only closes once half-open probes succeed, so
+// waiting is only over if other callers keep sending requests, or an
+// operator closes it.
+func (cb *CircuitBreaker) WaitUntilClosed(ctx context.Context) error {
+	if cb.unprotected() {
+		return nil
+	}
+
+	for {
+		cb.mutex.Lock()
+		cb.currentState(cb.clock.Now())
+		if cb.state() == StateClosed {
+			cb.mutex.Unlock()
+			return nil
+		}
+		if cb.changed == nil {
+			cb.changed = make(chan struct{})
+		}
+		changed := cb.changed
+		cb.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// notifyWaiters wakes up the callers of WaitUntilClosed after a change of
+// state. cb.mutex must be held.
+func (cb *CircuitBreaker) notifyWaiters() {
+	if cb.changed != nil {
+		close(cb.changed)
+		cb.changed = nil
+	}
+}
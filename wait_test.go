@@ -0,0 +1,49 @@
+package soteria_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestWaitUntilClosed(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{})
+	if err := cb.WaitUntilClosed(context.Background()); err != nil {
+		t.Fatalf("WaitUntilClosed on a closed breaker = %v", err)
+	}
+
+	trip(cb)
+	done := make(chan error, 1)
+	go func() { done <- cb.WaitUntilClosed(context.Background()) }()
+
+	clock.Advance(cb.Timeout())
+	cb.State()
+	select {
+	case err := <-done:
+		t.Fatalf("WaitUntilClosed returned %v while half-open", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	succeed(cb)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("WaitUntilClosed = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WaitUntilClosed still blocked once closed")
+	}
+}
+
+func TestWaitUntilClosedContext(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+	trip(cb)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := cb.WaitUntilClosed(ctx); err != context.DeadlineExceeded {
+		t.Errorf("WaitUntilClosed = %v, want the deadline of ctx", err)
+	}
+}
@@ -0,0 +1,159 @@
+package soteria
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WindowStats counts the outcomes a CircuitBreaker saw within one of the
+// rolling windows of Settings.Windows. Window is encoded in JSON as text,
+// such as "1m0s".
+type WindowStats struct {
+	Window    time.Duration `json:"window"`
+	Successes uint32        `json:"successes"`
+	Failures  uint32        `json:"failures"`
+}
+
+func (w WindowStats) MarshalJSON() ([]byte, error) {
+	type plain WindowStats
+	return json.Marshal(struct {
+		plain
+		Window jsonDuration `json:"window"`
+	}{plain(w), jsonDuration(w.Window)})
+}
+
+func (w *WindowStats) UnmarshalJSON(data []byte) error {
+	type plain WindowStats
+	v := struct {
+		*plain
+		Window jsonDuration `json:"window"`
+	}{plain: (*plain)(w)}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	w.Window = time.Duration(v.Window)
+	return nil
+}
+
+// Requests returns the number of outcomes counted by w.
+func (w WindowStats) Requests() uint32 {
+	return w.Successes + w.Failures
+}
+
+// FailureRatio returns the share of failures counted by w, 0 if it counted
+// nothing.
+func (w WindowStats) FailureRatio() float64 {
+	if w.Requests() == 0 {
+		return 0
+	}
+	return float64(w.Failures) / float64(w.Requests())
+}
+
+// Window returns the WindowStats of the rolling window of size d, if
+// Settings.Windows has one.
+func (c Stats) Window(d time.Duration) (WindowStats, bool) {
+	for _, w := range c.Windows {
+		if w.Window == d {
+			return w, true
+		}
+	}
+	return WindowStats{}, false
+}
+
+// windowBuckets is the number of buckets a rolling window slides by.
+const windowBuckets = 10
+
+type bucket struct {
+	used      bool
+	slot      int64 // start in widths since the Unix epoch
+	start     time.Time
+	successes uint32
+	failures  uint32
+}
+
+// window counts outcomes over the last size, in buckets of width.
+type window struct {
+	size    time.Duration
+	width   time.Duration
+	buckets [windowBuckets]bucket
+}
+
+func newWindows(sizes []time.Duration) []*window {
+	windows := make([]*window, 0, len(sizes))
+	for _, size := range sizes {
+		width := size / windowBuckets
+		if width <= 0 {
+			width = 1
+		}
+		windows = append(windows, &window{size: size, width: width})
+	}
+	return windows
+}
+
+func (w *window) add(now time.Time, failed bool) {
+	start := truncate(now, w.width)
+	slot := start.UnixNano() / int64(w.width)
+	i := int(slot % windowBuckets)
+	if i < 0 {
+		i += windowBuckets
+	}
+
+	// buckets are told apart by slot: the monotonic readings of starts
+	// truncated from different times of a slot may differ by a few ns
+	b := &w.buckets[i]
+	if !b.used || b.slot != slot {
+		*b = bucket{used: true, slot: slot, start: start}
+	}
+
+	if failed {
+		b.failures++
+	} else {
+		b.successes++
+	}
+}
+
+func (w *window) stats(now time.Time) WindowStats {
+	s := WindowStats{Window: w.size}
+	for _, b := range w.buckets {
+		if b.used && !b.start.After(now) && now.Sub(b.start) < w.size {
+			s.Successes += b.successes
+			s.Failures += b.failures
+		}
+	}
+	return s
+}
+
+func (w *window) reset() {
+	w.buckets = [windowBuckets]bucket{}
+}
+
+func sameWindows(windows []*window, sizes []time.Duration) bool {
+	if len(windows) != len(sizes) {
+		return false
+	}
+	for i, w := range windows {
+		if w.size != sizes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// record counts an outcome in every rolling window. cb.mutex must be held.
+func (cb *CircuitBreaker) record(now time.Time, failed bool) {
+	for _, w := range cb.windows {
+		w.add(now, failed)
+	}
+}
+
+// snapshot returns a copy of the stats of cb along with its rolling
+// windows as of now. cb.mutex must be held.
+func (cb *CircuitBreaker) snapshot(now time.Time) Stats {
+	s := cb.stats.snapshot()
+	s.Generation = cb.generation
+	for _, w := range cb.windows {
+		s.Windows = append(s.Windows, w.stats(now))
+	}
+	return s
+}
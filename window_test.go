@@ -0,0 +1,112 @@
+package soteria_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jtejido/soteria"
+)
+
+func TestRollingWindows(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{
+		Interval: 10 * time.Second,
+		Windows:  []time.Duration{time.Minute, 5 * time.Minute},
+	})
+
+	fail(cb)
+	clock.Advance(2 * time.Minute)
+	succeed(cb)
+	fail(cb)
+
+	st := cb.Stats()
+	if len(st.Windows) != 2 {
+		t.Fatalf("Windows = %+v", st.Windows)
+	}
+	short, _ := st.Window(time.Minute)
+	long, _ := st.Window(5 * time.Minute)
+	if short.Successes != 1 || short.Failures != 1 {
+		t.Errorf("1m window = %+v, want the first failure slid out", short)
+	}
+	if long.Successes != 1 || long.Failures != 2 {
+		t.Errorf("5m window = %+v, want every outcome", long)
+	}
+	if st.TotalFailures != 1 {
+		t.Errorf("TotalFailures = %d, want the interval counts cleared independently", st.TotalFailures)
+	}
+
+	if _, ok := st.Window(time.Hour); ok {
+		t.Error("found a window that was not configured")
+	}
+}
+
+func TestRollingWindowsFeedReadyToTrip(t *testing.T) {
+	cb, clock := newBreaker(t, soteria.Settings{
+		Interval: time.Second,
+		Windows:  []time.Duration{time.Minute},
+		ReadyToTrip: func(stats soteria.Stats) bool {
+			w, _ := stats.Window(time.Minute)
+			return w.Requests() >= 4 && w.FailureRatio() >= 0.5
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		fail(cb)
+		clock.Advance(time.Second)
+		succeed(cb)
+		clock.Advance(time.Second)
+	}
+
+	if cb.State() != soteria.StateOpen {
+		t.Errorf("State = %v, want a trip on the 1m window", cb.State())
+	}
+}
+
+func TestRollingWindowsClearOnStateChange(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{Windows: []time.Duration{time.Minute}})
+	trip(cb)
+
+	if w, _ := cb.Stats().Window(time.Minute); w.Requests() != 0 {
+		t.Errorf("window = %+v after the trip, want it cleared", w)
+	}
+}
+
+func TestWindowStatsRatio(t *testing.T) {
+	if r := (soteria.WindowStats{}).FailureRatio(); r != 0 {
+		t.Errorf("FailureRatio of an empty window = %v", r)
+	}
+	if r := (soteria.WindowStats{Successes: 3, Failures: 1}).FailureRatio(); r != 0.25 {
+		t.Errorf("FailureRatio = %v, want 0.25", r)
+	}
+}
+
+func TestWindowStatsJSON(t *testing.T) {
+	w := soteria.WindowStats{Window: 5 * time.Minute, Successes: 2, Failures: 1}
+
+	b, err := json.Marshal(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"successes":2,"failures":1,"window":"5m0s"}` {
+		t.Errorf("Marshal = %s", b)
+	}
+
+	var got soteria.WindowStats
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, w) {
+		t.Errorf("round trip = %+v, want %+v", got, w)
+	}
+}
+
+func TestRollingWindowsOnSystemClock(t *testing.T) {
+	cb := soteria.New(soteria.Settings{Windows: []time.Duration{time.Hour}, ReadyToTrip: func(soteria.Stats) bool { return false }})
+	for i := 0; i < 100; i++ {
+		fail(cb)
+	}
+	if w, _ := cb.Stats().Window(time.Hour); w.Failures != 100 {
+		t.Errorf("window = %+v, want every failure counted", w)
+	}
+}
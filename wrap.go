@@ -0,0 +1,19 @@
+package soteria
+
+import "context"
+
+// Wrap returns fn decorated with cb: every call of the returned function
+// goes through cb.ExecuteContext. Commonly called client methods can so be
+// wrapped once, when the client is built, rather than at every call site.
+// A rejected call returns the zero value of T and the rejection error.
+func Wrap[T any](cb *CircuitBreaker, fn func(ctx context.Context) (T, error)) func(ctx context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		var result T
+		_, err := cb.ExecuteContext(ctx, func(ctx context.Context) (interface{}, error) {
+			var err error
+			result, err = fn(ctx)
+			return nil, err
+		})
+		return result, err
+	}
+}
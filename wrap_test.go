@@ -0,0 +1,41 @@
+package soteria_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jtejido/soteria"
+	"github.com/jtejido/soteria/soteriatest"
+)
+
+func TestWrap(t *testing.T) {
+	cb, _ := newBreaker(t, soteria.Settings{})
+
+	calls := 0
+	get := soteria.Wrap(cb, func(ctx context.Context) (int, error) {
+		calls++
+		if _, ok := soteria.InfoFromContext(ctx); !ok {
+			t.Error("wrapped function called without BreakerInfo")
+		}
+		if calls > 1 {
+			return calls, errFail
+		}
+		return calls, nil
+	})
+
+	if n, err := get(context.Background()); n != 1 || err != nil {
+		t.Errorf("get = %d, %v, want 1, nil", n, err)
+	}
+	if n, err := get(context.Background()); n != 2 || err != errFail {
+		t.Errorf("get = %d, %v, want a result along with the error", n, err)
+	}
+
+	soteriatest.Trip(t, cb, 10)
+	if n, err := get(context.Background()); n != 0 || !errors.Is(err, soteria.ErrOpenState) {
+		t.Errorf("get while open = %d, %v, want 0, ErrOpenState", n, err)
+	}
+	if s := cb.Stats(); s.Requests != 0 {
+		t.Errorf("rejected call counted: %+v", s)
+	}
+}